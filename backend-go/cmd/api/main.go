@@ -11,6 +11,7 @@ import (
 	"backend-go/internal/config"
 	"backend-go/internal/handlers"
 	"backend-go/internal/middleware"
+	"backend-go/internal/models"
 	"backend-go/internal/repository"
 	"backend-go/internal/services"
 	"backend-go/pkg/mongodb"
@@ -59,6 +60,29 @@ func main() {
 	}
 	log.Println("✓ Connected to MongoDB with MGM")
 
+	// Trips saved before geo queries need a GeoJSON location before the 2dsphere index is built
+	if _, err := repository.NewTripRepository().BackfillLocations(context.Background()); err != nil {
+		log.Printf("⚠️  Failed to backfill trip locations: %v", err)
+	}
+
+	if err := repository.EnsureIndexes(context.Background()); err != nil {
+		log.Printf("⚠️  Failed to ensure MongoDB indexes: %v", err)
+	} else {
+		log.Println("✓ Ensured MongoDB indexes")
+	}
+
+	// Denormalize entry titles on older trips for full-text search, the backfill publishes no trip events
+	go func() {
+		updated, err := services.NewTripService(nil).BackfillEntryTitles(context.Background())
+		if err != nil {
+			log.Printf("⚠️  Failed to backfill trip entry titles: %v", err)
+			return
+		}
+		if updated > 0 {
+			log.Printf("✓ Backfilled entry titles on %d trips", updated)
+		}
+	}()
+
 	// Connect to Redis
 	var redisClient *redis.Client
 	if cfg.Redis.URL != "" {
@@ -85,6 +109,9 @@ func main() {
 	sseHub := sse.NewHub()
 	log.Println("✓ Initialized SSE Hub for real-time notifications")
 
+	// Trip-scoped hub for collaborative editing, services publish itinerary, expense and member changes to it
+	tripHub := sse.NewTripHub()
+
 	// Create Gin router
 	router := gin.Default()
 
@@ -100,8 +127,8 @@ func main() {
 	// Initialize handlers
 	healthHandler := handlers.NewHealthHandler()
 	userHandler := handlers.NewUserHandler()
-	expenseHandler := handlers.NewExpenseHandler()
-	itineraryHandler := handlers.NewItineraryHandler()
+	expenseHandler := handlers.NewExpenseHandler(tripHub)
+	itineraryHandler := handlers.NewItineraryHandler(tripHub, &cfg.Travel, &cfg.Schedule)
 	fileHandler, err := handlers.NewFileHandler(&cfg.R2)
 	if err != nil {
 		log.Fatalf("Failed to create file handler: %v", err)
@@ -128,11 +155,19 @@ func main() {
 	userRepo := repository.NewUserRepository()
 	notificationService := services.NewNotificationService(notificationRepo, userRepo, sseHub)
 	notificationHandler := handlers.NewNotificationHandler(notificationService)
-	sseHandler := handlers.NewSSEHandler(sseHub)
+	sseHandler := handlers.NewSSEHandler(sseHub, tripHub)
 
 	placeHandler := handlers.NewPlaceHandler(&cfg.Google, cityService, redisService)
 	commentHandler := handlers.NewCommentHandler(notificationService)
-	tripHandler := handlers.NewTripHandler(notificationService)
+	tripHandler := handlers.NewTripHandler(notificationService, tripHub, &cfg.Travel, &cfg.Schedule)
+	tripInvitationHandler := handlers.NewTripInvitationHandler(notificationService, tripHub)
+	tripExportHandler := handlers.NewTripExportHandler(&cfg.Booklet)
+	tripImportHandler := handlers.NewTripImportHandler(tripHub, &cfg.Travel, &cfg.Schedule)
+	tripInteractionHandler := handlers.NewTripInteractionHandler()
+	tripLifecycleHandler := handlers.NewTripLifecycleHandler(&cfg.Lifecycle)
+	tripTrashHandler := handlers.NewTripTrashHandler(&cfg.Lifecycle)
+	tripStatsHandler := handlers.NewTripStatsHandler()
+	tripDocumentHandler := handlers.NewTripDocumentHandler(tripHub)
 
 	// Initialize check-in service and handler
 	checkInRepo := repository.NewCheckInRepository()
@@ -162,6 +197,8 @@ func main() {
 	fileHandler.RegisterRoutes(v1, cfg.Clerk.SecretKey, cfg.Clerk.JWTIssuerDomain)
 	placeHandler.RegisterRoutes(v1, cfg.Clerk.SecretKey, cfg.Clerk.JWTIssuerDomain, commentHandler)
 	commentHandler.RegisterCommentRoutes(v1, cfg.Clerk.SecretKey, cfg.Clerk.JWTIssuerDomain)
+	tripInvitationHandler.RegisterInvitationRoutes(v1, cfg.Clerk.SecretKey, cfg.Clerk.JWTIssuerDomain)
+	tripExportHandler.RegisterCalendarRoutes(v1)
 	unsplashHandler.RegisterRoutes(v1)
 
 	// Notification routes
@@ -180,6 +217,7 @@ func main() {
 	sseRoutes.Use(middleware.SSEAuth(cfg.Clerk.SecretKey, cfg.Clerk.JWTIssuerDomain))
 	{
 		sseRoutes.GET("/notifications", sseHandler.StreamNotifications)
+		sseRoutes.GET("/trips/:id", middleware.LoadTrip(), middleware.RequireTripRole(models.MemberRoleOwner, models.MemberRoleEditor, models.MemberRoleViewer), sseHandler.StreamTrip)
 	}
 
 	// Check-in routes
@@ -198,7 +236,12 @@ func main() {
 
 	// Trip routes - both /trips and /trips/:id
 	trips := v1.Group("/trips")
-	trips.GET("", tripHandler.ListTrips)
+	trips.GET("", middleware.OptionalAuth(cfg.Clerk.SecretKey, cfg.Clerk.JWTIssuerDomain), tripHandler.ListTrips)
+	trips.GET("/search", middleware.OptionalAuth(cfg.Clerk.SecretKey, cfg.Clerk.JWTIssuerDomain), tripHandler.SearchTrips)
+	trips.GET("/nearby", middleware.OptionalAuth(cfg.Clerk.SecretKey, cfg.Clerk.JWTIssuerDomain), tripHandler.FindNearbyTrips)
+	trips.GET("/within", middleware.OptionalAuth(cfg.Clerk.SecretKey, cfg.Clerk.JWTIssuerDomain), tripHandler.FindTripsWithinBox)
+	trips.GET("/bookmarked", middleware.Auth(cfg.Clerk.SecretKey, cfg.Clerk.JWTIssuerDomain), tripInteractionHandler.ListBookmarkedTrips)
+	trips.GET("/trash", middleware.Auth(cfg.Clerk.SecretKey, cfg.Clerk.JWTIssuerDomain), tripTrashHandler.ListTrash)
 
 	authenticated := trips.Group("")
 	authenticated.Use(middleware.Auth(cfg.Clerk.SecretKey, cfg.Clerk.JWTIssuerDomain))
@@ -211,6 +254,13 @@ func main() {
 	tripHandler.RegisterRoutes(tripDetail, cfg.Clerk.SecretKey, cfg.Clerk.JWTIssuerDomain, commentHandler, expenseHandler, itineraryHandler)
 	expenseHandler.RegisterRoutes(tripDetail, cfg.Clerk.SecretKey, cfg.Clerk.JWTIssuerDomain)
 	itineraryHandler.RegisterRoutes(tripDetail, cfg.Clerk.SecretKey, cfg.Clerk.JWTIssuerDomain)
+	tripExportHandler.RegisterTripExportRoutes(tripDetail, cfg.Clerk.SecretKey, cfg.Clerk.JWTIssuerDomain)
+	tripImportHandler.RegisterRoutes(tripDetail, cfg.Clerk.SecretKey, cfg.Clerk.JWTIssuerDomain)
+	tripInteractionHandler.RegisterRoutes(tripDetail, cfg.Clerk.SecretKey, cfg.Clerk.JWTIssuerDomain)
+	tripLifecycleHandler.RegisterRoutes(tripDetail, cfg.Clerk.SecretKey, cfg.Clerk.JWTIssuerDomain)
+	tripTrashHandler.RegisterRoutes(tripDetail, cfg.Clerk.SecretKey, cfg.Clerk.JWTIssuerDomain)
+	tripStatsHandler.RegisterRoutes(tripDetail, cfg.Clerk.SecretKey, cfg.Clerk.JWTIssuerDomain)
+	tripDocumentHandler.RegisterRoutes(tripDetail, cfg.Clerk.SecretKey, cfg.Clerk.JWTIssuerDomain)
 
 	// Admin routes (requires authentication + admin role)
 	admin := v1.Group("/admin")
@@ -243,6 +293,7 @@ func main() {
 
 		// System health
 		admin.GET("/system/health", adminSystemHandler.GetSystemHealth)
+		admin.POST("/system/reconcile-counters", adminSystemHandler.ReconcileCounters)
 	}
 
 	// Start server
@@ -253,6 +304,11 @@ func main() {
 	log.Printf("Metrics: %v", cfg.OTEL.MetricsEnabled)
 	log.Printf("Logging: level=%s, format=%s", cfg.OTEL.LogLevel, cfg.OTEL.LogFormat)
 
+	// Publish scheduled drafts and auto-archive ended trips in the background
+	sweeperCtx, stopSweeper := context.WithCancel(context.Background())
+	defer stopSweeper()
+	go services.NewTripLifecycleService(&cfg.Lifecycle).RunSweeper(sweeperCtx)
+
 	// Start server in goroutine
 	go func() {
 		if err := router.Run(addr); err != nil {
@@ -266,6 +322,7 @@ func main() {
 	<-quit
 
 	log.Println("Shutting down server...")
+	stopSweeper()
 
 	// Cleanup
 	// Note: MGM handles MongoDB connection internally, no explicit disconnect needed
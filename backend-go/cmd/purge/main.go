@@ -0,0 +1,41 @@
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"backend-go/internal/config"
+	"backend-go/internal/services"
+	"backend-go/pkg/mongodb"
+)
+
+// Purge job to hard delete trips that have been in the trash longer than TRIP_TRASH_RETENTION_DAYS
+// Itineraries, entries, expenses, comments and interactions go with them.
+func main() {
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	// Initialize MGM (Mongo Go Models)
+	err = mongodb.InitMGM(
+		cfg.MongoDB.URI,
+		cfg.MongoDB.Database,
+	)
+	if err != nil {
+		log.Fatalf("Failed to initialize MGM: %v", err)
+	}
+	log.Println("✓ Connected to MongoDB")
+
+	// Run purge
+	ctx := context.Background()
+	trashService := services.NewTripTrashService(&cfg.Lifecycle)
+	purged, err := trashService.PurgeExpired(ctx, time.Now())
+	if err != nil {
+		log.Fatalf("Failed to purge trash (%d trips purged before the error): %v", purged, err)
+	}
+
+	log.Printf("✓ Purge completed: %d trips deleted (retention %d days)", purged, cfg.Lifecycle.TrashRetentionDays)
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+
+	"backend-go/internal/config"
+	"backend-go/internal/services"
+	"backend-go/pkg/mongodb"
+)
+
+// Reconcile job to recompute cached engagement counters on trips and comments
+// Usage: go run ./cmd/reconcile [-dry-run] [-batch-size 500]
+func main() {
+	dryRun := flag.Bool("dry-run", false, "report differences without writing")
+	batchSize := flag.Int("batch-size", 500, "documents read per batch")
+	flag.Parse()
+
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	// Initialize MGM (Mongo Go Models)
+	err = mongodb.InitMGM(
+		cfg.MongoDB.URI,
+		cfg.MongoDB.Database,
+	)
+	if err != nil {
+		log.Fatalf("Failed to initialize MGM: %v", err)
+	}
+	log.Println("✓ Connected to MongoDB")
+
+	// Run reconciliation
+	ctx := context.Background()
+	reconcileService := services.NewCounterReconcileService()
+	report, err := reconcileService.Reconcile(ctx, services.ReconcileOptions{
+		DryRun:    *dryRun,
+		BatchSize: *batchSize,
+	})
+	if err != nil {
+		log.Fatalf("Failed to reconcile counters: %v", err)
+	}
+
+	for _, diff := range report.Differences {
+		log.Printf("  %s/%s %s: cached %d, actual %d", diff.Collection, diff.ID, diff.Field, diff.Cached, diff.Actual)
+	}
+	if report.DifferencesTruncated {
+		log.Println("  ... more differences not listed")
+	}
+
+	if *dryRun {
+		log.Printf("✓ Dry run completed: %d/%d trips and %d/%d comments have drifted counters",
+			report.TripsMismatched, report.TripsScanned, report.CommentsMismatched, report.CommentsScanned)
+		return
+	}
+	log.Printf("✓ Reconcile completed: %d/%d trips and %d/%d comments fixed, %d documents updated",
+		report.TripsMismatched, report.TripsScanned, report.CommentsMismatched, report.CommentsScanned, report.Updated)
+}
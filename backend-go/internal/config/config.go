@@ -18,6 +18,10 @@ type Config struct {
 	Unsplash UnsplashConfig
 	OTEL     OTELConfig
 	CORS     CORSConfig
+	Booklet  BookletConfig
+	Lifecycle LifecycleConfig
+	Travel    TravelConfig
+	Schedule  ScheduleConfig
 }
 
 type ServerConfig struct {
@@ -97,6 +101,33 @@ type CORSConfig struct {
 	AllowedHeaders []string
 }
 
+type BookletConfig struct {
+	FontPath          string // UTF-8 TrueType font, required for non-Latin text (e.g. Thai)
+	BoldFontPath      string // Optional, falls back to FontPath
+	CoverTimeout      int    // Seconds to wait for the cover photo download
+	CoverMaxSizeBytes int64
+	CoverAllowedHosts []string // Cover photos are only downloaded from these hosts (our uploads and Unsplash)
+}
+
+type LifecycleConfig struct {
+	SweepIntervalMinutes int // How often scheduled publishes and auto-archiving run, 0 disables the sweeper
+	AutoArchiveAfterDays int // Default days after a trip ends before it's archived, owners can override, 0 = never
+	TrashRetentionDays   int // Days a deleted trip stays restorable before the purge job removes it for good
+}
+
+type TravelConfig struct {
+	WalkSpeedKmh           float64 // Average speeds used to estimate travel time between entries
+	TransitSpeedKmh        float64
+	DriveSpeedKmh          float64
+	DetourFactor           float64 // Straight-line distance times this approximates the distance along streets
+	TransitOverheadMinutes int     // Waiting and transfers added to every transit leg
+	DriveOverheadMinutes   int     // Parking added to every drive leg
+}
+
+type ScheduleConfig struct {
+	MaxDayHours float64 // Scheduled time per day above this is flagged by itinerary validation
+}
+
 // Load loads configuration from environment variables
 func Load() (*Config, error) {
 	// Load .env file if exists
@@ -163,7 +194,34 @@ func Load() (*Config, error) {
 		CORS: CORSConfig{
 			AllowedOrigins: getEnvAsSlice("CORS_ALLOWED_ORIGINS", []string{"*"}),
 			AllowedMethods: getEnvAsSlice("CORS_ALLOWED_METHODS", []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}),
-			AllowedHeaders: getEnvAsSlice("CORS_ALLOWED_HEADERS", []string{"Content-Type", "Authorization"}),
+			AllowedHeaders: getEnvAsSlice("CORS_ALLOWED_HEADERS", []string{"Content-Type", "Authorization", "If-Match"}),
+		},
+		Booklet: BookletConfig{
+			FontPath:          getEnv("BOOKLET_FONT_PATH", ""),
+			BoldFontPath:      getEnv("BOOKLET_BOLD_FONT_PATH", ""),
+			CoverTimeout:      getEnvAsInt("BOOKLET_COVER_TIMEOUT", 10),
+			CoverMaxSizeBytes: int64(getEnvAsInt("BOOKLET_COVER_MAX_SIZE_MB", 10)) * 1024 * 1024,
+			CoverAllowedHosts: getEnvAsSlice("BOOKLET_COVER_ALLOWED_HOSTS", []string{
+				getEnv("R2_DEV_SUBDOMAIN", ""),
+				"images.unsplash.com",
+				"plus.unsplash.com",
+			}),
+		},
+		Lifecycle: LifecycleConfig{
+			SweepIntervalMinutes: getEnvAsInt("TRIP_SWEEP_INTERVAL_MINUTES", 15),
+			AutoArchiveAfterDays: getEnvAsInt("TRIP_AUTO_ARCHIVE_AFTER_DAYS", 30),
+			TrashRetentionDays:   getEnvAsInt("TRIP_TRASH_RETENTION_DAYS", 30),
+		},
+		Travel: TravelConfig{
+			WalkSpeedKmh:           getEnvAsFloat("TRAVEL_WALK_SPEED_KMH", 4.5),
+			TransitSpeedKmh:        getEnvAsFloat("TRAVEL_TRANSIT_SPEED_KMH", 20),
+			DriveSpeedKmh:          getEnvAsFloat("TRAVEL_DRIVE_SPEED_KMH", 30),
+			DetourFactor:           getEnvAsFloat("TRAVEL_DETOUR_FACTOR", 1.3),
+			TransitOverheadMinutes: getEnvAsInt("TRAVEL_TRANSIT_OVERHEAD_MINUTES", 10),
+			DriveOverheadMinutes:   getEnvAsInt("TRAVEL_DRIVE_OVERHEAD_MINUTES", 5),
+		},
+		Schedule: ScheduleConfig{
+			MaxDayHours: getEnvAsFloat("SCHEDULE_MAX_DAY_HOURS", 12),
 		},
 	}
 
@@ -185,6 +243,14 @@ func getEnvAsInt(key string, defaultValue int) int {
 	return defaultValue
 }
 
+func getEnvAsFloat(key string, defaultValue float64) float64 {
+	valueStr := getEnv(key, "")
+	if value, err := strconv.ParseFloat(valueStr, 64); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
 func getEnvAsBool(key string, defaultValue bool) bool {
 	valueStr := getEnv(key, "")
 	if value, err := strconv.ParseBool(valueStr); err == nil {
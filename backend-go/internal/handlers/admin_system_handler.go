@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"net/http"
+	"strconv"
 
 	"backend-go/internal/services"
 	"backend-go/pkg/utils"
@@ -13,14 +14,16 @@ import (
 )
 
 type AdminSystemHandler struct {
-	adminService *services.AdminService
-	tracer       trace.Tracer
+	adminService     *services.AdminService
+	reconcileService *services.CounterReconcileService
+	tracer           trace.Tracer
 }
 
 func NewAdminSystemHandler(db *mongo.Database) *AdminSystemHandler {
 	return &AdminSystemHandler{
-		adminService: services.NewAdminService(db),
-		tracer:       otel.Tracer("admin-system-handler"),
+		adminService:     services.NewAdminService(db),
+		reconcileService: services.NewCounterReconcileService(),
+		tracer:           otel.Tracer("admin-system-handler"),
 	}
 }
 
@@ -42,3 +45,47 @@ func (h *AdminSystemHandler) GetSystemHealth(c *gin.Context) {
 	logger.Output(health)
 	Success(c, http.StatusOK, health)
 }
+
+// ReconcileCounters handles POST /api/v1/admin/system/reconcile-counters
+// Recomputes cached engagement counters on trips and comments
+// Query params: ?dryRun=true&batchSize=500
+func (h *AdminSystemHandler) ReconcileCounters(c *gin.Context) {
+	ctx := c.Request.Context()
+	ctx, span := h.tracer.Start(ctx, "AdminSystemHandler.ReconcileCounters")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	dryRun, err := strconv.ParseBool(c.DefaultQuery("dryRun", "false"))
+	if err != nil {
+		logger.Error(err)
+		BadRequest(c, "Invalid dryRun value")
+		return
+	}
+
+	batchSize, err := strconv.Atoi(c.DefaultQuery("batchSize", "500"))
+	if err != nil || batchSize < 1 {
+		BadRequest(c, "Invalid batchSize value")
+		return
+	}
+
+	opts := services.ReconcileOptions{
+		DryRun:    dryRun,
+		BatchSize: batchSize,
+	}
+
+	logger.Input(opts)
+
+	report, err := h.reconcileService.Reconcile(ctx, opts)
+	if err != nil {
+		logger.Error(err)
+		InternalServerError(c, "Failed to reconcile counters: "+err.Error())
+		return
+	}
+
+	logger.Output(map[string]interface{}{
+		"tripsMismatched":    report.TripsMismatched,
+		"commentsMismatched": report.CommentsMismatched,
+		"updated":            report.Updated,
+	})
+	Success(c, http.StatusOK, report)
+}
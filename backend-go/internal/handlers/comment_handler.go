@@ -127,6 +127,14 @@ func (h *CommentHandler) GetReplies(c *gin.Context) {
 		"commentID": commentID,
 	})
 
+	// Replies on trip comments follow the trip visibility
+	viewerID, _ := middleware.GetCurrentUserID(c)
+	if canView, err := h.commentService.CanViewComment(ctx, commentID, viewerID); err != nil || !canView {
+		logger.Warn("Comment not found or not visible")
+		NotFound(c, "Comment not found")
+		return
+	}
+
 	replies, err := h.commentService.GetReplies(ctx, commentID)
 	if err != nil {
 		logger.Error(err)
@@ -399,6 +407,12 @@ func (h *CommentHandler) LikeComment(c *gin.Context) {
 		"userID":    userID,
 	})
 
+	if canView, err := h.commentService.CanViewComment(ctx, commentID, userID); err != nil || !canView {
+		logger.Warn("Comment not found or not visible")
+		NotFound(c, "Comment not found")
+		return
+	}
+
 	err := h.commentService.LikeComment(ctx, commentID, userID)
 	if err != nil {
 		logger.Error(err)
@@ -460,6 +474,12 @@ func (h *CommentHandler) UnlikeComment(c *gin.Context) {
 		"userID":    userID,
 	})
 
+	if canView, err := h.commentService.CanViewComment(ctx, commentID, userID); err != nil || !canView {
+		logger.Warn("Comment not found or not visible")
+		NotFound(c, "Comment not found")
+		return
+	}
+
 	err := h.commentService.UnlikeComment(ctx, commentID, userID)
 	if err != nil {
 		logger.Error(err)
@@ -487,10 +507,21 @@ func (h *CommentHandler) RegisterTripCommentRoutes(trips *gin.RouterGroup, clerk
 		c.Next()
 	}
 
-	trips.GET("/comments", setTargetType, h.GetComments)
+	// Comments follow the trip visibility (private and draft trips are members only)
+	trips.GET("/comments",
+		middleware.OptionalAuth(clerkSecretKey, clerkJWTIssuerDomain),
+		middleware.LoadTrip(),
+		middleware.RequireTripRead(),
+		setTargetType,
+		h.GetComments,
+	)
 
 	authenticated := trips.Group("")
-	authenticated.Use(middleware.Auth(clerkSecretKey, clerkJWTIssuerDomain))
+	authenticated.Use(
+		middleware.Auth(clerkSecretKey, clerkJWTIssuerDomain),
+		middleware.LoadTrip(),
+		middleware.RequireTripRead(),
+	)
 	{
 		authenticated.POST("/comments", setTargetType, h.CreateComment)
 		authenticated.PATCH("/comments/:commentId", setTargetType, h.UpdateComment)
@@ -521,7 +552,7 @@ func (h *CommentHandler) RegisterPlaceCommentRoutes(places *gin.RouterGroup, cle
 func (h *CommentHandler) RegisterCommentRoutes(v1 *gin.RouterGroup, clerkSecretKey, clerkJWTIssuerDomain string) {
 	comments := v1.Group("/comments")
 	{
-		comments.GET("/:commentId/replies", h.GetReplies)
+		comments.GET("/:commentId/replies", middleware.OptionalAuth(clerkSecretKey, clerkJWTIssuerDomain), h.GetReplies)
 
 		authenticated := comments.Group("")
 		authenticated.Use(middleware.Auth(clerkSecretKey, clerkJWTIssuerDomain))
@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"backend-go/internal/middleware"
+	"backend-go/internal/schemas"
+
+	"github.com/gin-gonic/gin"
+)
+
+// After an update the stored trip is at version 2, the ETag GET sends for it must come back
+// through If-Match as the same version so the next edit isn't rejected
+func TestTripDetailETagSucceedsAsIfMatch(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	detail := &schemas.TripDetailResponse{Version: 2}
+
+	get := httptest.NewRecorder()
+	getCtx, _ := gin.CreateTestContext(get)
+	SetETag(getCtx, detail.Version)
+	etag := get.Header().Get("ETag")
+
+	var sent *int64
+	router := gin.New()
+	router.PATCH("/trips/:id", middleware.RequireIfMatch(), func(c *gin.Context) {
+		sent = middleware.GetIfMatchVersion(c)
+		c.Status(http.StatusOK)
+	})
+
+	patch := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPatch, "/trips/1", nil)
+	req.Header.Set("If-Match", etag)
+	router.ServeHTTP(patch, req)
+
+	if patch.Code != http.StatusOK {
+		t.Fatalf("If-Match %s was rejected with %d", etag, patch.Code)
+	}
+	if sent == nil || *sent != detail.Version {
+		t.Fatalf("If-Match %s parsed as %v, want version %d", etag, sent, detail.Version)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"backend-go/internal/models"
 	"backend-go/internal/schemas"
 	"backend-go/internal/services"
+	"backend-go/pkg/sse"
 	"backend-go/pkg/utils"
 
 	"github.com/gin-gonic/gin"
@@ -20,24 +21,36 @@ type ExpenseHandler struct {
 	tracer         trace.Tracer
 }
 
-func NewExpenseHandler() *ExpenseHandler {
+func NewExpenseHandler(tripHub *sse.TripHub) *ExpenseHandler {
 	return &ExpenseHandler{
-		expenseService: services.NewExpenseService(),
+		expenseService: services.NewExpenseService(tripHub),
 		tracer:         otel.Tracer("expense-handler"),
 	}
 }
 
 // RegisterRoutes registers expense routes under /trips/:id
 func (h *ExpenseHandler) RegisterRoutes(trips *gin.RouterGroup, clerkSecretKey, clerkJWTIssuerDomain string) {
-	// Public routes
-	trips.GET("/expenses", h.GetExpensesByTripID)
-	trips.GET("/expenses/total", h.GetTotalExpensesByTrip)
-	trips.GET("/expenses/category", h.GetExpensesByCategory)
-	trips.GET("/expenses/:expenseId", h.GetExpense)
+	// Public routes (draft trips are visible to members only)
+	public := trips.Group("")
+	public.Use(
+		middleware.OptionalAuth(clerkSecretKey, clerkJWTIssuerDomain),
+		middleware.LoadTrip(),
+		middleware.RequireTripRead(),
+	)
+	{
+		public.GET("/expenses", h.GetExpensesByTripID)
+		public.GET("/expenses/total", h.GetTotalExpensesByTrip)
+		public.GET("/expenses/category", h.GetExpensesByCategory)
+		public.GET("/expenses/:expenseId", h.GetExpense)
+	}
 
-	// Authenticated routes
+	// Authenticated routes (owner and editor only)
 	authenticated := trips.Group("")
-	authenticated.Use(middleware.Auth(clerkSecretKey, clerkJWTIssuerDomain))
+	authenticated.Use(
+		middleware.Auth(clerkSecretKey, clerkJWTIssuerDomain),
+		middleware.LoadTrip(),
+		middleware.RequireTripRole(models.MemberRoleOwner, models.MemberRoleEditor),
+	)
 	{
 		authenticated.POST("/expenses", h.CreateExpense)
 		authenticated.PATCH("/expenses/:expenseId", h.UpdateExpense)
@@ -162,7 +175,7 @@ func (h *ExpenseHandler) CreateExpense(c *gin.Context) {
 	)
 	if err != nil {
 		logger.Error(err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.JSON(errorStatus(err, http.StatusBadRequest), gin.H{"error": err.Error()})
 		return
 	}
 
@@ -217,7 +230,7 @@ func (h *ExpenseHandler) UpdateExpense(c *gin.Context) {
 	)
 	if err != nil {
 		logger.Error(err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.JSON(errorStatus(err, http.StatusBadRequest), gin.H{"error": err.Error()})
 		return
 	}
 
@@ -245,7 +258,7 @@ func (h *ExpenseHandler) DeleteExpense(c *gin.Context) {
 
 	if err := h.expenseService.DeleteExpense(ctx, expenseID); err != nil {
 		logger.Error(err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{"error": err.Error()})
 		return
 	}
 
@@ -273,7 +286,7 @@ func (h *ExpenseHandler) MarkExpenseAsSettled(c *gin.Context) {
 
 	if err := h.expenseService.MarkExpenseAsSettled(ctx, expenseID); err != nil {
 		logger.Error(err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.JSON(errorStatus(err, http.StatusBadRequest), gin.H{"error": err.Error()})
 		return
 	}
 
@@ -2,69 +2,100 @@ package handlers
 
 import (
 	"bytes"
+	"context"
+	"errors"
+	"fmt"
 	"io"
 	"net/http"
+	"strings"
 
+	"backend-go/internal/config"
 	"backend-go/internal/middleware"
 	"backend-go/internal/models"
 	"backend-go/internal/schemas"
 	"backend-go/internal/services"
+	"backend-go/pkg/sse"
 	"backend-go/pkg/utils"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/trace"
 )
 
 type ItineraryHandler struct {
 	itineraryService *services.ItineraryService
+	batchService     *services.ItineraryBatchService
 	tracer           trace.Tracer
 }
 
-func NewItineraryHandler() *ItineraryHandler {
+func NewItineraryHandler(tripHub *sse.TripHub, travel *config.TravelConfig, schedule *config.ScheduleConfig) *ItineraryHandler {
 	return &ItineraryHandler{
-		itineraryService: services.NewItineraryService(),
+		itineraryService: services.NewItineraryService(tripHub, travel, schedule),
+		batchService:     services.NewItineraryBatchService(tripHub, travel, schedule),
 		tracer:           otel.Tracer("itinerary-handler"),
 	}
 }
 
 // RegisterRoutes registers itinerary routes under /trips/:id
 func (h *ItineraryHandler) RegisterRoutes(trips *gin.RouterGroup, clerkSecretKey, clerkJWTIssuerDomain string) {
-	// Public routes
-	trips.GET("/itineraries", h.GetItinerariesByTripID)
-	trips.GET("/itineraries/:itineraryId", h.GetItinerary)
-	trips.GET("/itineraries/:itineraryId/entries", h.GetEntriesByItineraryID)
-	trips.GET("/itineraries/:itineraryId/entries/:entryId", h.GetEntry)
+	// Public routes (draft trips are visible to members only)
+	public := trips.Group("")
+	public.Use(
+		middleware.OptionalAuth(clerkSecretKey, clerkJWTIssuerDomain),
+		middleware.LoadTrip(),
+		middleware.RequireTripRead(),
+	)
+	{
+		public.GET("/itineraries", h.GetItinerariesByTripID)
+		public.GET("/itineraries/validate", h.ValidateTrip)
+		public.GET("/itineraries/:itineraryId", h.GetItinerary)
+		public.GET("/itineraries/:itineraryId/validate", h.ValidateItinerary)
+		public.GET("/itineraries/:itineraryId/entries", h.GetEntriesByItineraryID)
+		public.GET("/itineraries/:itineraryId/entries/:entryId", h.GetEntry)
+		public.GET("/itineraries/:itineraryId/optimize-route", h.SuggestEntryOrder)
+		public.GET("/itineraries/:itineraryId/fill-times", h.PreviewFillTimes)
+	}
 
-	// Authenticated routes
+	// Authenticated routes (owner and editor only)
 	authenticated := trips.Group("")
-	authenticated.Use(middleware.Auth(clerkSecretKey, clerkJWTIssuerDomain))
+	authenticated.Use(
+		middleware.Auth(clerkSecretKey, clerkJWTIssuerDomain),
+		middleware.LoadTrip(),
+		middleware.RequireTripRole(models.MemberRoleOwner, models.MemberRoleEditor),
+	)
 	{
 		// Itinerary routes
 		authenticated.POST("/itineraries", h.CreateItinerary)
-		authenticated.PATCH("/itineraries/:itineraryId", h.UpdateItinerary)
-		authenticated.DELETE("/itineraries/:itineraryId", h.DeleteItinerary)
+		authenticated.POST("/itineraries/batch", h.ApplyBatch)
+		authenticated.PATCH("/itineraries/:itineraryId", middleware.RequireIfMatch(), h.UpdateItinerary)
+		authenticated.DELETE("/itineraries/:itineraryId", middleware.RequireIfMatch(), h.DeleteItinerary)
 		authenticated.POST("/itineraries/:itineraryId/insert-after", h.InsertItineraryAfter)
 
 		// Entry routes
 		authenticated.POST("/itineraries/:itineraryId/entries", h.CreateEntry)
-		authenticated.PATCH("/itineraries/:itineraryId/entries/:entryId", h.UpdateEntry)
-		authenticated.DELETE("/itineraries/:itineraryId/entries/:entryId", h.DeleteEntry)
+		authenticated.PATCH("/itineraries/:itineraryId/entries/:entryId", middleware.RequireIfMatch(), h.UpdateEntry)
+		authenticated.DELETE("/itineraries/:itineraryId/entries/:entryId", middleware.RequireIfMatch(), h.DeleteEntry)
+		authenticated.POST("/itineraries/:itineraryId/entries/:entryId/move", middleware.RequireIfMatch(), h.MoveEntry)
+		authenticated.POST("/itineraries/:itineraryId/entries/:entryId/copy", h.CopyEntry)
 
-		// Todo routes
+		// Todo routes, todos are embedded so If-Match carries the entry ETag
 		authenticated.POST("/itineraries/:itineraryId/entries/:entryId/todos", h.CreateTodo)
-		authenticated.PATCH("/itineraries/:itineraryId/entries/:entryId/todos/:todoId", h.UpdateTodo)
-		authenticated.DELETE("/itineraries/:itineraryId/entries/:entryId/todos/:todoId", h.DeleteTodo)
+		authenticated.PATCH("/itineraries/:itineraryId/entries/:entryId/todos/:todoId", middleware.RequireIfMatch(), h.UpdateTodo)
+		authenticated.DELETE("/itineraries/:itineraryId/entries/:entryId/todos/:todoId", middleware.RequireIfMatch(), h.DeleteTodo)
 		authenticated.POST("/itineraries/:itineraryId/entries/:entryId/todos/:todoId/toggle", h.ToggleTodo)
-		authenticated.PATCH("/itineraries/:itineraryId/entries/:entryId/todos/reorder", h.ReorderTodos)
+		authenticated.PATCH("/itineraries/:itineraryId/entries/:entryId/todos/reorder", middleware.RequireIfMatch(), h.ReorderTodos)
 
-		// Reorder entries
-		authenticated.PATCH("/itineraries/:itineraryId/entries/reorder", h.ReorderEntries)
+		// Reorder entries, If-Match carries the itinerary ETag
+		authenticated.PATCH("/itineraries/:itineraryId/entries/reorder", middleware.RequireIfMatch(), h.ReorderEntries)
+		authenticated.POST("/itineraries/:itineraryId/optimize-route", middleware.RequireIfMatch(), h.ApplySuggestedEntryOrder)
+		authenticated.POST("/itineraries/:itineraryId/fill-times", middleware.RequireIfMatch(), h.ApplyFillTimes)
 	}
 }
 
 // GetItinerariesByTripID godoc
 // @Summary Get all itineraries (days) for a trip
+// @Description Each day carries legs with travel estimates between consecutive place entries, tooShort flags gaps shorter than the travel time
 // @Tags itineraries
 // @Param tripId path string true "Trip ID"
 // @Success 200 {array} models.Itinerary
@@ -112,6 +143,7 @@ func (h *ItineraryHandler) GetItinerary(c *gin.Context) {
 	}
 
 	logger.Output(itinerary)
+	SetETag(c, itinerary.Version)
 	c.JSON(http.StatusOK, itinerary)
 }
 
@@ -153,7 +185,7 @@ func (h *ItineraryHandler) CreateItinerary(c *gin.Context) {
 	)
 	if err != nil {
 		logger.Error(err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.JSON(errorStatus(err, http.StatusBadRequest), gin.H{"error": err.Error()})
 		return
 	}
 
@@ -191,14 +223,20 @@ func (h *ItineraryHandler) UpdateItinerary(c *gin.Context) {
 		req.Title,
 		req.Date,
 		req.Order,
+		middleware.GetIfMatchVersion(c),
 	)
 	if err != nil {
 		logger.Error(err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		if errors.Is(err, services.ErrVersionConflict) {
+			h.respondItineraryConflict(ctx, c, itineraryID)
+			return
+		}
+		c.JSON(errorStatus(err, http.StatusBadRequest), gin.H{"error": err.Error()})
 		return
 	}
 
 	logger.Output(itinerary)
+	SetETag(c, itinerary.Version)
 	c.JSON(http.StatusOK, itinerary)
 }
 
@@ -217,9 +255,13 @@ func (h *ItineraryHandler) DeleteItinerary(c *gin.Context) {
 	itineraryID := c.Param("itineraryId")
 	logger.Input(map[string]interface{}{"itineraryID": itineraryID})
 
-	if err := h.itineraryService.DeleteItinerary(ctx, itineraryID); err != nil {
+	if err := h.itineraryService.DeleteItinerary(ctx, itineraryID, middleware.GetIfMatchVersion(c)); err != nil {
 		logger.Error(err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		if errors.Is(err, services.ErrVersionConflict) {
+			h.respondItineraryConflict(ctx, c, itineraryID)
+			return
+		}
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{"error": err.Error()})
 		return
 	}
 
@@ -276,11 +318,13 @@ func (h *ItineraryHandler) GetEntry(c *gin.Context) {
 	}
 
 	logger.Output(entry)
+	SetETag(c, entry.Version)
 	c.JSON(http.StatusOK, entry)
 }
 
 // CreateEntry godoc
 // @Summary Create a new entry in an itinerary (day)
+// @Description The entry carries warnings about schedule problems it causes, they don't block saving
 // @Tags itinerary-entries
 // @Param itineraryId path string true "Itinerary ID"
 // @Param entry body schemas.CreateEntryRequest true "Entry data"
@@ -337,16 +381,19 @@ func (h *ItineraryHandler) CreateEntry(c *gin.Context) {
 	)
 	if err != nil {
 		logger.Error(err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.JSON(errorStatus(err, http.StatusBadRequest), gin.H{"error": err.Error()})
 		return
 	}
 
+	entry.Warnings = h.itineraryService.EntryScheduleWarnings(ctx, entry)
+
 	logger.Output(map[string]interface{}{"entryID": entry.ID.Hex()})
 	c.JSON(http.StatusCreated, entry)
 }
 
 // UpdateEntry godoc
 // @Summary Update an entry
+// @Description The entry carries warnings about schedule problems it causes, they don't block saving
 // @Tags itinerary-entries
 // @Param id path string true "Entry ID"
 // @Param entry body schemas.UpdateEntryRequest true "Entry data"
@@ -378,14 +425,22 @@ func (h *ItineraryHandler) UpdateEntry(c *gin.Context) {
 		req.EndTime,
 		req.Order,
 		req.Todos,
+		middleware.GetIfMatchVersion(c),
 	)
 	if err != nil {
 		logger.Error(err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		if errors.Is(err, services.ErrVersionConflict) {
+			h.respondEntryConflict(ctx, c, entryID)
+			return
+		}
+		c.JSON(errorStatus(err, http.StatusBadRequest), gin.H{"error": err.Error()})
 		return
 	}
 
+	entry.Warnings = h.itineraryService.EntryScheduleWarnings(ctx, entry)
+
 	logger.Output(entry)
+	SetETag(c, entry.Version)
 	c.JSON(http.StatusOK, entry)
 }
 
@@ -404,9 +459,13 @@ func (h *ItineraryHandler) DeleteEntry(c *gin.Context) {
 	entryID := c.Param("entryId")
 	logger.Input(map[string]interface{}{"entryID": entryID})
 
-	if err := h.itineraryService.DeleteEntry(ctx, entryID); err != nil {
+	if err := h.itineraryService.DeleteEntry(ctx, entryID, middleware.GetIfMatchVersion(c)); err != nil {
 		logger.Error(err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		if errors.Is(err, services.ErrVersionConflict) {
+			h.respondEntryConflict(ctx, c, entryID)
+			return
+		}
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{"error": err.Error()})
 		return
 	}
 
@@ -441,13 +500,19 @@ func (h *ItineraryHandler) UpdateTodos(c *gin.Context) {
 		"todosCount": len(req.Todos),
 	})
 
-	if err := h.itineraryService.UpdateTodos(ctx, entryID, req.Todos); err != nil {
+	entry, err := h.itineraryService.UpdateTodos(ctx, entryID, req.Todos, middleware.GetIfMatchVersion(c))
+	if err != nil {
 		logger.Error(err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		if errors.Is(err, services.ErrVersionConflict) {
+			h.respondEntryConflict(ctx, c, entryID)
+			return
+		}
+		c.JSON(errorStatus(err, http.StatusBadRequest), gin.H{"error": err.Error()})
 		return
 	}
 
 	logger.Output("Todos updated successfully")
+	SetETag(c, entry.Version)
 	c.JSON(http.StatusOK, gin.H{"message": "todos updated"})
 }
 
@@ -482,7 +547,7 @@ func (h *ItineraryHandler) CreateTodo(c *gin.Context) {
 	todo, err := h.itineraryService.CreateTodo(ctx, entryID, req.Title, *req.Order)
 	if err != nil {
 		logger.Error(err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.JSON(errorStatus(err, http.StatusBadRequest), gin.H{"error": err.Error()})
 		return
 	}
 
@@ -528,13 +593,19 @@ func (h *ItineraryHandler) UpdateTodo(c *gin.Context) {
 		"title":   *req.Title,
 	})
 
-	if err := h.itineraryService.UpdateTodo(ctx, entryID, todoID, *req.Title); err != nil {
+	entry, err := h.itineraryService.UpdateTodo(ctx, entryID, todoID, *req.Title, middleware.GetIfMatchVersion(c))
+	if err != nil {
 		logger.Error(err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		if errors.Is(err, services.ErrVersionConflict) {
+			h.respondEntryConflict(ctx, c, entryID)
+			return
+		}
+		c.JSON(errorStatus(err, http.StatusBadRequest), gin.H{"error": err.Error()})
 		return
 	}
 
 	logger.Output("Todo updated successfully")
+	SetETag(c, entry.Version)
 	c.JSON(http.StatusOK, gin.H{"message": "todo updated"})
 }
 
@@ -559,13 +630,19 @@ func (h *ItineraryHandler) DeleteTodo(c *gin.Context) {
 		"todoID":  todoID,
 	})
 
-	if err := h.itineraryService.DeleteTodo(ctx, entryID, todoID); err != nil {
+	entry, err := h.itineraryService.DeleteTodo(ctx, entryID, todoID, middleware.GetIfMatchVersion(c))
+	if err != nil {
 		logger.Error(err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		if errors.Is(err, services.ErrVersionConflict) {
+			h.respondEntryConflict(ctx, c, entryID)
+			return
+		}
+		c.JSON(errorStatus(err, http.StatusBadRequest), gin.H{"error": err.Error()})
 		return
 	}
 
 	logger.Output("Todo deleted successfully")
+	SetETag(c, entry.Version)
 	c.Status(http.StatusNoContent)
 }
 
@@ -596,10 +673,14 @@ func (h *ItineraryHandler) ReorderTodos(c *gin.Context) {
 		"todoIDCount": len(req.TodoIDs),
 	})
 
-	entry, err := h.itineraryService.ReorderTodos(ctx, entryID, req.TodoIDs)
+	entry, err := h.itineraryService.ReorderTodos(ctx, entryID, req.TodoIDs, middleware.GetIfMatchVersion(c))
 	if err != nil {
 		logger.Error(err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		if errors.Is(err, services.ErrVersionConflict) {
+			h.respondEntryConflict(ctx, c, entryID)
+			return
+		}
+		c.JSON(errorStatus(err, http.StatusBadRequest), gin.H{"error": err.Error()})
 		return
 	}
 
@@ -607,6 +688,7 @@ func (h *ItineraryHandler) ReorderTodos(c *gin.Context) {
 	entryResponse := schemas.ToItineraryEntryResponse(entry)
 
 	logger.Output("Todos reordered successfully")
+	SetETag(c, entry.Version)
 	c.JSON(http.StatusOK, gin.H{
 		"message": "todos reordered",
 		"entry":   entryResponse,
@@ -634,13 +716,19 @@ func (h *ItineraryHandler) ToggleTodo(c *gin.Context) {
 		"todoID":  todoID,
 	})
 
-	if err := h.itineraryService.ToggleTodo(ctx, entryID, todoID); err != nil {
+	entry, err := h.itineraryService.ToggleTodo(ctx, entryID, todoID)
+	if err != nil {
 		logger.Error(err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		if errors.Is(err, services.ErrVersionConflict) {
+			h.respondEntryConflict(ctx, c, entryID)
+			return
+		}
+		c.JSON(errorStatus(err, http.StatusBadRequest), gin.H{"error": err.Error()})
 		return
 	}
 
 	logger.Output("Todo toggled successfully")
+	SetETag(c, entry.Version)
 	c.JSON(http.StatusOK, gin.H{"message": "todo toggled"})
 }
 
@@ -671,18 +759,514 @@ func (h *ItineraryHandler) ReorderEntries(c *gin.Context) {
 		"entryIDCount": len(req.EntryIDs),
 	})
 
-	entries, err := h.itineraryService.ReorderEntries(ctx, itineraryID, req.EntryIDs)
+	itinerary, err := h.itineraryService.ReorderEntries(ctx, itineraryID, req.EntryIDs, middleware.GetIfMatchVersion(c))
 	if err != nil {
 		logger.Error(err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		if errors.Is(err, services.ErrVersionConflict) {
+			h.respondItineraryConflict(ctx, c, itineraryID)
+			return
+		}
+		c.JSON(errorStatus(err, http.StatusBadRequest), gin.H{"error": err.Error()})
 		return
 	}
 
 	logger.Output("Entries reordered successfully")
+	SetETag(c, itinerary.Version)
 	c.JSON(http.StatusOK, gin.H{
 		"message": "entries reordered",
-		"entries": entries,
+		"entries": itinerary.Entries,
+	})
+}
+
+// ValidateTrip godoc
+// @Summary Check every day of a trip for schedule problems
+// @Description Reports overlapping entries, visits outside opening hours, end times before start times and days over the scheduled hours limit
+// @Tags itineraries
+// @Param id path string true "Trip ID"
+// @Success 200 {object} schemas.ScheduleValidationResponse
+// @Router /trips/{id}/itineraries/validate [get]
+func (h *ItineraryHandler) ValidateTrip(c *gin.Context) {
+	ctx := c.Request.Context()
+	ctx, span := h.tracer.Start(ctx, "ItineraryHandler.ValidateTrip")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	tripID := c.Param("id")
+	logger.Input(map[string]interface{}{"tripID": tripID})
+
+	warnings, err := h.itineraryService.ValidateTrip(ctx, tripID)
+	if err != nil {
+		logger.Error(err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	logger.Output(map[string]interface{}{"warnings": len(warnings)})
+	c.JSON(http.StatusOK, schemas.ScheduleValidationResponse{
+		Valid:    len(warnings) == 0,
+		Warnings: warnings,
+	})
+}
+
+// ValidateItinerary godoc
+// @Summary Check a day for schedule problems
+// @Tags itineraries
+// @Param id path string true "Trip ID"
+// @Param itineraryId path string true "Itinerary ID"
+// @Success 200 {object} schemas.ScheduleValidationResponse
+// @Router /trips/{id}/itineraries/{itineraryId}/validate [get]
+func (h *ItineraryHandler) ValidateItinerary(c *gin.Context) {
+	ctx := c.Request.Context()
+	ctx, span := h.tracer.Start(ctx, "ItineraryHandler.ValidateItinerary")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	itineraryID := c.Param("itineraryId")
+	logger.Input(map[string]interface{}{"itineraryID": itineraryID})
+
+	warnings, err := h.itineraryService.ValidateItinerary(ctx, itineraryID)
+	if err != nil {
+		logger.Error(err)
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	logger.Output(map[string]interface{}{"warnings": len(warnings)})
+	c.JSON(http.StatusOK, schemas.ScheduleValidationResponse{
+		Valid:    len(warnings) == 0,
+		Warnings: warnings,
+	})
+}
+
+// MoveEntry godoc
+// @Summary Move an entry to another day
+// @Description The target day may belong to another trip the user can edit, expenses and documents are unlinked then. If-Match carries the entry ETag.
+// @Tags itinerary-entries
+// @Param id path string true "Trip ID"
+// @Param itineraryId path string true "Itinerary ID"
+// @Param entryId path string true "Entry ID"
+// @Param move body schemas.MoveEntryRequest true "Target day and position"
+// @Success 200 {object} models.ItineraryEntry
+// @Router /trips/{id}/itineraries/{itineraryId}/entries/{entryId}/move [post]
+func (h *ItineraryHandler) MoveEntry(c *gin.Context) {
+	ctx := c.Request.Context()
+	ctx, span := h.tracer.Start(ctx, "ItineraryHandler.MoveEntry")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	entryID := c.Param("entryId")
+	userID, _ := middleware.GetCurrentUserID(c)
+
+	var req schemas.MoveEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("Invalid request body")
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	logger.Input(map[string]interface{}{
+		"entryID":           entryID,
+		"targetItineraryID": req.TargetItineraryID,
+		"order":             req.Order,
+	})
+
+	entry, err := h.itineraryService.MoveEntry(ctx, entryID, userID, req.TargetItineraryID, req.Order, middleware.GetIfMatchVersion(c))
+	if err != nil {
+		logger.Error(err)
+		if errors.Is(err, services.ErrVersionConflict) {
+			h.respondEntryConflict(ctx, c, entryID)
+			return
+		}
+		c.JSON(entryTransferErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	logger.Output(map[string]interface{}{"itineraryID": entry.ItineraryID.Hex()})
+	SetETag(c, entry.Version)
+	c.JSON(http.StatusOK, entry)
+}
+
+// CopyEntry godoc
+// @Summary Copy an entry to a day
+// @Description The target day may belong to another trip the user can edit. Todos, photos and place are copied, expenses and documents are not.
+// @Tags itinerary-entries
+// @Param id path string true "Trip ID"
+// @Param itineraryId path string true "Itinerary ID"
+// @Param entryId path string true "Entry ID"
+// @Param copy body schemas.MoveEntryRequest true "Target day and position"
+// @Success 201 {object} models.ItineraryEntry
+// @Router /trips/{id}/itineraries/{itineraryId}/entries/{entryId}/copy [post]
+func (h *ItineraryHandler) CopyEntry(c *gin.Context) {
+	ctx := c.Request.Context()
+	ctx, span := h.tracer.Start(ctx, "ItineraryHandler.CopyEntry")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	entryID := c.Param("entryId")
+	userID, _ := middleware.GetCurrentUserID(c)
+
+	var req schemas.MoveEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("Invalid request body")
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	logger.Input(map[string]interface{}{
+		"entryID":           entryID,
+		"targetItineraryID": req.TargetItineraryID,
+		"order":             req.Order,
+	})
+
+	entry, err := h.itineraryService.CopyEntry(ctx, entryID, userID, req.TargetItineraryID, req.Order)
+	if err != nil {
+		logger.Error(err)
+		c.JSON(entryTransferErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	logger.Output(map[string]interface{}{"entryID": entry.ID.Hex()})
+	SetETag(c, entry.Version)
+	c.JSON(http.StatusCreated, entry)
+}
+
+// entryTransferErrorStatus maps move and copy errors, the target day is checked by the service
+func entryTransferErrorStatus(err error) int {
+	switch err.Error() {
+	case "target itinerary not found", "entry not found":
+		return http.StatusNotFound
+	case "forbidden: you can't edit the target trip":
+		return http.StatusForbidden
+	}
+	return errorStatus(err, http.StatusBadRequest)
+}
+
+// ApplyBatch godoc
+// @Summary Apply itinerary, entry and todo changes all or nothing
+// @Description Operations run in order inside one MongoDB transaction (needs a replica set). The first failure rolls everything back.
+// @Description IDs may be "$<ref>" to name what an earlier operation created. Operations whose single endpoint requires If-Match must carry version unless their target is a ref, 428 otherwise.
+// @Tags itineraries
+// @Param id path string true "Trip ID"
+// @Param batch body schemas.BatchRequest true "Ordered operations"
+// @Success 200 {object} schemas.BatchResponse
+// @Failure 412 {object} schemas.BatchResponse
+// @Router /trips/{id}/itineraries/batch [post]
+func (h *ItineraryHandler) ApplyBatch(c *gin.Context) {
+	ctx := c.Request.Context()
+	ctx, span := h.tracer.Start(ctx, "ItineraryHandler.ApplyBatch")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	tripID := c.Param("id")
+	userID, _ := middleware.GetCurrentUserID(c)
+
+	var req schemas.BatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("Invalid request body")
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Same rule as If-Match on the single endpoints, a batch can't skip the concurrency check
+	if err := requireBatchVersions(req.Operations); err != nil {
+		logger.Warn("Missing operation version")
+		c.JSON(http.StatusPreconditionRequired, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := bindBatchPayloads(req.Operations); err != nil {
+		logger.Warn("Invalid operation data")
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	logger.Input(map[string]interface{}{
+		"tripID":     tripID,
+		"userID":     userID,
+		"operations": len(req.Operations),
+	})
+
+	response, err := h.batchService.ApplyBatch(ctx, tripID, userID, req.Operations)
+	if err != nil {
+		logger.Error(err)
+		response.Error = err.Error()
+		if errors.Is(err, services.ErrVersionConflict) {
+			c.JSON(http.StatusPreconditionFailed, response)
+			return
+		}
+		c.JSON(batchErrorStatus(err), response)
+		return
+	}
+
+	logger.Output(map[string]interface{}{"operations": len(response.Results)})
+	c.JSON(http.StatusOK, response)
+}
+
+// batchVersionedOps are the operations whose single endpoint requires If-Match
+var batchVersionedOps = map[string]bool{
+	schemas.BatchOpItineraryUpdate: true,
+	schemas.BatchOpItineraryDelete: true,
+	schemas.BatchOpEntryUpdate:     true,
+	schemas.BatchOpEntryDelete:     true,
+	schemas.BatchOpEntryMove:       true,
+	schemas.BatchOpEntriesReorder:  true,
+	schemas.BatchOpTodoUpdate:      true,
+	schemas.BatchOpTodoDelete:      true,
+}
+
+// requireBatchVersions rejects versioned operations that don't carry the version they expect
+// Targets created earlier in the batch ("$ref") can't have been changed by anyone else and are exempt.
+func requireBatchVersions(operations []schemas.BatchOperation) error {
+	for i, operation := range operations {
+		target := operation.EntryID
+		if strings.HasPrefix(operation.Op, "itinerary.") || operation.Op == schemas.BatchOpEntriesReorder {
+			target = operation.ItineraryID
+		}
+		if batchVersionedOps[operation.Op] && operation.Version == nil && !strings.HasPrefix(target, "$") {
+			return fmt.Errorf("operations[%d]: version is required for %s", i, operation.Op)
+		}
+	}
+	return nil
+}
+
+// bindBatchPayloads binds and validates each operation's data as the body of its single endpoint
+func bindBatchPayloads(operations []schemas.BatchOperation) error {
+	for i := range operations {
+		operation := &operations[i]
+
+		var payload interface{}
+		switch operation.Op {
+		case schemas.BatchOpItineraryCreate:
+			payload = &schemas.CreateItineraryRequest{}
+		case schemas.BatchOpItineraryUpdate:
+			payload = &schemas.UpdateItineraryRequest{}
+		case schemas.BatchOpEntryCreate:
+			payload = &schemas.CreateEntryRequest{}
+		case schemas.BatchOpEntryUpdate:
+			payload = &schemas.UpdateEntryRequest{}
+		case schemas.BatchOpEntryMove, schemas.BatchOpEntryCopy:
+			payload = &schemas.MoveEntryRequest{}
+		case schemas.BatchOpEntriesReorder:
+			payload = &schemas.ReorderEntriesRequest{}
+		case schemas.BatchOpTodoCreate:
+			payload = &schemas.CreateTodoRequest{}
+		case schemas.BatchOpTodoUpdate:
+			payload = &schemas.UpdateTodoRequest{}
+		default:
+			// Deletes and toggles only need IDs
+			continue
+		}
+
+		if len(operation.Data) == 0 {
+			return fmt.Errorf("operations[%d]: data is required", i)
+		}
+		if err := binding.JSON.BindBody(operation.Data, payload); err != nil {
+			return fmt.Errorf("operations[%d]: %w", i, err)
+		}
+		if update, ok := payload.(*schemas.UpdateTodoRequest); ok && update.Title == nil {
+			return fmt.Errorf("operations[%d]: no fields to update", i)
+		}
+		operation.Payload = payload
+	}
+	return nil
+}
+
+// batchErrorStatus maps the error of the operation that failed the batch
+func batchErrorStatus(err error) int {
+	switch err.Error() {
+	case "trip not found", "itinerary not found", "target itinerary not found", "entry not found", "todo not found":
+		return http.StatusNotFound
+	case "forbidden: you can't edit the target trip":
+		return http.StatusForbidden
+	}
+	return errorStatus(err, http.StatusBadRequest)
+}
+
+// SuggestEntryOrder godoc
+// @Summary Suggest the shortest order of a day's entries
+// @Description Preview only. Start and end entries, entries with a start time and entries without a location keep their positions.
+// @Tags itineraries
+// @Param id path string true "Trip ID"
+// @Param itineraryId path string true "Itinerary ID"
+// @Param startEntryId query string false "Entry to visit first"
+// @Param endEntryId query string false "Entry to visit last"
+// @Success 200 {object} schemas.RouteSuggestionResponse
+// @Router /trips/{id}/itineraries/{itineraryId}/optimize-route [get]
+func (h *ItineraryHandler) SuggestEntryOrder(c *gin.Context) {
+	ctx := c.Request.Context()
+	ctx, span := h.tracer.Start(ctx, "ItineraryHandler.SuggestEntryOrder")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	itineraryID := c.Param("itineraryId")
+
+	var req schemas.OptimizeRouteRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		logger.Warn("Invalid query")
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	logger.Input(map[string]interface{}{
+		"itineraryID":  itineraryID,
+		"startEntryID": req.StartEntryID,
+		"endEntryID":   req.EndEntryID,
+	})
+
+	suggestion, err := h.itineraryService.SuggestEntryOrder(ctx, itineraryID, req.StartEntryID, req.EndEntryID)
+	if err != nil {
+		logger.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	logger.Output(map[string]interface{}{
+		"changed": suggestion.Changed,
+		"savedKm": suggestion.SavedKm,
+	})
+	c.JSON(http.StatusOK, suggestion)
+}
+
+// ApplySuggestedEntryOrder godoc
+// @Summary Reorder a day's entries to the shortest suggested order
+// @Description Applies the order previewed by GET optimize-route, If-Match carries the itinerary ETag
+// @Tags itineraries
+// @Param id path string true "Trip ID"
+// @Param itineraryId path string true "Itinerary ID"
+// @Param route body schemas.OptimizeRouteRequest false "Fixed start and end entries"
+// @Success 200 {object} schemas.RouteSuggestionResponse
+// @Router /trips/{id}/itineraries/{itineraryId}/optimize-route [post]
+func (h *ItineraryHandler) ApplySuggestedEntryOrder(c *gin.Context) {
+	ctx := c.Request.Context()
+	ctx, span := h.tracer.Start(ctx, "ItineraryHandler.ApplySuggestedEntryOrder")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	itineraryID := c.Param("itineraryId")
+
+	// The body is optional, without it no entry is pinned
+	var req schemas.OptimizeRouteRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			logger.Warn("Invalid request body")
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	logger.Input(map[string]interface{}{
+		"itineraryID":  itineraryID,
+		"startEntryID": req.StartEntryID,
+		"endEntryID":   req.EndEntryID,
 	})
+
+	suggestion, itinerary, err := h.itineraryService.ApplySuggestedEntryOrder(ctx, itineraryID, req.StartEntryID, req.EndEntryID, middleware.GetIfMatchVersion(c))
+	if err != nil {
+		logger.Error(err)
+		if errors.Is(err, services.ErrVersionConflict) {
+			h.respondItineraryConflict(ctx, c, itineraryID)
+			return
+		}
+		c.JSON(errorStatus(err, http.StatusBadRequest), gin.H{"error": err.Error()})
+		return
+	}
+
+	logger.Output(map[string]interface{}{
+		"applied": suggestion.Applied,
+		"savedKm": suggestion.SavedKm,
+	})
+	SetETag(c, itinerary.Version)
+	c.JSON(http.StatusOK, suggestion)
+}
+
+// PreviewFillTimes godoc
+// @Summary Preview start and end times for a day's entries from their durations
+// @Description Entries with a start time keep it, travel between places is added before each start
+// @Tags itineraries
+// @Param id path string true "Trip ID"
+// @Param itineraryId path string true "Itinerary ID"
+// @Param dayStart query string true "HH:MM the first entry starts at"
+// @Param bufferMinutes query int false "Slack after each entry"
+// @Param defaultDuration query int false "Minutes for places without a duration, 60 when omitted"
+// @Param travelMode query string false "walk, transit or drive, transit when omitted"
+// @Success 200 {object} schemas.FillTimesResponse
+// @Router /trips/{id}/itineraries/{itineraryId}/fill-times [get]
+func (h *ItineraryHandler) PreviewFillTimes(c *gin.Context) {
+	ctx := c.Request.Context()
+	ctx, span := h.tracer.Start(ctx, "ItineraryHandler.PreviewFillTimes")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	itineraryID := c.Param("itineraryId")
+
+	var req schemas.FillTimesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		logger.Warn("Invalid query")
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	logger.Input(map[string]interface{}{
+		"itineraryID": itineraryID,
+		"dayStart":    req.DayStart,
+		"buffer":      req.BufferMinutes,
+	})
+
+	result, err := h.itineraryService.FillTimes(ctx, itineraryID, req)
+	if err != nil {
+		logger.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	logger.Output(map[string]interface{}{"entries": len(result.Entries)})
+	c.JSON(http.StatusOK, result)
+}
+
+// ApplyFillTimes godoc
+// @Summary Save start and end times for a day's entries from their durations
+// @Description Saves the times previewed by GET fill-times, If-Match carries the itinerary ETag
+// @Tags itineraries
+// @Param id path string true "Trip ID"
+// @Param itineraryId path string true "Itinerary ID"
+// @Param fill body schemas.FillTimesRequest true "Day start, buffer and travel mode"
+// @Success 200 {object} schemas.FillTimesResponse
+// @Router /trips/{id}/itineraries/{itineraryId}/fill-times [post]
+func (h *ItineraryHandler) ApplyFillTimes(c *gin.Context) {
+	ctx := c.Request.Context()
+	ctx, span := h.tracer.Start(ctx, "ItineraryHandler.ApplyFillTimes")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	itineraryID := c.Param("itineraryId")
+
+	var req schemas.FillTimesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("Invalid request body")
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	logger.Input(map[string]interface{}{
+		"itineraryID": itineraryID,
+		"dayStart":    req.DayStart,
+		"buffer":      req.BufferMinutes,
+	})
+
+	result, itinerary, err := h.itineraryService.ApplyFillTimes(ctx, itineraryID, req, middleware.GetIfMatchVersion(c))
+	if err != nil {
+		logger.Error(err)
+		if errors.Is(err, services.ErrVersionConflict) {
+			h.respondItineraryConflict(ctx, c, itineraryID)
+			return
+		}
+		c.JSON(errorStatus(err, http.StatusBadRequest), gin.H{"error": err.Error()})
+		return
+	}
+
+	logger.Output(map[string]interface{}{"entries": len(result.Entries)})
+	SetETag(c, itinerary.Version)
+	c.JSON(http.StatusOK, result)
 }
 
 // InsertItineraryAfter godoc
@@ -722,10 +1306,14 @@ func (h *ItineraryHandler) InsertItineraryAfter(c *gin.Context) {
 			NotFound(c, err.Error())
 			return
 		}
-		if err.Error() == "unauthorized: you don't own this trip" {
+		if err.Error() == "unauthorized: you can't edit this trip" {
 			Forbidden(c, err.Error())
 			return
 		}
+		if err.Error() == "trip is archived" {
+			Error(c, http.StatusConflict, err.Error())
+			return
+		}
 		InternalServerError(c, err.Error())
 		return
 	}
@@ -736,3 +1324,23 @@ func (h *ItineraryHandler) InsertItineraryAfter(c *gin.Context) {
 	})
 	Success(c, http.StatusCreated, newItinerary)
 }
+
+// respondItineraryConflict sends 412 with the day and its entries as they are now
+func (h *ItineraryHandler) respondItineraryConflict(ctx context.Context, c *gin.Context, itineraryID string) {
+	itinerary, err := h.itineraryService.GetItinerary(ctx, itineraryID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "itinerary not found"})
+		return
+	}
+	PreconditionFailed(c, itinerary.Version, itinerary)
+}
+
+// respondEntryConflict sends 412 with the entry as it is now
+func (h *ItineraryHandler) respondEntryConflict(ctx context.Context, c *gin.Context, entryID string) {
+	entry, err := h.itineraryService.GetEntry(ctx, entryID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "entry not found"})
+		return
+	}
+	PreconditionFailed(c, entry.Version, entry)
+}
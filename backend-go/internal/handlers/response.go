@@ -1,6 +1,9 @@
 package handlers
 
 import (
+	"net/http"
+	"strconv"
+
 	"github.com/gin-gonic/gin"
 )
 
@@ -18,6 +21,14 @@ type ErrorResponse struct {
 	Error   ErrorDetails `json:"error"`
 }
 
+// ConflictResponse represents a 412 response carrying the resource's current state
+type ConflictResponse struct {
+	Status  string       `json:"status"`
+	TraceID string       `json:"traceId"`
+	Error   ErrorDetails `json:"error"`
+	Current interface{}  `json:"current"`
+}
+
 // ErrorDetails contains error information
 type ErrorDetails struct {
 	Code    int    `json:"code"`
@@ -81,3 +92,36 @@ func NotFound(c *gin.Context, message string) {
 func InternalServerError(c *gin.Context, message string) {
 	Error(c, 500, message)
 }
+
+// SetETag sends the resource version as its ETag, clients echo it back in If-Match
+func SetETag(c *gin.Context, version int64) {
+	c.Header("ETag", strconv.Quote(strconv.FormatInt(version, 10)))
+}
+
+// PreconditionFailed sends a 412 with the current state and ETag so the client can merge and retry
+func PreconditionFailed(c *gin.Context, version int64, current interface{}) {
+	traceID, _ := c.Get("traceID")
+	traceIDStr := ""
+	if traceID != nil {
+		traceIDStr = traceID.(string)
+	}
+
+	SetETag(c, version)
+	c.JSON(http.StatusPreconditionFailed, ConflictResponse{
+		Status:  "error",
+		TraceID: traceIDStr,
+		Error: ErrorDetails{
+			Code:    http.StatusPreconditionFailed,
+			Message: "resource was modified, reload and retry",
+		},
+		Current: current,
+	})
+}
+
+// errorStatus maps service errors shared by several handlers to their status code, other errors use fallback
+func errorStatus(err error, fallback int) int {
+	if err.Error() == "trip is archived" {
+		return http.StatusConflict
+	}
+	return fallback
+}
@@ -11,12 +11,14 @@ import (
 )
 
 type SSEHandler struct {
-	hub *sse.Hub
+	hub     *sse.Hub
+	tripHub *sse.TripHub
 }
 
-func NewSSEHandler(hub *sse.Hub) *SSEHandler {
+func NewSSEHandler(hub *sse.Hub, tripHub *sse.TripHub) *SSEHandler {
 	return &SSEHandler{
-		hub: hub,
+		hub:     hub,
+		tripHub: tripHub,
 	}
 }
 
@@ -102,10 +104,99 @@ func (h *SSEHandler) StreamNotifications(c *gin.Context) {
 	}
 }
 
+// StreamTrip handles SSE connections for a trip's collaborative editing events
+// @Summary Stream trip changes via SSE
+// @Description Members receive typed events (day.*, entry.*, todos.updated, expense.*, document.*, member.*) when the trip changes
+// @Tags sse
+// @Produce text/event-stream
+// @Param id path string true "Trip ID"
+// @Success 200 {string} string "SSE stream"
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /api/v1/sse/trips/{id} [get]
+func (h *SSEHandler) StreamTrip(c *gin.Context) {
+	// Get user ID from auth middleware
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+	tripID := c.Param("id")
+
+	// Set SSE headers
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("Transfer-Encoding", "chunked")
+	c.Header("X-Accel-Buffering", "no") // Disable nginx buffering
+
+	// Create client channel, bursts like imports send one event per entry
+	clientChan := make(chan sse.TripEvent, 64)
+	client := h.tripHub.Register(tripID, userID.(string), clientChan)
+
+	log.Printf("SSE: Client subscribed to trip %s for user %s", tripID, userID.(string))
+
+	// Cleanup on disconnect
+	defer func() {
+		h.tripHub.Unregister(client)
+		log.Printf("SSE: Client unsubscribed from trip %s for user %s", tripID, userID.(string))
+	}()
+
+	// Send initial connection event
+	initialEvent := sse.Event{
+		Type:      "connected",
+		Timestamp: time.Now().UnixMilli(),
+	}
+	if data, err := sse.FormatSSEEvent("connection", initialEvent); err == nil {
+		c.Writer.Write(data)
+		c.Writer.Flush()
+	}
+
+	// Heartbeat ticker (every 30 seconds)
+	heartbeat := time.NewTicker(30 * time.Second)
+	defer heartbeat.Stop()
+
+	// Stream events
+	clientGone := c.Request.Context().Done()
+	for {
+		select {
+		case <-clientGone:
+			// Client disconnected
+			return
+		case event := <-clientChan:
+			if data, err := sse.FormatTripEvent(event); err == nil {
+				_, writeErr := c.Writer.Write(data)
+				if writeErr != nil {
+					return
+				}
+				c.Writer.Flush()
+			}
+			// Removed members lose access, end their stream
+			if event.CloseFor == userID.(string) {
+				return
+			}
+		case <-heartbeat.C:
+			// Send heartbeat to keep connection alive
+			heartbeatEvent := sse.Event{
+				Type:      "ping",
+				Timestamp: time.Now().UnixMilli(),
+			}
+			if data, err := sse.FormatSSEEvent("heartbeat", heartbeatEvent); err == nil {
+				_, writeErr := c.Writer.Write(data)
+				if writeErr != nil {
+					return
+				}
+				c.Writer.Flush()
+			}
+		}
+	}
+}
+
 // GetSSEStats returns SSE connection statistics (admin only)
 func (h *SSEHandler) GetSSEStats(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{
-		"connectedUsers":  h.hub.GetConnectedUsers(),
-		"connectionCount": h.hub.GetConnectionCount(),
+		"connectedUsers":      h.hub.GetConnectedUsers(),
+		"connectionCount":     h.hub.GetConnectionCount(),
+		"tripConnectionCount": h.tripHub.GetConnectionCount(),
 	})
 }
@@ -0,0 +1,259 @@
+package handlers
+
+import (
+	"net/http"
+
+	"backend-go/internal/middleware"
+	"backend-go/internal/models"
+	"backend-go/internal/schemas"
+	"backend-go/internal/services"
+	"backend-go/pkg/sse"
+	"backend-go/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type TripDocumentHandler struct {
+	documentService *services.TripDocumentService
+	tracer          trace.Tracer
+}
+
+func NewTripDocumentHandler(tripHub *sse.TripHub) *TripDocumentHandler {
+	return &TripDocumentHandler{
+		documentService: services.NewTripDocumentService(tripHub),
+		tracer:          otel.Tracer("trip-document-handler"),
+	}
+}
+
+// RegisterRoutes registers document vault routes under /trips/:id
+func (h *TripDocumentHandler) RegisterRoutes(trips *gin.RouterGroup, clerkSecretKey, clerkJWTIssuerDomain string) {
+	// Member routes, documents stay private even on public trips
+	members := trips.Group("")
+	members.Use(
+		middleware.Auth(clerkSecretKey, clerkJWTIssuerDomain),
+		middleware.LoadTrip(),
+		middleware.RequireTripRole(models.MemberRoleOwner, models.MemberRoleEditor, models.MemberRoleViewer),
+	)
+	{
+		members.GET("/documents", h.ListDocuments)
+		members.GET("/documents/:documentId", h.GetDocument)
+	}
+
+	// Authenticated routes (owner and editor only)
+	authenticated := trips.Group("")
+	authenticated.Use(
+		middleware.Auth(clerkSecretKey, clerkJWTIssuerDomain),
+		middleware.LoadTrip(),
+		middleware.RequireTripRole(models.MemberRoleOwner, models.MemberRoleEditor),
+	)
+	{
+		authenticated.POST("/documents", h.AttachDocument)
+		authenticated.PATCH("/documents/:documentId", h.UpdateDocument)
+		authenticated.DELETE("/documents/:documentId", h.DetachDocument)
+	}
+}
+
+// ListDocuments godoc
+// @Summary List trip documents
+// @Description Tickets, bookings, insurance and ID documents attached to the trip, members only
+// @Tags documents
+// @Param id path string true "Trip ID"
+// @Param entryId query string false "Only documents of this entry"
+// @Param category query string false "ticket, booking, insurance, id or other"
+// @Success 200 {array} models.TripDocument
+// @Router /trips/{id}/documents [get]
+func (h *TripDocumentHandler) ListDocuments(c *gin.Context) {
+	ctx := c.Request.Context()
+	ctx, span := h.tracer.Start(ctx, "TripDocumentHandler.ListDocuments")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	tripID := c.Param("id")
+
+	var query schemas.ListTripDocumentsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		logger.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	logger.Input(map[string]interface{}{
+		"tripID":   tripID,
+		"entryID":  query.EntryID,
+		"category": query.Category,
+	})
+
+	documents, err := h.documentService.ListDocuments(ctx, tripID, query)
+	if err != nil {
+		logger.Error(err)
+		c.JSON(documentErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	logger.Output(map[string]interface{}{
+		"count": len(documents),
+	})
+	c.JSON(http.StatusOK, documents)
+}
+
+// GetDocument godoc
+// @Summary Get a trip document
+// @Tags documents
+// @Param id path string true "Trip ID"
+// @Param documentId path string true "Document ID"
+// @Success 200 {object} models.TripDocument
+// @Router /trips/{id}/documents/{documentId} [get]
+func (h *TripDocumentHandler) GetDocument(c *gin.Context) {
+	ctx := c.Request.Context()
+	ctx, span := h.tracer.Start(ctx, "TripDocumentHandler.GetDocument")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	documentID := c.Param("documentId")
+
+	logger.Input(map[string]interface{}{
+		"documentID": documentID,
+	})
+
+	document, err := h.documentService.GetDocument(ctx, documentID)
+	if err != nil {
+		logger.Error(err)
+		c.JSON(documentErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	logger.Output(map[string]interface{}{
+		"documentID": document.ID.Hex(),
+	})
+	c.JSON(http.StatusOK, document)
+}
+
+// AttachDocument godoc
+// @Summary Attach an uploaded file to the trip or one of its entries
+// @Description The file must have been uploaded by the caller via /files/upload
+// @Tags documents
+// @Param id path string true "Trip ID"
+// @Param document body schemas.AttachTripDocumentRequest true "Document data"
+// @Success 201 {object} models.TripDocument
+// @Router /trips/{id}/documents [post]
+func (h *TripDocumentHandler) AttachDocument(c *gin.Context) {
+	ctx := c.Request.Context()
+	ctx, span := h.tracer.Start(ctx, "TripDocumentHandler.AttachDocument")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	tripID := c.Param("id")
+	userID, _ := middleware.GetCurrentUserID(c)
+
+	var req schemas.AttachTripDocumentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	logger.Input(map[string]interface{}{
+		"tripID":   tripID,
+		"userID":   userID,
+		"fileID":   req.FileID,
+		"entryID":  req.EntryID,
+		"category": req.Category,
+	})
+
+	document, err := h.documentService.AttachDocument(ctx, tripID, userID, req)
+	if err != nil {
+		logger.Error(err)
+		c.JSON(documentErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	logger.Output(map[string]interface{}{
+		"documentID": document.ID.Hex(),
+	})
+	c.JSON(http.StatusCreated, document)
+}
+
+// UpdateDocument godoc
+// @Summary Update a trip document
+// @Description An empty entryId moves the document from its entry to the trip
+// @Tags documents
+// @Param id path string true "Trip ID"
+// @Param documentId path string true "Document ID"
+// @Param document body schemas.UpdateTripDocumentRequest true "Document data"
+// @Success 200 {object} models.TripDocument
+// @Router /trips/{id}/documents/{documentId} [patch]
+func (h *TripDocumentHandler) UpdateDocument(c *gin.Context) {
+	ctx := c.Request.Context()
+	ctx, span := h.tracer.Start(ctx, "TripDocumentHandler.UpdateDocument")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	documentID := c.Param("documentId")
+
+	var req schemas.UpdateTripDocumentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	logger.Input(map[string]interface{}{
+		"documentID": documentID,
+		"entryID":    req.EntryID,
+		"category":   req.Category,
+	})
+
+	document, err := h.documentService.UpdateDocument(ctx, documentID, req)
+	if err != nil {
+		logger.Error(err)
+		c.JSON(documentErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	logger.Output(map[string]interface{}{
+		"documentID": document.ID.Hex(),
+	})
+	c.JSON(http.StatusOK, document)
+}
+
+// DetachDocument godoc
+// @Summary Detach a document from the trip
+// @Description Only the link is removed, the file stays in its uploader's files
+// @Tags documents
+// @Param id path string true "Trip ID"
+// @Param documentId path string true "Document ID"
+// @Success 204
+// @Router /trips/{id}/documents/{documentId} [delete]
+func (h *TripDocumentHandler) DetachDocument(c *gin.Context) {
+	ctx := c.Request.Context()
+	ctx, span := h.tracer.Start(ctx, "TripDocumentHandler.DetachDocument")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	documentID := c.Param("documentId")
+
+	logger.Input(map[string]interface{}{
+		"documentID": documentID,
+	})
+
+	if err := h.documentService.DetachDocument(ctx, documentID); err != nil {
+		logger.Error(err)
+		c.JSON(documentErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	logger.Info("Document detached successfully")
+	c.Status(http.StatusNoContent)
+}
+
+func documentErrorStatus(err error) int {
+	switch err.Error() {
+	case "document not found", "file not found", "entry not found", "trip not found":
+		return http.StatusNotFound
+	case "unauthorized: you don't own this file":
+		return http.StatusForbidden
+	}
+	return errorStatus(err, http.StatusBadRequest)
+}
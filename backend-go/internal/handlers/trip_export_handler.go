@@ -0,0 +1,285 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"backend-go/internal/config"
+	"backend-go/internal/middleware"
+	"backend-go/internal/models"
+	"backend-go/internal/schemas"
+	"backend-go/internal/services"
+	"backend-go/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const calendarContentType = "text/calendar; charset=utf-8"
+
+// mapExportFormats maps export formats to their content type and file extension
+var mapExportFormats = map[string]struct {
+	contentType string
+	extension   string
+}{
+	services.MapFormatGeoJSON: {"application/geo+json", "geojson"},
+	services.MapFormatGPX:     {"application/gpx+xml", "gpx"},
+	services.MapFormatKML:     {"application/vnd.google-earth.kml+xml", "kml"},
+}
+
+type TripExportHandler struct {
+	exportService *services.TripExportService
+	tracer        trace.Tracer
+}
+
+func NewTripExportHandler(cfg *config.BookletConfig) *TripExportHandler {
+	return &TripExportHandler{
+		exportService: services.NewTripExportService(cfg),
+		tracer:        otel.Tracer("trip-export-handler"),
+	}
+}
+
+// ExportICS handles GET /api/v1/trips/:id/export/ics
+func (h *TripExportHandler) ExportICS(c *gin.Context) {
+	ctx := c.Request.Context()
+	ctx, span := h.tracer.Start(ctx, "TripExportHandler.ExportICS")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	tripID := c.Param("id")
+
+	logger.Input(map[string]interface{}{
+		"tripID": tripID,
+	})
+
+	data, trip, err := h.exportService.ExportICS(ctx, tripID)
+	if err != nil {
+		logger.Error(err)
+		handleExportError(c, err)
+		return
+	}
+
+	logger.Output(map[string]interface{}{
+		"bytes": len(data),
+	})
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.ics"`, exportFilename(trip)))
+	c.Data(http.StatusOK, calendarContentType, data)
+}
+
+// ExportMap handles GET /api/v1/trips/:id/export/geojson, /export/gpx and /export/kml
+func (h *TripExportHandler) ExportMap(format string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		ctx, span := h.tracer.Start(ctx, "TripExportHandler.ExportMap")
+		defer span.End()
+		logger := utils.NewTraceLogger(ctx, span)
+
+		tripID := c.Param("id")
+
+		logger.Input(map[string]interface{}{
+			"tripID": tripID,
+			"format": format,
+		})
+
+		data, trip, err := h.exportService.ExportMap(ctx, tripID, format)
+		if err != nil {
+			logger.Error(err)
+			handleExportError(c, err)
+			return
+		}
+
+		output := mapExportFormats[format]
+		logger.Output(map[string]interface{}{
+			"bytes": len(data),
+		})
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.%s"`, exportFilename(trip), output.extension))
+		c.Data(http.StatusOK, output.contentType, data)
+	}
+}
+
+// ExportBooklet handles GET /api/v1/trips/:id/export/pdf
+func (h *TripExportHandler) ExportBooklet(c *gin.Context) {
+	ctx := c.Request.Context()
+	ctx, span := h.tracer.Start(ctx, "TripExportHandler.ExportBooklet")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	tripID := c.Param("id")
+	userID, _ := middleware.GetCurrentUserID(c)
+
+	logger.Input(map[string]interface{}{
+		"tripID": tripID,
+		"userID": userID,
+	})
+
+	data, trip, err := h.exportService.ExportBooklet(ctx, tripID, userID)
+	if err != nil {
+		logger.Error(err)
+		handleExportError(c, err)
+		return
+	}
+
+	logger.Output(map[string]interface{}{
+		"bytes": len(data),
+	})
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.pdf"`, exportFilename(trip)))
+	c.Data(http.StatusOK, "application/pdf", data)
+}
+
+// GetCalendarFeed handles POST /api/v1/trips/:id/calendar-feed
+func (h *TripExportHandler) GetCalendarFeed(c *gin.Context) {
+	ctx := c.Request.Context()
+	ctx, span := h.tracer.Start(ctx, "TripExportHandler.GetCalendarFeed")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	tripID := c.Param("id")
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		logger.Warn("User not authenticated")
+		Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	logger.Input(map[string]interface{}{
+		"tripID": tripID,
+		"userID": userID,
+	})
+
+	token, err := h.exportService.GetCalendarFeedToken(ctx, tripID, userID)
+	if err != nil {
+		logger.Error(err)
+		handleExportError(c, err)
+		return
+	}
+
+	// Build the feed URL from the request so it matches the host the client talks to
+	scheme := "http"
+	if c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	path := fmt.Sprintf("%s/api/v1/calendar/%s.ics", c.Request.Host, token)
+
+	logger.Output(map[string]interface{}{
+		"tripID": tripID,
+	})
+	Success(c, http.StatusOK, schemas.CalendarFeedResponse{
+		Token:     token,
+		WebcalURL: "webcal://" + path,
+		URL:       scheme + "://" + path,
+	})
+}
+
+// RevokeCalendarFeed handles DELETE /api/v1/trips/:id/calendar-feed
+func (h *TripExportHandler) RevokeCalendarFeed(c *gin.Context) {
+	ctx := c.Request.Context()
+	ctx, span := h.tracer.Start(ctx, "TripExportHandler.RevokeCalendarFeed")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	tripID := c.Param("id")
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		logger.Warn("User not authenticated")
+		Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	logger.Input(map[string]interface{}{
+		"tripID": tripID,
+		"userID": userID,
+	})
+
+	if err := h.exportService.RevokeCalendarFeed(ctx, tripID, userID); err != nil {
+		logger.Error(err)
+		handleExportError(c, err)
+		return
+	}
+
+	logger.Info("Calendar feed revoked")
+	Success(c, http.StatusOK, gin.H{"message": "Calendar feed revoked"})
+}
+
+// GetCalendarByToken handles GET /api/v1/calendar/:token (public, token-protected feed)
+func (h *TripExportHandler) GetCalendarByToken(c *gin.Context) {
+	ctx := c.Request.Context()
+	ctx, span := h.tracer.Start(ctx, "TripExportHandler.GetCalendarByToken")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	// Calendar apps expect the subscription URL to end with .ics
+	token := strings.TrimSuffix(c.Param("token"), ".ics")
+
+	data, err := h.exportService.ExportICSByToken(ctx, token)
+	if err != nil {
+		logger.Error(err)
+		handleExportError(c, err)
+		return
+	}
+
+	logger.Output(map[string]interface{}{
+		"bytes": len(data),
+	})
+	c.Header("Cache-Control", "private, max-age=300")
+	c.Data(http.StatusOK, calendarContentType, data)
+}
+
+func handleExportError(c *gin.Context, err error) {
+	switch {
+	case err.Error() == "trip not found" || err.Error() == "calendar feed not found":
+		NotFound(c, err.Error())
+	case strings.HasPrefix(err.Error(), "unauthorized"):
+		Forbidden(c, err.Error())
+	case err.Error() == "unsupported export format":
+		BadRequest(c, err.Error())
+	default:
+		InternalServerError(c, err.Error())
+	}
+}
+
+var unsafeFilenameChars = regexp.MustCompile(`[^\p{L}\p{N}._-]+`)
+
+// exportFilename builds a download filename from the trip title
+func exportFilename(trip *models.Trip) string {
+	name := strings.Trim(unsafeFilenameChars.ReplaceAllString(trip.Title, "-"), "-")
+	if name == "" {
+		return "trip-" + trip.ID.Hex()
+	}
+	return name
+}
+
+// RegisterTripExportRoutes registers export routes under /trips/:id
+func (h *TripExportHandler) RegisterTripExportRoutes(trips *gin.RouterGroup, clerkSecretKey, clerkJWTIssuerDomain string) {
+	exports := trips.Group("/export")
+	exports.Use(
+		middleware.OptionalAuth(clerkSecretKey, clerkJWTIssuerDomain),
+		middleware.LoadTrip(),
+		middleware.RequireTripRead(),
+	)
+	{
+		exports.GET("/ics", h.ExportICS)
+		exports.GET("/geojson", h.ExportMap(services.MapFormatGeoJSON))
+		exports.GET("/gpx", h.ExportMap(services.MapFormatGPX))
+		exports.GET("/kml", h.ExportMap(services.MapFormatKML))
+		exports.GET("/pdf", h.ExportBooklet)
+	}
+
+	authenticated := trips.Group("/calendar-feed")
+	authenticated.Use(
+		middleware.Auth(clerkSecretKey, clerkJWTIssuerDomain),
+		middleware.LoadTrip(),
+		middleware.RequireTripRead(),
+	)
+	{
+		authenticated.POST("", h.GetCalendarFeed)
+		authenticated.DELETE("", middleware.RequireTripRole(models.MemberRoleOwner), h.RevokeCalendarFeed)
+	}
+}
+
+// RegisterCalendarRoutes registers the public calendar feed route
+func (h *TripExportHandler) RegisterCalendarRoutes(v1 *gin.RouterGroup) {
+	v1.GET("/calendar/:token", h.GetCalendarByToken)
+}
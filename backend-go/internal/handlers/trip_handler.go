@@ -1,11 +1,16 @@
 package handlers
 
 import (
+	"context"
+	"errors"
 	"net/http"
 
+	"backend-go/internal/config"
 	"backend-go/internal/middleware"
+	"backend-go/internal/models"
 	"backend-go/internal/schemas"
 	"backend-go/internal/services"
+	"backend-go/pkg/sse"
 	"backend-go/pkg/utils"
 
 	"github.com/gin-gonic/gin"
@@ -18,15 +23,17 @@ type TripHandler struct {
 	expenseService      *services.ExpenseService
 	itineraryService    *services.ItineraryService
 	notificationService *services.NotificationService
+	tripHub             *sse.TripHub
 	tracer              trace.Tracer
 }
 
-func NewTripHandler(notificationService *services.NotificationService) *TripHandler {
+func NewTripHandler(notificationService *services.NotificationService, tripHub *sse.TripHub, travel *config.TravelConfig, schedule *config.ScheduleConfig) *TripHandler {
 	return &TripHandler{
-		tripService:         services.NewTripService(),
-		expenseService:      services.NewExpenseService(),
-		itineraryService:    services.NewItineraryService(),
+		tripService:         services.NewTripService(tripHub),
+		expenseService:      services.NewExpenseService(tripHub),
+		itineraryService:    services.NewItineraryService(tripHub, travel, schedule),
 		notificationService: notificationService,
+		tripHub:             tripHub,
 		tracer:              otel.Tracer("trip-handler"),
 	}
 }
@@ -86,8 +93,10 @@ func (h *TripHandler) GetTrip(c *gin.Context) {
 		"tripID": tripID,
 	})
 
+	viewerID, _ := middleware.GetCurrentUserID(c)
+
 	// Use aggregation to get all data in 1 query (trip + itineraries + entries + expenses + users)
-	data, err := h.tripService.GetTripWithFullData(ctx, tripID)
+	data, err := h.tripService.GetTripWithFullData(ctx, tripID, viewerID)
 	if err != nil {
 		logger.Error(err)
 		NotFound(c, "Trip not found")
@@ -95,6 +104,7 @@ func (h *TripHandler) GetTrip(c *gin.Context) {
 	}
 
 	logger.Output(data)
+	SetETag(c, data.Version)
 	Success(c, http.StatusOK, data)
 }
 
@@ -116,9 +126,144 @@ func (h *TripHandler) ListTrips(c *gin.Context) {
 		"offset": query.Offset,
 	})
 
-	trips, err := h.tripService.ListTrips(ctx, &query)
+	viewerID, _ := middleware.GetCurrentUserID(c)
+
+	trips, err := h.tripService.ListTrips(ctx, &query, viewerID)
+	if err != nil {
+		logger.Error(err)
+		InternalServerError(c, err.Error())
+		return
+	}
+
+	logger.Output(map[string]interface{}{
+		"count": len(trips),
+	})
+	Success(c, http.StatusOK, gin.H{
+		"trips": trips,
+		"meta": gin.H{
+			"limit":  query.Limit,
+			"offset": query.Offset,
+			"total":  len(trips),
+		},
+	})
+}
+
+// SearchTrips handles GET /api/v1/trips/search
+func (h *TripHandler) SearchTrips(c *gin.Context) {
+	ctx := c.Request.Context()
+	ctx, span := h.tracer.Start(ctx, "TripHandler.SearchTrips")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	var query schemas.SearchTripsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		logger.Error(err)
+		BadRequest(c, "Invalid query parameters: "+err.Error())
+		return
+	}
+
+	logger.Input(map[string]interface{}{
+		"q":      query.Q,
+		"limit":  query.Limit,
+		"offset": query.Offset,
+	})
+
+	viewerID, _ := middleware.GetCurrentUserID(c)
+
+	result, err := h.tripService.SearchTrips(ctx, &query, viewerID)
+	if err != nil {
+		logger.Error(err)
+		InternalServerError(c, err.Error())
+		return
+	}
+
+	logger.Output(map[string]interface{}{
+		"count": len(result.Trips),
+		"total": result.Total,
+	})
+	Success(c, http.StatusOK, gin.H{
+		"trips":  result.Trips,
+		"facets": result.Facets,
+		"meta": gin.H{
+			"limit":  query.Limit,
+			"offset": query.Offset,
+			"total":  result.Total,
+		},
+	})
+}
+
+// FindNearbyTrips handles GET /api/v1/trips/nearby
+func (h *TripHandler) FindNearbyTrips(c *gin.Context) {
+	ctx := c.Request.Context()
+	ctx, span := h.tracer.Start(ctx, "TripHandler.FindNearbyTrips")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	var query schemas.NearbyTripsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		logger.Error(err)
+		BadRequest(c, "Invalid query parameters: "+err.Error())
+		return
+	}
+
+	logger.Input(map[string]interface{}{
+		"lat":      *query.Lat,
+		"lng":      *query.Lng,
+		"radiusKm": query.RadiusKm,
+	})
+
+	viewerID, _ := middleware.GetCurrentUserID(c)
+
+	trips, err := h.tripService.FindNearbyTrips(ctx, &query, viewerID)
+	if err != nil {
+		logger.Error(err)
+		InternalServerError(c, err.Error())
+		return
+	}
+
+	logger.Output(map[string]interface{}{
+		"count": len(trips),
+	})
+	Success(c, http.StatusOK, gin.H{
+		"trips": trips,
+		"meta": gin.H{
+			"limit":  query.Limit,
+			"offset": query.Offset,
+			"total":  len(trips),
+		},
+	})
+}
+
+// FindTripsWithinBox handles GET /api/v1/trips/within
+func (h *TripHandler) FindTripsWithinBox(c *gin.Context) {
+	ctx := c.Request.Context()
+	ctx, span := h.tracer.Start(ctx, "TripHandler.FindTripsWithinBox")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	var query schemas.WithinTripsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		logger.Error(err)
+		BadRequest(c, "Invalid query parameters: "+err.Error())
+		return
+	}
+
+	logger.Input(map[string]interface{}{
+		"swLat": *query.SWLat,
+		"swLng": *query.SWLng,
+		"neLat": *query.NELat,
+		"neLng": *query.NELng,
+	})
+
+	viewerID, _ := middleware.GetCurrentUserID(c)
+
+	trips, err := h.tripService.FindTripsWithinBox(ctx, &query, viewerID)
 	if err != nil {
 		logger.Error(err)
+		if err.Error() == "invalid bounding box" {
+			BadRequest(c, err.Error())
+			return
+		}
 		InternalServerError(c, err.Error())
 		return
 	}
@@ -169,13 +314,27 @@ func (h *TripHandler) UpdateTrip(c *gin.Context) {
 		"request": req,
 	})
 
-	trip, err := h.tripService.UpdateTrip(ctx, tripID, userID, &req)
+	trip, err := h.tripService.UpdateTrip(ctx, tripID, userID, &req, middleware.GetIfMatchVersion(c))
 	if err != nil {
 		logger.Error(err)
+		if errors.Is(err, services.ErrVersionConflict) {
+			respondTripConflict(ctx, c, h.tripService, tripID, userID)
+			return
+		}
 		if err.Error() == "trip not found" {
 			NotFound(c, err.Error())
 			return
 		}
+		if err.Error() == "trip is archived" || err.Error() == "cannot shorten trip: removed days have entries" {
+			Error(c, http.StatusConflict, err.Error())
+			return
+		}
+		if err.Error() == "invalid start date format, expected YYYY-MM-DD" ||
+			err.Error() == "invalid end date format, expected YYYY-MM-DD" ||
+			err.Error() == "start date must be before end date" {
+			BadRequest(c, err.Error())
+			return
+		}
 		if err.Error() == "unauthorized: you don't own this trip" {
 			Forbidden(c, err.Error())
 			return
@@ -185,6 +344,7 @@ func (h *TripHandler) UpdateTrip(c *gin.Context) {
 	}
 
 	logger.Output(trip)
+	SetETag(c, trip.Version)
 	Success(c, http.StatusOK, trip)
 }
 
@@ -213,9 +373,13 @@ func (h *TripHandler) DeleteTrip(c *gin.Context) {
 		"userID": userID,
 	})
 
-	err := h.tripService.DeleteTrip(ctx, tripID, userID)
+	err := h.tripService.DeleteTrip(ctx, tripID, userID, middleware.GetIfMatchVersion(c))
 	if err != nil {
 		logger.Error(err)
+		if errors.Is(err, services.ErrVersionConflict) {
+			respondTripConflict(ctx, c, h.tripService, tripID, userID)
+			return
+		}
 		if err.Error() == "trip not found" {
 			NotFound(c, err.Error())
 			return
@@ -235,17 +399,92 @@ func (h *TripHandler) DeleteTrip(c *gin.Context) {
 	})
 }
 
+// ForkTrip handles POST /api/v1/trips/:id/fork
+func (h *TripHandler) ForkTrip(c *gin.Context) {
+	ctx := c.Request.Context()
+	ctx, span := h.tracer.Start(ctx, "TripHandler.ForkTrip")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	tripID := c.Param("id")
+	if tripID == "" {
+		logger.Warn("Trip ID is required")
+		BadRequest(c, "Trip ID is required")
+		return
+	}
+
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		logger.Warn("User not authenticated")
+		Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	var req schemas.ForkTripRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error(err)
+		BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	logger.Input(map[string]interface{}{
+		"tripID":  tripID,
+		"userID":  userID,
+		"request": req,
+	})
+
+	trip, err := h.tripService.ForkTrip(ctx, tripID, userID, &req)
+	if err != nil {
+		logger.Error(err)
+		if err.Error() == "trip not found" {
+			NotFound(c, err.Error())
+			return
+		}
+		if err.Error() == "invalid start date format, expected YYYY-MM-DD" {
+			BadRequest(c, err.Error())
+			return
+		}
+		InternalServerError(c, err.Error())
+		return
+	}
+
+	logger.Output(map[string]interface{}{
+		"tripID":       trip.ID.Hex(),
+		"forkedFromID": tripID,
+	})
+	Success(c, http.StatusCreated, trip)
+}
+
+// respondTripConflict sends 412 with the trip as the viewer would now GET it
+func respondTripConflict(ctx context.Context, c *gin.Context, tripService *services.TripService, tripID, viewerID string) {
+	data, err := tripService.GetTripWithFullData(ctx, tripID, viewerID)
+	if err != nil {
+		NotFound(c, "Trip not found")
+		return
+	}
+	PreconditionFailed(c, data.Version, data)
+}
+
 // RegisterRoutes registers trip routes
 func (h *TripHandler) RegisterRoutes(trips *gin.RouterGroup, clerkSecretKey, clerkJWTIssuerDomain string, commentHandler *CommentHandler, expenseHandler *ExpenseHandler, itineraryHandler *ItineraryHandler) {
-	// Public routes on /trips/:id
-	trips.GET("", h.GetTrip)
+	// Public routes on /trips/:id (draft trips are visible to members only)
+	trips.GET("",
+		middleware.OptionalAuth(clerkSecretKey, clerkJWTIssuerDomain),
+		middleware.LoadTrip(),
+		middleware.RequireTripRead(),
+		h.GetTrip,
+	)
 
 	// Authenticated routes
 	authenticated := trips.Group("")
-	authenticated.Use(middleware.Auth(clerkSecretKey, clerkJWTIssuerDomain))
+	authenticated.Use(
+		middleware.Auth(clerkSecretKey, clerkJWTIssuerDomain),
+		middleware.LoadTrip(),
+	)
 	{
-		authenticated.PATCH("", h.UpdateTrip)
-		authenticated.DELETE("", h.DeleteTrip)
+		authenticated.PATCH("", middleware.RequireTripRole(models.MemberRoleOwner), middleware.RequireIfMatch(), h.UpdateTrip)
+		authenticated.DELETE("", middleware.RequireTripRole(models.MemberRoleOwner), middleware.RequireIfMatch(), h.DeleteTrip)
+		authenticated.POST("/fork", middleware.RequireTripRead(), h.ForkTrip)
 	}
 
 	// Comment routes
@@ -254,6 +493,10 @@ func (h *TripHandler) RegisterRoutes(trips *gin.RouterGroup, clerkSecretKey, cle
 	}
 
 	// Member routes
-	memberHandler := NewTripMemberHandler(h.notificationService)
+	memberHandler := NewTripMemberHandler(h.tripHub)
 	memberHandler.RegisterRoutes(trips, clerkSecretKey, clerkJWTIssuerDomain)
+
+	// Invitation routes
+	invitationHandler := NewTripInvitationHandler(h.notificationService, h.tripHub)
+	invitationHandler.RegisterTripInvitationRoutes(trips, clerkSecretKey, clerkJWTIssuerDomain)
 }
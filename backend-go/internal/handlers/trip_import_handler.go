@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"backend-go/internal/config"
+	"backend-go/internal/middleware"
+	"backend-go/internal/models"
+	"backend-go/internal/services"
+	"backend-go/pkg/sse"
+	"backend-go/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// maxImportFileSize limits uploaded itinerary files
+const maxImportFileSize = 5 * 1024 * 1024
+
+type TripImportHandler struct {
+	importService *services.TripImportService
+	tracer        trace.Tracer
+}
+
+func NewTripImportHandler(tripHub *sse.TripHub, travel *config.TravelConfig, schedule *config.ScheduleConfig) *TripImportHandler {
+	return &TripImportHandler{
+		importService: services.NewTripImportService(tripHub, travel, schedule),
+		tracer:        otel.Tracer("trip-import-handler"),
+	}
+}
+
+// ImportItinerary handles POST /api/v1/trips/:id/import
+// Accepts a multipart "file" field, the format comes from ?format= or the file extension/content
+// ?timezone= is the IANA zone the trip is planned in, calendar times are converted to it
+func (h *TripImportHandler) ImportItinerary(c *gin.Context) {
+	ctx := c.Request.Context()
+	ctx, span := h.tracer.Start(ctx, "TripImportHandler.ImportItinerary")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	tripID := c.Param("id")
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		logger.Warn("User not authenticated")
+		Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		logger.Error(err)
+		BadRequest(c, "No file provided")
+		return
+	}
+
+	if fileHeader.Size > maxImportFileSize {
+		BadRequest(c, "File is too large (max 5MB)")
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		logger.Error(err)
+		BadRequest(c, "Failed to read file")
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, maxImportFileSize))
+	if err != nil {
+		logger.Error(err)
+		BadRequest(c, "Failed to read file")
+		return
+	}
+
+	format := strings.ToLower(c.Query("format"))
+	if format == "" {
+		format = detectImportFormat(fileHeader.Filename, data)
+	}
+
+	var location *time.Location
+	if timezone := c.Query("timezone"); timezone != "" {
+		location, err = time.LoadLocation(timezone)
+		if err != nil {
+			logger.Error(err)
+			BadRequest(c, "Invalid timezone, expected an IANA name like Europe/Paris")
+			return
+		}
+	}
+
+	logger.Input(map[string]interface{}{
+		"tripID":   tripID,
+		"userID":   userID,
+		"filename": fileHeader.Filename,
+		"format":   format,
+		"timezone": c.Query("timezone"),
+	})
+
+	report, err := h.importService.ImportItinerary(ctx, tripID, userID, format, location, data)
+	if err != nil {
+		logger.Error(err)
+		switch {
+		case err.Error() == "trip not found":
+			NotFound(c, err.Error())
+		case strings.HasPrefix(err.Error(), "unauthorized"):
+			Forbidden(c, err.Error())
+		case err.Error() == "trip is archived":
+			Error(c, http.StatusConflict, err.Error())
+		case err.Error() == "unsupported import format" || strings.HasPrefix(err.Error(), "invalid import file"):
+			BadRequest(c, err.Error())
+		default:
+			InternalServerError(c, err.Error())
+		}
+		return
+	}
+
+	logger.Output(map[string]interface{}{
+		"imported": report.Imported,
+		"skipped":  report.Skipped,
+		"failed":   report.Failed,
+	})
+	Success(c, http.StatusOK, report)
+}
+
+// detectImportFormat guesses the format from the file extension, then from the content
+func detectImportFormat(filename string, data []byte) string {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".ics", ".ical", ".icalendar":
+		return services.ImportFormatICS
+	case ".csv":
+		return services.ImportFormatCSV
+	case ".json":
+		return services.ImportFormatJSON
+	}
+
+	trimmed := bytes.TrimSpace(data)
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("BEGIN:VCALENDAR")):
+		return services.ImportFormatICS
+	case bytes.HasPrefix(trimmed, []byte("{")):
+		return services.ImportFormatJSON
+	default:
+		return services.ImportFormatCSV
+	}
+}
+
+// RegisterRoutes registers import routes under /trips/:id
+func (h *TripImportHandler) RegisterRoutes(trips *gin.RouterGroup, clerkSecretKey, clerkJWTIssuerDomain string) {
+	trips.POST("/import",
+		middleware.Auth(clerkSecretKey, clerkJWTIssuerDomain),
+		middleware.LoadTrip(),
+		middleware.RequireTripRole(models.MemberRoleOwner, models.MemberRoleEditor),
+		h.ImportItinerary,
+	)
+}
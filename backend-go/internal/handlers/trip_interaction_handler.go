@@ -0,0 +1,276 @@
+package handlers
+
+import (
+	"net/http"
+
+	"backend-go/internal/middleware"
+	"backend-go/internal/models"
+	"backend-go/internal/schemas"
+	"backend-go/internal/services"
+	"backend-go/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type TripInteractionHandler struct {
+	interactionService *services.TripInteractionService
+	tracer             trace.Tracer
+}
+
+func NewTripInteractionHandler() *TripInteractionHandler {
+	return &TripInteractionHandler{
+		interactionService: services.NewTripInteractionService(),
+		tracer:             otel.Tracer("trip-interaction-handler"),
+	}
+}
+
+// React handles POST /api/v1/trips/:id/reactions
+func (h *TripInteractionHandler) React(c *gin.Context) {
+	ctx := c.Request.Context()
+	ctx, span := h.tracer.Start(ctx, "TripInteractionHandler.React")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	var req schemas.ReactToTripRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error(err)
+		BadRequest(c, "Invalid request body: "+err.Error())
+		return
+	}
+
+	tripID := c.Param("id")
+	userID, _ := middleware.GetCurrentUserID(c)
+
+	logger.Input(map[string]interface{}{
+		"tripID":   tripID,
+		"userID":   userID,
+		"reaction": req.Type,
+	})
+
+	response, err := h.interactionService.React(ctx, tripID, userID, models.InteractionAction(req.Type))
+	if err != nil {
+		logger.Error(err)
+		handleInteractionError(c, err)
+		return
+	}
+
+	logger.Output(response)
+	Success(c, http.StatusOK, response)
+}
+
+// Unreact handles DELETE /api/v1/trips/:id/reactions
+func (h *TripInteractionHandler) Unreact(c *gin.Context) {
+	ctx := c.Request.Context()
+	ctx, span := h.tracer.Start(ctx, "TripInteractionHandler.Unreact")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	tripID := c.Param("id")
+	userID, _ := middleware.GetCurrentUserID(c)
+
+	logger.Input(map[string]interface{}{
+		"tripID": tripID,
+		"userID": userID,
+	})
+
+	response, err := h.interactionService.Unreact(ctx, tripID, userID)
+	if err != nil {
+		logger.Error(err)
+		handleInteractionError(c, err)
+		return
+	}
+
+	logger.Output(response)
+	Success(c, http.StatusOK, response)
+}
+
+// Bookmark handles POST /api/v1/trips/:id/bookmark
+func (h *TripInteractionHandler) Bookmark(c *gin.Context) {
+	ctx := c.Request.Context()
+	ctx, span := h.tracer.Start(ctx, "TripInteractionHandler.Bookmark")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	tripID := c.Param("id")
+	userID, _ := middleware.GetCurrentUserID(c)
+
+	logger.Input(map[string]interface{}{
+		"tripID": tripID,
+		"userID": userID,
+	})
+
+	response, err := h.interactionService.Bookmark(ctx, tripID, userID)
+	if err != nil {
+		logger.Error(err)
+		handleInteractionError(c, err)
+		return
+	}
+
+	logger.Output(response)
+	Success(c, http.StatusOK, response)
+}
+
+// Unbookmark handles DELETE /api/v1/trips/:id/bookmark
+func (h *TripInteractionHandler) Unbookmark(c *gin.Context) {
+	ctx := c.Request.Context()
+	ctx, span := h.tracer.Start(ctx, "TripInteractionHandler.Unbookmark")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	tripID := c.Param("id")
+	userID, _ := middleware.GetCurrentUserID(c)
+
+	logger.Input(map[string]interface{}{
+		"tripID": tripID,
+		"userID": userID,
+	})
+
+	response, err := h.interactionService.Unbookmark(ctx, tripID, userID)
+	if err != nil {
+		logger.Error(err)
+		handleInteractionError(c, err)
+		return
+	}
+
+	logger.Output(response)
+	Success(c, http.StatusOK, response)
+}
+
+// RecordView handles POST /api/v1/trips/:id/views
+func (h *TripInteractionHandler) RecordView(c *gin.Context) {
+	ctx := c.Request.Context()
+	ctx, span := h.tracer.Start(ctx, "TripInteractionHandler.RecordView")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	tripID := c.Param("id")
+	userID, _ := middleware.GetCurrentUserID(c)
+
+	logger.Input(map[string]interface{}{
+		"tripID": tripID,
+		"userID": userID,
+	})
+
+	response, err := h.interactionService.RecordView(ctx, tripID, userID)
+	if err != nil {
+		logger.Error(err)
+		handleInteractionError(c, err)
+		return
+	}
+
+	logger.Output(response)
+	Success(c, http.StatusOK, response)
+}
+
+// RecordShare handles POST /api/v1/trips/:id/shares
+func (h *TripInteractionHandler) RecordShare(c *gin.Context) {
+	ctx := c.Request.Context()
+	ctx, span := h.tracer.Start(ctx, "TripInteractionHandler.RecordShare")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	tripID := c.Param("id")
+	userID, _ := middleware.GetCurrentUserID(c)
+
+	logger.Input(map[string]interface{}{
+		"tripID": tripID,
+		"userID": userID,
+	})
+
+	response, err := h.interactionService.RecordShare(ctx, tripID, userID)
+	if err != nil {
+		logger.Error(err)
+		handleInteractionError(c, err)
+		return
+	}
+
+	logger.Output(response)
+	Success(c, http.StatusOK, response)
+}
+
+// ListBookmarkedTrips handles GET /api/v1/trips/bookmarked
+func (h *TripInteractionHandler) ListBookmarkedTrips(c *gin.Context) {
+	ctx := c.Request.Context()
+	ctx, span := h.tracer.Start(ctx, "TripInteractionHandler.ListBookmarkedTrips")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	var query schemas.ListBookmarkedTripsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		logger.Error(err)
+		BadRequest(c, "Invalid query parameters: "+err.Error())
+		return
+	}
+
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		logger.Warn("User not authenticated")
+		Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	logger.Input(map[string]interface{}{
+		"userID": userID,
+		"limit":  query.Limit,
+		"offset": query.Offset,
+	})
+
+	trips, err := h.interactionService.ListBookmarkedTrips(ctx, userID, &query)
+	if err != nil {
+		logger.Error(err)
+		InternalServerError(c, err.Error())
+		return
+	}
+
+	logger.Output(map[string]interface{}{
+		"count": len(trips),
+	})
+	Success(c, http.StatusOK, gin.H{
+		"trips": trips,
+		"meta": gin.H{
+			"limit":  query.Limit,
+			"offset": query.Offset,
+			"total":  len(trips),
+		},
+	})
+}
+
+// RegisterRoutes registers interaction routes on /trips/:id
+func (h *TripInteractionHandler) RegisterRoutes(trips *gin.RouterGroup, clerkSecretKey, clerkJWTIssuerDomain string) {
+	// Views and shares are counted for anonymous visitors too
+	public := trips.Group("")
+	public.Use(
+		middleware.OptionalAuth(clerkSecretKey, clerkJWTIssuerDomain),
+		middleware.LoadTrip(),
+		middleware.RequireTripRead(),
+	)
+	{
+		public.POST("/views", h.RecordView)
+		public.POST("/shares", h.RecordShare)
+	}
+
+	authenticated := trips.Group("")
+	authenticated.Use(
+		middleware.Auth(clerkSecretKey, clerkJWTIssuerDomain),
+		middleware.LoadTrip(),
+	)
+	{
+		authenticated.POST("/reactions", middleware.RequireTripRead(), h.React)
+		authenticated.DELETE("/reactions", h.Unreact)
+		authenticated.POST("/bookmark", middleware.RequireTripRead(), h.Bookmark)
+		authenticated.DELETE("/bookmark", h.Unbookmark)
+	}
+}
+
+func handleInteractionError(c *gin.Context, err error) {
+	switch err.Error() {
+	case "trip not found":
+		NotFound(c, err.Error())
+	case "invalid IDs", "invalid reaction":
+		BadRequest(c, err.Error())
+	default:
+		InternalServerError(c, err.Error())
+	}
+}
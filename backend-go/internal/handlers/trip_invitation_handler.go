@@ -0,0 +1,395 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"backend-go/internal/middleware"
+	"backend-go/internal/models"
+	"backend-go/internal/schemas"
+	"backend-go/internal/services"
+	"backend-go/pkg/sse"
+	"backend-go/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type TripInvitationHandler struct {
+	invitationService   *services.TripInvitationService
+	notificationService *services.NotificationService
+	tracer              trace.Tracer
+}
+
+func NewTripInvitationHandler(notificationService *services.NotificationService, tripHub *sse.TripHub) *TripInvitationHandler {
+	return &TripInvitationHandler{
+		invitationService:   services.NewTripInvitationService(tripHub),
+		notificationService: notificationService,
+		tracer:              otel.Tracer("trip-invitation-handler"),
+	}
+}
+
+// GetTripInvitations handles GET /api/v1/trips/:id/invitations
+func (h *TripInvitationHandler) GetTripInvitations(c *gin.Context) {
+	ctx := c.Request.Context()
+	ctx, span := h.tracer.Start(ctx, "TripInvitationHandler.GetTripInvitations")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	tripID := c.Param("id")
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		logger.Warn("User not authenticated")
+		Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	logger.Input(map[string]interface{}{
+		"tripID": tripID,
+		"userID": userID,
+	})
+
+	invitations, err := h.invitationService.GetTripInvitations(ctx, tripID, userID)
+	if err != nil {
+		logger.Error(err)
+		handleInvitationError(c, err)
+		return
+	}
+
+	logger.Output(map[string]interface{}{
+		"count": len(invitations),
+	})
+	Success(c, http.StatusOK, gin.H{"invitations": invitations})
+}
+
+// CreateTripInvitation handles POST /api/v1/trips/:id/invitations
+func (h *TripInvitationHandler) CreateTripInvitation(c *gin.Context) {
+	ctx := c.Request.Context()
+	ctx, span := h.tracer.Start(ctx, "TripInvitationHandler.CreateTripInvitation")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	tripID := c.Param("id")
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		logger.Warn("User not authenticated")
+		Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	var req schemas.CreateTripInvitationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error(err)
+		BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	logger.Input(map[string]interface{}{
+		"tripID":  tripID,
+		"userID":  userID,
+		"request": req,
+	})
+
+	invitation, err := h.invitationService.InviteUser(ctx, tripID, userID, &req)
+	if err != nil {
+		logger.Error(err)
+		handleInvitationError(c, err)
+		return
+	}
+
+	// Notify the invited user
+	go func() {
+		h.notificationService.CreateNotification(
+			context.Background(),
+			req.UserID,
+			userID,
+			tripID,
+			models.NotificationTypeTripInvite,
+			"invited you to a trip",
+		)
+	}()
+
+	logger.Output(invitation)
+	Success(c, http.StatusCreated, invitation)
+}
+
+// CreateInviteLink handles POST /api/v1/trips/:id/invite-links
+func (h *TripInvitationHandler) CreateInviteLink(c *gin.Context) {
+	ctx := c.Request.Context()
+	ctx, span := h.tracer.Start(ctx, "TripInvitationHandler.CreateInviteLink")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	tripID := c.Param("id")
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		logger.Warn("User not authenticated")
+		Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	var req schemas.CreateInviteLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error(err)
+		BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	logger.Input(map[string]interface{}{
+		"tripID":  tripID,
+		"userID":  userID,
+		"request": req,
+	})
+
+	invitation, err := h.invitationService.CreateInviteLink(ctx, tripID, userID, &req)
+	if err != nil {
+		logger.Error(err)
+		handleInvitationError(c, err)
+		return
+	}
+
+	logger.Output(map[string]interface{}{
+		"invitationID": invitation.ID.Hex(),
+	})
+	Success(c, http.StatusCreated, invitation)
+}
+
+// RevokeInvitation handles DELETE /api/v1/trips/:id/invitations/:invitationId
+func (h *TripInvitationHandler) RevokeInvitation(c *gin.Context) {
+	ctx := c.Request.Context()
+	ctx, span := h.tracer.Start(ctx, "TripInvitationHandler.RevokeInvitation")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	tripID := c.Param("id")
+	invitationID := c.Param("invitationId")
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		logger.Warn("User not authenticated")
+		Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	logger.Input(map[string]interface{}{
+		"tripID":       tripID,
+		"invitationID": invitationID,
+		"userID":       userID,
+	})
+
+	if err := h.invitationService.RevokeInvitation(ctx, tripID, invitationID, userID); err != nil {
+		logger.Error(err)
+		handleInvitationError(c, err)
+		return
+	}
+
+	logger.Info("Invitation revoked successfully")
+	Success(c, http.StatusOK, gin.H{
+		"message": "Invitation revoked successfully",
+		"id":      invitationID,
+	})
+}
+
+// GetMyInvitations handles GET /api/v1/invitations
+func (h *TripInvitationHandler) GetMyInvitations(c *gin.Context) {
+	ctx := c.Request.Context()
+	ctx, span := h.tracer.Start(ctx, "TripInvitationHandler.GetMyInvitations")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		logger.Warn("User not authenticated")
+		Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	logger.Input(map[string]interface{}{
+		"userID": userID,
+	})
+
+	invitations, err := h.invitationService.GetMyInvitations(ctx, userID)
+	if err != nil {
+		logger.Error(err)
+		InternalServerError(c, err.Error())
+		return
+	}
+
+	logger.Output(map[string]interface{}{
+		"count": len(invitations),
+	})
+	Success(c, http.StatusOK, gin.H{"invitations": invitations})
+}
+
+// AcceptInvitation handles POST /api/v1/invitations/:invitationId/accept
+func (h *TripInvitationHandler) AcceptInvitation(c *gin.Context) {
+	ctx := c.Request.Context()
+	ctx, span := h.tracer.Start(ctx, "TripInvitationHandler.AcceptInvitation")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	invitationID := c.Param("invitationId")
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		logger.Warn("User not authenticated")
+		Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	logger.Input(map[string]interface{}{
+		"invitationID": invitationID,
+		"userID":       userID,
+	})
+
+	invitation, err := h.invitationService.AcceptInvitation(ctx, invitationID, userID)
+	if err != nil {
+		logger.Error(err)
+		handleInvitationError(c, err)
+		return
+	}
+
+	// Notify the inviter that the invitation was accepted
+	go func() {
+		h.notificationService.CreateNotification(
+			context.Background(),
+			invitation.InvitedBy.Hex(),
+			userID,
+			invitation.TripID.Hex(),
+			models.NotificationTypeInviteAccepted,
+			"accepted your trip invitation",
+		)
+	}()
+
+	logger.Output(invitation)
+	Success(c, http.StatusOK, invitation)
+}
+
+// DeclineInvitation handles POST /api/v1/invitations/:invitationId/decline
+func (h *TripInvitationHandler) DeclineInvitation(c *gin.Context) {
+	ctx := c.Request.Context()
+	ctx, span := h.tracer.Start(ctx, "TripInvitationHandler.DeclineInvitation")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	invitationID := c.Param("invitationId")
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		logger.Warn("User not authenticated")
+		Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	logger.Input(map[string]interface{}{
+		"invitationID": invitationID,
+		"userID":       userID,
+	})
+
+	invitation, err := h.invitationService.DeclineInvitation(ctx, invitationID, userID)
+	if err != nil {
+		logger.Error(err)
+		handleInvitationError(c, err)
+		return
+	}
+
+	// Notify the inviter that the invitation was declined
+	go func() {
+		h.notificationService.CreateNotification(
+			context.Background(),
+			invitation.InvitedBy.Hex(),
+			userID,
+			invitation.TripID.Hex(),
+			models.NotificationTypeInviteDeclined,
+			"declined your trip invitation",
+		)
+	}()
+
+	logger.Output(invitation)
+	Success(c, http.StatusOK, invitation)
+}
+
+// JoinByToken handles POST /api/v1/invitations/join/:token
+func (h *TripInvitationHandler) JoinByToken(c *gin.Context) {
+	ctx := c.Request.Context()
+	ctx, span := h.tracer.Start(ctx, "TripInvitationHandler.JoinByToken")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	token := c.Param("token")
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		logger.Warn("User not authenticated")
+		Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	logger.Input(map[string]interface{}{
+		"userID": userID,
+	})
+
+	invitation, err := h.invitationService.JoinByToken(ctx, token, userID)
+	if err != nil {
+		logger.Error(err)
+		handleInvitationError(c, err)
+		return
+	}
+
+	// Notify the trip owner that someone joined via link
+	go func() {
+		h.notificationService.CreateNotification(
+			context.Background(),
+			invitation.InvitedBy.Hex(),
+			userID,
+			invitation.TripID.Hex(),
+			models.NotificationTypeMemberJoined,
+			"joined your trip via invite link",
+		)
+	}()
+
+	logger.Output(invitation)
+	Success(c, http.StatusOK, invitation)
+}
+
+// handleInvitationError maps invitation service errors to HTTP responses
+func handleInvitationError(c *gin.Context, err error) {
+	switch {
+	case err.Error() == "trip not found" || err.Error() == "invitation not found" || err.Error() == "user not found":
+		NotFound(c, err.Error())
+	case strings.HasPrefix(err.Error(), "unauthorized"):
+		Forbidden(c, err.Error())
+	case err.Error() == "user is already a member" || err.Error() == "user already has a pending invitation":
+		Error(c, http.StatusConflict, err.Error())
+	case err.Error() == "invitation is no longer valid" || err.Error() == "invitation is no longer pending":
+		Error(c, http.StatusGone, err.Error())
+	default:
+		InternalServerError(c, err.Error())
+	}
+}
+
+// RegisterTripInvitationRoutes registers owner-only invitation routes under /trips/:id
+func (h *TripInvitationHandler) RegisterTripInvitationRoutes(trips *gin.RouterGroup, clerkSecretKey, clerkJWTIssuerDomain string) {
+	authenticated := trips.Group("")
+	authenticated.Use(
+		middleware.Auth(clerkSecretKey, clerkJWTIssuerDomain),
+		middleware.LoadTrip(),
+		middleware.RequireTripRole(models.MemberRoleOwner),
+	)
+	{
+		authenticated.GET("/invitations", h.GetTripInvitations)
+		authenticated.POST("/invitations", h.CreateTripInvitation)
+		authenticated.DELETE("/invitations/:invitationId", h.RevokeInvitation)
+		authenticated.POST("/invite-links", h.CreateInviteLink)
+	}
+}
+
+// RegisterInvitationRoutes registers invitee routes under /invitations
+func (h *TripInvitationHandler) RegisterInvitationRoutes(v1 *gin.RouterGroup, clerkSecretKey, clerkJWTIssuerDomain string) {
+	invitations := v1.Group("/invitations")
+	invitations.Use(middleware.Auth(clerkSecretKey, clerkJWTIssuerDomain))
+	{
+		invitations.GET("", h.GetMyInvitations)
+		invitations.POST("/:invitationId/accept", h.AcceptInvitation)
+		invitations.POST("/:invitationId/decline", h.DeclineInvitation)
+		invitations.POST("/join/:token", h.JoinByToken)
+	}
+}
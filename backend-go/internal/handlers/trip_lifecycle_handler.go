@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"net/http"
+
+	"backend-go/internal/config"
+	"backend-go/internal/middleware"
+	"backend-go/internal/models"
+	"backend-go/internal/schemas"
+	"backend-go/internal/services"
+	"backend-go/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type TripLifecycleHandler struct {
+	lifecycleService *services.TripLifecycleService
+	tracer           trace.Tracer
+}
+
+func NewTripLifecycleHandler(cfg *config.LifecycleConfig) *TripLifecycleHandler {
+	return &TripLifecycleHandler{
+		lifecycleService: services.NewTripLifecycleService(cfg),
+		tracer:           otel.Tracer("trip-lifecycle-handler"),
+	}
+}
+
+// ArchiveTrip handles POST /api/v1/trips/:id/archive
+func (h *TripLifecycleHandler) ArchiveTrip(c *gin.Context) {
+	ctx := c.Request.Context()
+	ctx, span := h.tracer.Start(ctx, "TripLifecycleHandler.ArchiveTrip")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	tripID := c.Param("id")
+	userID, _ := middleware.GetCurrentUserID(c)
+
+	logger.Input(map[string]interface{}{
+		"tripID": tripID,
+		"userID": userID,
+	})
+
+	trip, err := h.lifecycleService.ArchiveTrip(ctx, tripID, userID)
+	if err != nil {
+		logger.Error(err)
+		handleLifecycleError(c, err)
+		return
+	}
+
+	logger.Output(trip)
+	Success(c, http.StatusOK, trip)
+}
+
+// UnarchiveTrip handles POST /api/v1/trips/:id/unarchive
+func (h *TripLifecycleHandler) UnarchiveTrip(c *gin.Context) {
+	ctx := c.Request.Context()
+	ctx, span := h.tracer.Start(ctx, "TripLifecycleHandler.UnarchiveTrip")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	tripID := c.Param("id")
+	userID, _ := middleware.GetCurrentUserID(c)
+
+	logger.Input(map[string]interface{}{
+		"tripID": tripID,
+		"userID": userID,
+	})
+
+	trip, err := h.lifecycleService.UnarchiveTrip(ctx, tripID, userID)
+	if err != nil {
+		logger.Error(err)
+		handleLifecycleError(c, err)
+		return
+	}
+
+	logger.Output(trip)
+	Success(c, http.StatusOK, trip)
+}
+
+// PublishTrip handles POST /api/v1/trips/:id/publish
+// Body: {"publishAt": "2026-01-01T09:00:00Z"} schedules, an empty body publishes now
+func (h *TripLifecycleHandler) PublishTrip(c *gin.Context) {
+	ctx := c.Request.Context()
+	ctx, span := h.tracer.Start(ctx, "TripLifecycleHandler.PublishTrip")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	var req schemas.PublishTripRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			logger.Error(err)
+			BadRequest(c, "Invalid request body: "+err.Error())
+			return
+		}
+	}
+
+	tripID := c.Param("id")
+	userID, _ := middleware.GetCurrentUserID(c)
+
+	logger.Input(map[string]interface{}{
+		"tripID":    tripID,
+		"userID":    userID,
+		"publishAt": req.PublishAt,
+	})
+
+	trip, err := h.lifecycleService.PublishTrip(ctx, tripID, userID, &req)
+	if err != nil {
+		logger.Error(err)
+		handleLifecycleError(c, err)
+		return
+	}
+
+	logger.Output(trip)
+	Success(c, http.StatusOK, trip)
+}
+
+// CancelScheduledPublish handles DELETE /api/v1/trips/:id/publish-schedule
+func (h *TripLifecycleHandler) CancelScheduledPublish(c *gin.Context) {
+	ctx := c.Request.Context()
+	ctx, span := h.tracer.Start(ctx, "TripLifecycleHandler.CancelScheduledPublish")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	tripID := c.Param("id")
+	userID, _ := middleware.GetCurrentUserID(c)
+
+	logger.Input(map[string]interface{}{
+		"tripID": tripID,
+		"userID": userID,
+	})
+
+	trip, err := h.lifecycleService.CancelScheduledPublish(ctx, tripID, userID)
+	if err != nil {
+		logger.Error(err)
+		handleLifecycleError(c, err)
+		return
+	}
+
+	logger.Output(trip)
+	Success(c, http.StatusOK, trip)
+}
+
+// RegisterRoutes registers lifecycle routes on /trips/:id, owner only
+func (h *TripLifecycleHandler) RegisterRoutes(trips *gin.RouterGroup, clerkSecretKey, clerkJWTIssuerDomain string) {
+	owner := trips.Group("")
+	owner.Use(
+		middleware.Auth(clerkSecretKey, clerkJWTIssuerDomain),
+		middleware.LoadTrip(),
+		middleware.RequireTripRole(models.MemberRoleOwner),
+	)
+	{
+		owner.POST("/archive", h.ArchiveTrip)
+		owner.POST("/unarchive", h.UnarchiveTrip)
+		owner.POST("/publish", h.PublishTrip)
+		owner.DELETE("/publish-schedule", h.CancelScheduledPublish)
+	}
+}
+
+func handleLifecycleError(c *gin.Context, err error) {
+	switch err.Error() {
+	case "trip not found", "trip has no scheduled publish":
+		NotFound(c, err.Error())
+	case "unauthorized: you don't own this trip":
+		Forbidden(c, err.Error())
+	case "trip is archived", "trip is already archived", "trip is not archived", "trip is already published":
+		Error(c, http.StatusConflict, err.Error())
+	default:
+		InternalServerError(c, err.Error())
+	}
+}
@@ -1,13 +1,15 @@
 package handlers
 
 import (
-	"context"
+	"errors"
 	"net/http"
+	"strings"
 
 	"backend-go/internal/middleware"
 	"backend-go/internal/models"
 	"backend-go/internal/schemas"
 	"backend-go/internal/services"
+	"backend-go/pkg/sse"
 	"backend-go/pkg/utils"
 
 	"github.com/gin-gonic/gin"
@@ -16,16 +18,14 @@ import (
 )
 
 type TripMemberHandler struct {
-	tripService         *services.TripService
-	notificationService *services.NotificationService
-	tracer              trace.Tracer
+	tripService *services.TripService
+	tracer      trace.Tracer
 }
 
-func NewTripMemberHandler(notificationService *services.NotificationService) *TripMemberHandler {
+func NewTripMemberHandler(tripHub *sse.TripHub) *TripMemberHandler {
 	return &TripMemberHandler{
-		tripService:         services.NewTripService(),
-		notificationService: notificationService,
-		tracer:              otel.Tracer("trip-member-handler"),
+		tripService: services.NewTripService(tripHub),
+		tracer:      otel.Tracer("trip-member-handler"),
 	}
 }
 
@@ -61,72 +61,19 @@ func (h *TripMemberHandler) GetTripMembers(c *gin.Context) {
 	logger.Output(map[string]interface{}{
 		"count": len(members),
 	})
+	// Members are embedded in the trip, member edits send the trip ETag back in If-Match
+	if trip, ok := middleware.GetCurrentTrip(c); ok {
+		SetETag(c, trip.Version)
+	}
 	Success(c, http.StatusOK, gin.H{"members": members})
 }
 
 // AddTripMember handles POST /api/v1/trips/:id/members
+// Members join through invitations, so this permanently redirects to POST /api/v1/trips/:id/invitations
+// (308 keeps the method and body) and the invitation route does the checks.
 func (h *TripMemberHandler) AddTripMember(c *gin.Context) {
-	ctx := c.Request.Context()
-	ctx, span := h.tracer.Start(ctx, "TripMemberHandler.AddTripMember")
-	defer span.End()
-	logger := utils.NewTraceLogger(ctx, span)
-
-	tripID := c.Param("id")
-	if tripID == "" {
-		logger.Warn("Trip ID is required")
-		BadRequest(c, "Trip ID is required")
-		return
-	}
-
-	userID, exists := middleware.GetCurrentUserID(c)
-	if !exists {
-		logger.Warn("User not authenticated")
-		Unauthorized(c, "User not authenticated")
-		return
-	}
-
-	var req schemas.AddTripMemberRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		logger.Error(err)
-		BadRequest(c, "Invalid request: "+err.Error())
-		return
-	}
-
-	logger.Input(map[string]interface{}{
-		"tripID":  tripID,
-		"userID":  userID,
-		"request": req,
-	})
-
-	member, err := h.tripService.AddTripMember(ctx, tripID, userID, &req)
-	if err != nil {
-		logger.Error(err)
-		if err.Error() == "trip not found" {
-			NotFound(c, err.Error())
-			return
-		}
-		if err.Error() == "unauthorized: only owner can add members" {
-			Forbidden(c, err.Error())
-			return
-		}
-		InternalServerError(c, err.Error())
-		return
-	}
-
-	// Send notification to the added member
-	go func() {
-		h.notificationService.CreateNotification(
-			context.Background(),
-			req.UserID,
-			userID,
-			tripID,
-			models.NotificationTypeTripInvite,
-			"added you to a trip",
-		)
-	}()
-
-	logger.Output(member)
-	Success(c, http.StatusCreated, member)
+	target := strings.TrimSuffix(c.Request.URL.Path, "/members") + "/invitations"
+	c.Redirect(http.StatusPermanentRedirect, target)
 }
 
 // UpdateTripMember handles PATCH /api/v1/trips/:id/members/:memberId
@@ -165,9 +112,13 @@ func (h *TripMemberHandler) UpdateTripMember(c *gin.Context) {
 		"request":  req,
 	})
 
-	member, err := h.tripService.UpdateTripMember(ctx, tripID, memberID, userID, &req)
+	member, err := h.tripService.UpdateTripMember(ctx, tripID, memberID, userID, &req, middleware.GetIfMatchVersion(c))
 	if err != nil {
 		logger.Error(err)
+		if errors.Is(err, services.ErrVersionConflict) {
+			respondTripConflict(ctx, c, h.tripService, tripID, userID)
+			return
+		}
 		if err.Error() == "trip not found" || err.Error() == "member not found" {
 			NotFound(c, err.Error())
 			return
@@ -212,9 +163,13 @@ func (h *TripMemberHandler) DeleteTripMember(c *gin.Context) {
 		"userID":   userID,
 	})
 
-	err := h.tripService.DeleteTripMember(ctx, tripID, memberID, userID)
+	err := h.tripService.DeleteTripMember(ctx, tripID, memberID, userID, middleware.GetIfMatchVersion(c))
 	if err != nil {
 		logger.Error(err)
+		if errors.Is(err, services.ErrVersionConflict) {
+			respondTripConflict(ctx, c, h.tripService, tripID, userID)
+			return
+		}
 		if err.Error() == "trip not found" || err.Error() == "member not found" {
 			NotFound(c, err.Error())
 			return
@@ -236,13 +191,16 @@ func (h *TripMemberHandler) DeleteTripMember(c *gin.Context) {
 
 // RegisterRoutes registers trip member routes
 func (h *TripMemberHandler) RegisterRoutes(trips *gin.RouterGroup, clerkSecretKey, clerkJWTIssuerDomain string) {
-	// All member routes require authentication
+	// All member routes require authentication, only the owner can manage members
 	authenticated := trips.Group("/members")
-	authenticated.Use(middleware.Auth(clerkSecretKey, clerkJWTIssuerDomain))
+	authenticated.Use(
+		middleware.Auth(clerkSecretKey, clerkJWTIssuerDomain),
+		middleware.LoadTrip(),
+	)
 	{
-		authenticated.GET("", h.GetTripMembers)
+		authenticated.GET("", middleware.RequireTripRead(), h.GetTripMembers)
 		authenticated.POST("", h.AddTripMember)
-		authenticated.PATCH("/:memberId", h.UpdateTripMember)
-		authenticated.DELETE("/:memberId", h.DeleteTripMember)
+		authenticated.PATCH("/:memberId", middleware.RequireTripRole(models.MemberRoleOwner), middleware.RequireIfMatch(), h.UpdateTripMember)
+		authenticated.DELETE("/:memberId", middleware.RequireTripRole(models.MemberRoleOwner), middleware.RequireIfMatch(), h.DeleteTripMember)
 	}
 }
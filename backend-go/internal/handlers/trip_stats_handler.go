@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"net/http"
+
+	"backend-go/internal/middleware"
+	"backend-go/internal/services"
+	"backend-go/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type TripStatsHandler struct {
+	statsService *services.TripStatsService
+	tracer       trace.Tracer
+}
+
+func NewTripStatsHandler() *TripStatsHandler {
+	return &TripStatsHandler{
+		statsService: services.NewTripStatsService(),
+		tracer:       otel.Tracer("trip-stats-handler"),
+	}
+}
+
+// GetTripStats handles GET /api/v1/trips/:id/stats
+func (h *TripStatsHandler) GetTripStats(c *gin.Context) {
+	ctx := c.Request.Context()
+	ctx, span := h.tracer.Start(ctx, "TripStatsHandler.GetTripStats")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	tripID := c.Param("id")
+
+	logger.Input(map[string]interface{}{
+		"tripID": tripID,
+	})
+
+	stats, err := h.statsService.GetTripStats(ctx, tripID)
+	if err != nil {
+		logger.Error(err)
+		if err.Error() == "trip not found" {
+			NotFound(c, err.Error())
+			return
+		}
+		InternalServerError(c, err.Error())
+		return
+	}
+
+	logger.Output(map[string]interface{}{
+		"days":    stats.DayCount,
+		"entries": stats.EntryCount,
+	})
+	Success(c, http.StatusOK, stats)
+}
+
+// RegisterRoutes registers stats routes on /trips/:id, readable by anyone who can view the trip
+func (h *TripStatsHandler) RegisterRoutes(trips *gin.RouterGroup, clerkSecretKey, clerkJWTIssuerDomain string) {
+	trips.GET("/stats",
+		middleware.OptionalAuth(clerkSecretKey, clerkJWTIssuerDomain),
+		middleware.LoadTrip(),
+		middleware.RequireTripRead(),
+		h.GetTripStats,
+	)
+}
@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"net/http"
+
+	"backend-go/internal/config"
+	"backend-go/internal/middleware"
+	"backend-go/internal/schemas"
+	"backend-go/internal/services"
+	"backend-go/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type TripTrashHandler struct {
+	trashService *services.TripTrashService
+	tracer       trace.Tracer
+}
+
+func NewTripTrashHandler(cfg *config.LifecycleConfig) *TripTrashHandler {
+	return &TripTrashHandler{
+		trashService: services.NewTripTrashService(cfg),
+		tracer:       otel.Tracer("trip-trash-handler"),
+	}
+}
+
+// ListTrash handles GET /api/v1/trips/trash
+func (h *TripTrashHandler) ListTrash(c *gin.Context) {
+	ctx := c.Request.Context()
+	ctx, span := h.tracer.Start(ctx, "TripTrashHandler.ListTrash")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	var query schemas.ListTrashQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		logger.Error(err)
+		BadRequest(c, "Invalid query parameters: "+err.Error())
+		return
+	}
+
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		logger.Warn("User not authenticated")
+		Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	logger.Input(map[string]interface{}{
+		"userID": userID,
+		"limit":  query.Limit,
+		"offset": query.Offset,
+	})
+
+	trips, err := h.trashService.ListTrash(ctx, userID, &query)
+	if err != nil {
+		logger.Error(err)
+		InternalServerError(c, err.Error())
+		return
+	}
+
+	logger.Output(map[string]interface{}{
+		"count": len(trips),
+	})
+	Success(c, http.StatusOK, gin.H{
+		"trips": trips,
+		"meta": gin.H{
+			"limit":         query.Limit,
+			"offset":        query.Offset,
+			"total":         len(trips),
+			"retentionDays": h.trashService.RetentionDays(),
+		},
+	})
+}
+
+// RestoreTrip handles POST /api/v1/trips/:id/restore
+func (h *TripTrashHandler) RestoreTrip(c *gin.Context) {
+	ctx := c.Request.Context()
+	ctx, span := h.tracer.Start(ctx, "TripTrashHandler.RestoreTrip")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	tripID := c.Param("id")
+	userID, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		logger.Warn("User not authenticated")
+		Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	logger.Input(map[string]interface{}{
+		"tripID": tripID,
+		"userID": userID,
+	})
+
+	trip, err := h.trashService.RestoreTrip(ctx, tripID, userID)
+	if err != nil {
+		logger.Error(err)
+		switch err.Error() {
+		case "trip not found":
+			NotFound(c, err.Error())
+		case "unauthorized: you don't own this trip":
+			Forbidden(c, err.Error())
+		default:
+			InternalServerError(c, err.Error())
+		}
+		return
+	}
+
+	logger.Output(trip)
+	Success(c, http.StatusOK, trip)
+}
+
+// RegisterRoutes registers the restore route on /trips/:id
+// LoadTrip is left out on purpose, it only finds trips that aren't deleted
+func (h *TripTrashHandler) RegisterRoutes(trips *gin.RouterGroup, clerkSecretKey, clerkJWTIssuerDomain string) {
+	trips.POST("/restore", middleware.Auth(clerkSecretKey, clerkJWTIssuerDomain), h.RestoreTrip)
+}
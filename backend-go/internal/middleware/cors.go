@@ -48,6 +48,9 @@ func CORS(cfg *config.CORSConfig) gin.HandlerFunc {
 		}
 		c.Writer.Header().Set("Access-Control-Allow-Headers", headers)
 
+		// Let browsers read the ETag needed for If-Match on later writes
+		c.Writer.Header().Set("Access-Control-Expose-Headers", "ETag")
+
 		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
 		c.Writer.Header().Set("Access-Control-Max-Age", "86400")
 
@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireIfMatch returns a middleware that rejects writes without an If-Match header
+// The header carries the version from the resource ETag, "*" matches any version.
+func RequireIfMatch() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetHeader("If-Match") == "" {
+			abortWithError(c, http.StatusPreconditionRequired, "If-Match header is required")
+			return
+		}
+
+		if _, ok := parseIfMatch(c.GetHeader("If-Match")); !ok {
+			abortWithError(c, http.StatusBadRequest, "Invalid If-Match header, expected the resource ETag")
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// GetIfMatchVersion returns the version the client expects the resource to have
+// Nil means no check, either the header is absent or it is "*".
+func GetIfMatchVersion(c *gin.Context) *int64 {
+	version, ok := parseIfMatch(c.GetHeader("If-Match"))
+	if !ok {
+		return nil
+	}
+	return version
+}
+
+// parseIfMatch parses an ETag like "3" or W/"3", or "*" which yields a nil version
+func parseIfMatch(header string) (*int64, bool) {
+	value := strings.TrimSpace(header)
+	if value == "" {
+		return nil, false
+	}
+	if value == "*" {
+		return nil, true
+	}
+
+	value = strings.TrimPrefix(value, "W/")
+	if len(value) < 2 || !strings.HasPrefix(value, `"`) || !strings.HasSuffix(value, `"`) {
+		return nil, false
+	}
+
+	version, err := strconv.ParseInt(value[1:len(value)-1], 10, 64)
+	if err != nil || version < 0 {
+		return nil, false
+	}
+	return &version, true
+}
@@ -0,0 +1,177 @@
+package middleware
+
+import (
+	"net/http"
+
+	"backend-go/internal/models"
+	"backend-go/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TripAccessMiddleware struct {
+	tripRepository      *repository.TripRepository
+	itineraryRepository *repository.ItineraryRepository
+	entryRepository     *repository.ItineraryEntryRepository
+	expenseRepository   *repository.ExpenseRepository
+	documentRepository  *repository.TripDocumentRepository
+}
+
+func NewTripAccessMiddleware() *TripAccessMiddleware {
+	return &TripAccessMiddleware{
+		tripRepository:      repository.NewTripRepository(),
+		itineraryRepository: repository.NewItineraryRepository(),
+		entryRepository:     repository.NewItineraryEntryRepository(),
+		expenseRepository:   repository.NewExpenseRepository(),
+		documentRepository:  repository.NewTripDocumentRepository(),
+	}
+}
+
+// LoadTrip returns a middleware that loads the trip from the :id path parameter once per request
+// and verifies that nested :itineraryId, :entryId, :expenseId and :documentId parameters belong to it
+func LoadTrip() gin.HandlerFunc {
+	return NewTripAccessMiddleware().LoadTrip()
+}
+
+// RequireTripRole returns a middleware that only allows trip members with one of the given roles
+// This middleware must be used AFTER Auth and LoadTrip middleware
+func RequireTripRole(roles ...string) gin.HandlerFunc {
+	return NewTripAccessMiddleware().RequireRole(roles...)
+}
+
+// RequireTripRead returns a middleware that hides draft and private trips from non-members
+// This middleware must be used AFTER OptionalAuth (or Auth) and LoadTrip middleware
+func RequireTripRead() gin.HandlerFunc {
+	return NewTripAccessMiddleware().RequireRead()
+}
+
+// LoadTrip loads the trip and sets it in context together with the caller's trip role
+func (m *TripAccessMiddleware) LoadTrip() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+
+		trip, err := m.tripRepository.FindByID(ctx, c.Param("id"))
+		if err != nil {
+			abortWithError(c, http.StatusNotFound, "Trip not found")
+			return
+		}
+
+		// Nested resources must belong to this trip, otherwise a member of one trip
+		// could modify another trip's data through their own trip's URL
+		if itineraryID := c.Param("itineraryId"); itineraryID != "" {
+			itinerary, err := m.itineraryRepository.FindByID(ctx, itineraryID)
+			if err != nil || itinerary.TripID != trip.ID {
+				abortWithError(c, http.StatusNotFound, "Itinerary not found")
+				return
+			}
+
+			if entryID := c.Param("entryId"); entryID != "" {
+				entry, err := m.entryRepository.FindByID(ctx, entryID)
+				if err != nil || entry.ItineraryID != itinerary.ID {
+					abortWithError(c, http.StatusNotFound, "Entry not found")
+					return
+				}
+			}
+		}
+
+		if expenseID := c.Param("expenseId"); expenseID != "" {
+			expense, err := m.expenseRepository.FindByID(ctx, expenseID)
+			if err != nil || expense.TripID != trip.ID {
+				abortWithError(c, http.StatusNotFound, "Expense not found")
+				return
+			}
+		}
+
+		if documentID := c.Param("documentId"); documentID != "" {
+			document, err := m.documentRepository.FindByID(ctx, documentID)
+			if err != nil || document.TripID != trip.ID {
+				abortWithError(c, http.StatusNotFound, "Document not found")
+				return
+			}
+		}
+
+		c.Set("trip", trip)
+		if userID, exists := GetCurrentUserID(c); exists {
+			if role := m.tripRepository.GetMemberRole(trip, userID); role != "" {
+				c.Set("tripRole", role)
+			}
+		}
+
+		c.Next()
+	}
+}
+
+// RequireRole aborts with 403 unless the caller has one of the given roles in the loaded trip
+func (m *TripAccessMiddleware) RequireRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, exists := GetCurrentUserID(c); !exists {
+			abortWithError(c, http.StatusUnauthorized, "Unauthorized: User not authenticated")
+			return
+		}
+
+		role, _ := GetCurrentTripRole(c)
+		for _, allowed := range roles {
+			if role == allowed {
+				c.Next()
+				return
+			}
+		}
+
+		abortWithError(c, http.StatusForbidden, "Forbidden: insufficient trip permissions")
+	}
+}
+
+// RequireRead aborts with 404 when the caller can't view the loaded trip
+// Unlisted trips pass because reaching them by ID is the direct link
+func (m *TripAccessMiddleware) RequireRead() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		trip, exists := GetCurrentTrip(c)
+		if !exists {
+			abortWithError(c, http.StatusNotFound, "Trip not found")
+			return
+		}
+
+		userID, _ := GetCurrentUserID(c)
+		if !m.tripRepository.CanView(trip, userID) {
+			// Respond with 404 so hidden trips don't leak their existence
+			abortWithError(c, http.StatusNotFound, "Trip not found")
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// GetCurrentTrip retrieves the trip loaded by LoadTrip from context
+func GetCurrentTrip(c *gin.Context) (*models.Trip, bool) {
+	trip, exists := c.Get("trip")
+	if !exists {
+		return nil, false
+	}
+
+	t, ok := trip.(*models.Trip)
+	return t, ok
+}
+
+// GetCurrentTripRole retrieves the caller's role in the loaded trip from context
+func GetCurrentTripRole(c *gin.Context) (string, bool) {
+	role, exists := c.Get("tripRole")
+	if !exists {
+		return "", false
+	}
+
+	r, ok := role.(string)
+	return r, ok
+}
+
+// abortWithError writes the standard error envelope and aborts the request
+func abortWithError(c *gin.Context, code int, message string) {
+	c.JSON(code, gin.H{
+		"status": "error",
+		"error": gin.H{
+			"code":    code,
+			"message": message,
+		},
+	})
+	c.Abort()
+}
@@ -2,6 +2,8 @@ package models
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/kamva/mgm/v3"
@@ -25,8 +27,27 @@ type ItineraryEntry struct {
 	Photos      []string            `bson:"photos,omitempty" json:"photos,omitempty"`
 	Order       int                 `bson:"order" json:"order"`                     // Order within the day
 	Todos       []Todo              `bson:"todos,omitempty" json:"todos,omitempty"` // Embedded todos
+	Version     int64               `bson:"version" json:"version"`                 // Bumped on every update, sent as the ETag
+	Warnings    []ScheduleWarning   `bson:"-" json:"warnings,omitempty"`            // Schedule problems, returned on create and update
 }
 
+// ScheduleWarning is a problem with a day's schedule, it never blocks saving
+type ScheduleWarning struct {
+	Type         string `json:"type"`
+	ItineraryID  string `json:"itineraryId"`
+	EntryID      string `json:"entryId,omitempty"`
+	OtherEntryID string `json:"otherEntryId,omitempty"` // The entry overlapped with
+	Message      string `json:"message"`
+}
+
+// Types of ScheduleWarning
+const (
+	ScheduleWarningOverlap       = "overlap"
+	ScheduleWarningClosed        = "outside_opening_hours"
+	ScheduleWarningEndBeforeStart = "end_before_start"
+	ScheduleWarningDayTooLong    = "day_too_long"
+)
+
 // MarshalJSON customizes JSON marshaling to map MongoDB _id to id and use camelCase
 func (e ItineraryEntry) MarshalJSON() ([]byte, error) {
 	type Alias ItineraryEntry
@@ -77,6 +98,52 @@ type Todo struct {
 	Order     int    `bson:"order" json:"order"`
 }
 
+// ParseClock parses an "HH:MM" time into minutes since midnight
+func ParseClock(value string) (int, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, errors.New("invalid time format, expected HH:MM")
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// FormatClock formats minutes since midnight as "HH:MM" (wrapping past midnight)
+func FormatClock(minutes int) string {
+	minutes = ((minutes % 1440) + 1440) % 1440
+	return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60)
+}
+
+// Schedule returns the entry start and end as minutes since midnight of its day
+// End falls back to StartTime + Duration, or equals start when neither is known.
+// An EndTime earlier than StartTime is treated as ending after midnight.
+// ok is false when the entry has no valid StartTime.
+func (e *ItineraryEntry) Schedule() (start, end int, ok bool) {
+	if e.StartTime == nil || *e.StartTime == "" {
+		return 0, 0, false
+	}
+
+	start, err := ParseClock(*e.StartTime)
+	if err != nil {
+		return 0, 0, false
+	}
+
+	end = start
+	if e.EndTime != nil && *e.EndTime != "" {
+		if parsed, err := ParseClock(*e.EndTime); err == nil {
+			end = parsed
+			if end < start {
+				end += 1440
+			}
+			return start, end, true
+		}
+	}
+	if e.Duration != nil && *e.Duration > 0 {
+		end = start + *e.Duration
+	}
+
+	return start, end, true
+}
+
 // CollectionName returns the collection name for ItineraryEntry
 func (e *ItineraryEntry) CollectionName() string {
 	return "itinerary_entries"
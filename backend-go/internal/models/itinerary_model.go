@@ -17,9 +17,32 @@ type Itinerary struct {
 	Date      string             `bson:"date" json:"date"`            // ISO date format YYYY-MM-DD
 	Title     string             `bson:"title" json:"title"`          // e.g., "Day 1: Exploring Bangkok"
 	Order     int                `bson:"order" json:"order"`          // For sorting days
+	Unscheduled bool             `bson:"unscheduled,omitempty" json:"unscheduled,omitempty"` // Holding bucket for entries from days removed by shortening the trip, has no date or day number
+	Version   int64              `bson:"version" json:"version"`      // Bumped on every update, sent as the ETag
 	Entries   []*ItineraryEntry  `bson:"-" json:"entries,omitempty"` // Populated when queried, not stored in DB
+	Legs      []*TravelLeg       `bson:"-" json:"legs,omitempty"`    // Travel between consecutive place entries, computed when listed
 }
 
+// TravelLeg estimates getting from one place entry to the next place entry of the same day
+type TravelLeg struct {
+	FromEntryID    string   `json:"fromEntryId"`
+	ToEntryID      string   `json:"toEntryId"`
+	DistanceKm     float64  `json:"distanceKm"` // Straight-line distance
+	WalkMinutes    int      `json:"walkMinutes"`
+	TransitMinutes int      `json:"transitMinutes"`
+	DriveMinutes   int      `json:"driveMinutes"`
+	GapMinutes     *int     `json:"gapMinutes,omitempty"`  // From the first entry's end to the next one's start, when both are scheduled
+	TooShort       bool     `json:"tooShort"`              // The gap is shorter than even the fastest estimate
+	TooShortFor    []string `json:"tooShortFor,omitempty"` // Travel modes that don't fit in the gap
+}
+
+// Travel modes of a TravelLeg
+const (
+	TravelModeWalk    = "walk"
+	TravelModeTransit = "transit"
+	TravelModeDrive   = "drive"
+)
+
 // MarshalJSON customizes JSON marshaling to map MongoDB _id to id and use camelCase
 func (i Itinerary) MarshalJSON() ([]byte, error) {
 	type Alias Itinerary
@@ -14,6 +14,9 @@ const (
 	NotificationTypeCommentReply NotificationType = "comment_reply"
 	NotificationTypeMemberJoined NotificationType = "member_joined"
 	NotificationTypeLike         NotificationType = "like"
+
+	NotificationTypeInviteAccepted NotificationType = "invite_accepted"
+	NotificationTypeInviteDeclined NotificationType = "invite_declined"
 )
 
 type Notification struct {
@@ -1,6 +1,8 @@
 package models
 
 import (
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/kamva/mgm/v3"
@@ -37,6 +39,18 @@ type GeoPoint struct {
 	Longitude   float64   `bson:"longitude,omitempty" json:"longitude"`
 }
 
+// LatLng returns the point as latitude/longitude, preferring GeoJSON coordinates
+// ok is false when the place has no usable location
+func (g GeoPoint) LatLng() (lat, lng float64, ok bool) {
+	if len(g.Coordinates) == 2 {
+		return g.Coordinates[1], g.Coordinates[0], true
+	}
+	if g.Latitude != 0 || g.Longitude != 0 {
+		return g.Latitude, g.Longitude, true
+	}
+	return 0, 0, false
+}
+
 // PlacePhoto represents a photo reference from Google Places
 type PlacePhoto struct {
 	PhotoReference string `bson:"photo_reference" json:"photoReference"`
@@ -51,6 +65,155 @@ type OpeningHours struct {
 	WeekdayText []string `bson:"weekday_text,omitempty" json:"weekdayText,omitempty"`
 }
 
+// OpeningPeriod is a span a place is open, in minutes since midnight of its weekday
+// Close is past 1440 when the place closes after midnight.
+type OpeningPeriod struct {
+	Open  int `json:"open"`
+	Close int `json:"close"`
+}
+
+// Periods parses WeekdayText ("Monday: 9:00 AM – 5:00 PM") into opening periods per weekday
+// A closed day maps to an empty slice, weekdays whose text can't be parsed are left out.
+func (h *OpeningHours) Periods() map[time.Weekday][]OpeningPeriod {
+	periods := map[time.Weekday][]OpeningPeriod{}
+	if h == nil {
+		return periods
+	}
+
+	for _, line := range h.WeekdayText {
+		name, hours, found := strings.Cut(line, ":")
+		if !found {
+			continue
+		}
+		weekday, ok := weekdayNames[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			continue
+		}
+		if dayPeriods, ok := parseOpeningPeriods(hours); ok {
+			periods[weekday] = dayPeriods
+		}
+	}
+	return periods
+}
+
+// IsOpenDuring reports whether a visit from start to end (minutes since midnight, end may pass 1440)
+// on the weekday fits in one opening period, including periods running on from the previous day.
+// known is false when the hours for that weekday are missing or can't be parsed.
+func (h *OpeningHours) IsOpenDuring(weekday time.Weekday, start, end int) (open, known bool) {
+	periods := h.Periods()
+	today, known := periods[weekday]
+	if !known {
+		return false, false
+	}
+
+	candidates := append([]OpeningPeriod(nil), today...)
+	for _, period := range periods[(weekday+6)%7] {
+		if period.Close > 1440 {
+			candidates = append(candidates, OpeningPeriod{Open: period.Open - 1440, Close: period.Close - 1440})
+		}
+	}
+
+	for _, period := range candidates {
+		if start >= period.Open && end <= period.Close {
+			return true, true
+		}
+	}
+	return false, true
+}
+
+var weekdayNames = map[string]time.Weekday{
+	"sunday":    time.Sunday,
+	"monday":    time.Monday,
+	"tuesday":   time.Tuesday,
+	"wednesday": time.Wednesday,
+	"thursday":  time.Thursday,
+	"friday":    time.Friday,
+	"saturday":  time.Saturday,
+}
+
+// parseOpeningPeriods parses "9:00 AM – 12:00 PM, 1:00 – 5:00 PM", "Closed" or "Open 24 hours"
+func parseOpeningPeriods(text string) ([]OpeningPeriod, bool) {
+	// Google separates times with narrow and thin spaces and an en dash
+	text = strings.NewReplacer("\u202f", " ", "\u2009", " ", "\u00a0", " ", "–", "-", "—", "-").Replace(text)
+	text = strings.ToLower(strings.TrimSpace(text))
+
+	switch text {
+	case "closed":
+		return []OpeningPeriod{}, true
+	case "open 24 hours":
+		return []OpeningPeriod{{Open: 0, Close: 1440}}, true
+	}
+
+	periods := []OpeningPeriod{}
+	for _, span := range strings.Split(text, ",") {
+		from, to, found := strings.Cut(span, "-")
+		if !found {
+			return nil, false
+		}
+
+		closing, closeMeridiem, ok := parseOpeningClock(to, "")
+		if !ok {
+			return nil, false
+		}
+		// The opening time omits AM/PM when it matches the closing time's
+		opening, _, ok := parseOpeningClock(from, closeMeridiem)
+		if !ok {
+			return nil, false
+		}
+
+		// Closing at or before opening means after midnight, "12:00 AM" closing is the end of the day
+		if closing <= opening {
+			closing += 1440
+		}
+		periods = append(periods, OpeningPeriod{Open: opening, Close: closing})
+	}
+	return periods, true
+}
+
+// parseOpeningClock parses "9:00 am", "9 pm" or "21:00" into minutes since midnight
+// defaultMeridiem applies when the value has no am/pm, the returned meridiem is the one used.
+func parseOpeningClock(value, defaultMeridiem string) (int, string, bool) {
+	value = strings.TrimSpace(value)
+	meridiem := defaultMeridiem
+	for _, suffix := range []string{"am", "pm"} {
+		if strings.HasSuffix(value, suffix) {
+			meridiem = suffix
+			value = strings.TrimSpace(strings.TrimSuffix(value, suffix))
+		}
+	}
+
+	hourText, minuteText, hasMinutes := strings.Cut(value, ":")
+	hour, err := strconv.Atoi(hourText)
+	if err != nil {
+		return 0, "", false
+	}
+	minute := 0
+	if hasMinutes {
+		if minute, err = strconv.Atoi(minuteText); err != nil {
+			return 0, "", false
+		}
+	}
+	if minute < 0 || minute > 59 {
+		return 0, "", false
+	}
+
+	switch meridiem {
+	case "am", "pm":
+		if hour < 1 || hour > 12 {
+			return 0, "", false
+		}
+		hour %= 12
+		if meridiem == "pm" {
+			hour += 12
+		}
+	default:
+		if hour < 0 || hour > 24 {
+			return 0, "", false
+		}
+	}
+	return hour*60 + minute, meridiem, true
+}
+
 // EditorialSummary represents Google's editorial summary
 type EditorialSummary struct {
 	Overview string `bson:"overview" json:"overview"`
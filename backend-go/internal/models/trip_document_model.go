@@ -0,0 +1,55 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/kamva/mgm/v3"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// TripDocument attaches an uploaded file to a trip, optionally to one of its entries
+// The file stays owned by the uploader, detaching only removes this link
+type TripDocument struct {
+	mgm.DefaultModel `bson:",inline"`
+
+	TripID   primitive.ObjectID  `bson:"trip_id" json:"tripId"`
+	EntryID  *primitive.ObjectID `bson:"entry_id,omitempty" json:"entryId,omitempty"` // nil = attached to the trip itself
+	FileID   primitive.ObjectID  `bson:"file_id" json:"fileId"`
+	Category string              `bson:"category" json:"category"` // ticket, booking, insurance, id, other
+	Title    string              `bson:"title" json:"title"`
+	AddedBy  primitive.ObjectID  `bson:"added_by" json:"addedBy"`
+
+	// Populated fields (not stored in DB)
+	File *File `bson:"-" json:"file,omitempty"`
+}
+
+// MarshalJSON customizes JSON marshaling to map MongoDB _id to id and use camelCase
+func (d TripDocument) MarshalJSON() ([]byte, error) {
+	type Alias TripDocument
+	return json.Marshal(&struct {
+		ID        string    `json:"id"`
+		CreatedAt time.Time `json:"createdAt"`
+		UpdatedAt time.Time `json:"updatedAt"`
+		*Alias
+	}{
+		ID:        d.ID.Hex(),
+		CreatedAt: d.CreatedAt,
+		UpdatedAt: d.UpdatedAt,
+		Alias:     (*Alias)(&d),
+	})
+}
+
+// Constants for TripDocument category
+const (
+	DocumentCategoryTicket    = "ticket"
+	DocumentCategoryBooking   = "booking"
+	DocumentCategoryInsurance = "insurance"
+	DocumentCategoryID        = "id"
+	DocumentCategoryOther     = "other"
+)
+
+// CollectionName returns the collection name for TripDocument
+func (d *TripDocument) CollectionName() string {
+	return "trip_documents"
+}
@@ -0,0 +1,88 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/kamva/mgm/v3"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// TripInvitation represents a pending invitation to join a trip
+// Type "user" targets a single invitee, type "link" is a shareable token anyone can use to join
+type TripInvitation struct {
+	mgm.DefaultModel `bson:",inline"`
+
+	TripID      primitive.ObjectID  `bson:"trip_id" json:"tripId"`
+	InvitedBy   primitive.ObjectID  `bson:"invited_by" json:"invitedBy"`
+	InviteeID   *primitive.ObjectID `bson:"invitee_id,omitempty" json:"inviteeId,omitempty"` // Only for user invitations
+	Type        string              `bson:"type" json:"type"`                                // user, link
+	Role        string              `bson:"role" json:"role"`                                // editor, viewer
+	Status      string              `bson:"status" json:"status"`                            // pending, accepted, declined, revoked
+	Token       string              `bson:"token,omitempty" json:"token,omitempty"`          // Only for link invitations
+	ExpiresAt   *time.Time          `bson:"expires_at,omitempty" json:"expiresAt,omitempty"`
+	MaxUses     *int                `bson:"max_uses,omitempty" json:"maxUses,omitempty"` // nil = unlimited
+	UseCount    int                 `bson:"use_count" json:"useCount"`
+	RespondedAt *time.Time          `bson:"responded_at,omitempty" json:"respondedAt,omitempty"`
+
+	// Populated fields (not stored in DB)
+	Trip    *InvitationTripInfo `bson:"-" json:"trip,omitempty"`
+	Inviter *TripUserInfo       `bson:"-" json:"inviter,omitempty"`
+}
+
+// InvitationTripInfo represents trip summary shown to invitees
+type InvitationTripInfo struct {
+	ID         primitive.ObjectID `json:"id"`
+	Title      string             `json:"title"`
+	CoverPhoto *string            `json:"coverPhoto,omitempty"`
+	StartDate  time.Time          `json:"startDate"`
+	EndDate    time.Time          `json:"endDate"`
+}
+
+// MarshalJSON customizes JSON marshaling to map MongoDB _id to id and use camelCase
+func (i TripInvitation) MarshalJSON() ([]byte, error) {
+	type Alias TripInvitation
+	return json.Marshal(&struct {
+		ID        string    `json:"id"`
+		CreatedAt time.Time `json:"createdAt"`
+		UpdatedAt time.Time `json:"updatedAt"`
+		*Alias
+	}{
+		ID:        i.ID.Hex(),
+		CreatedAt: i.CreatedAt,
+		UpdatedAt: i.UpdatedAt,
+		Alias:     (*Alias)(&i),
+	})
+}
+
+// Constants for TripInvitation type
+const (
+	InvitationTypeUser = "user"
+	InvitationTypeLink = "link"
+)
+
+// Constants for TripInvitation status
+const (
+	InvitationStatusPending  = "pending"
+	InvitationStatusAccepted = "accepted"
+	InvitationStatusDeclined = "declined"
+	InvitationStatusRevoked  = "revoked"
+)
+
+// CollectionName returns the collection name for TripInvitation
+func (i *TripInvitation) CollectionName() string {
+	return "trip_invitations"
+}
+
+// IsExpired checks if the invitation has passed its expiry time
+func (i *TripInvitation) IsExpired() bool {
+	return i.ExpiresAt != nil && time.Now().After(*i.ExpiresAt)
+}
+
+// IsUsable checks if the invitation can still be accepted or joined
+func (i *TripInvitation) IsUsable() bool {
+	if i.Status != InvitationStatusPending || i.IsExpired() {
+		return false
+	}
+	return i.MaxUses == nil || i.UseCount < *i.MaxUses
+}
@@ -23,9 +23,19 @@ type Trip struct {
 	TripMembers    []TripMember       `bson:"trip_members,omitempty" json:"tripMembers,omitempty"`
 	Status         string             `bson:"status" json:"status"` // draft, published, archived
 	Type           string             `bson:"type" json:"type"`     // trip, guide
+	Visibility     string             `bson:"visibility" json:"visibility"` // private, unlisted, public
 	CoverPhoto     *string            `bson:"cover_photo,omitempty" json:"coverPhoto,omitempty"`
 	Tags           []string           `bson:"tags,omitempty" json:"tags,omitempty"`
-	DeletedAt      *time.Time         `bson:"deleted_at,omitempty" json:"deletedAt,omitempty"`
+	DeletedAt      *time.Time         `bson:"deleted_at" json:"deletedAt,omitempty"`                  // Soft delete, null when live or restored
+	ForkedFromID   *primitive.ObjectID `bson:"forked_from_id,omitempty" json:"forkedFromId,omitempty"` // Source trip when cloned from a guide
+	Version        int64              `bson:"version" json:"version"`                                 // Bumped on every update, sent as the ETag
+	CalendarToken  *string            `bson:"calendar_token" json:"-"`                                // Secret for the subscribable iCalendar feed, null when revoked
+	EntryTitles    []string           `bson:"entry_titles,omitempty" json:"-"`                        // Denormalized itinerary entry titles for full-text search
+	Location       *GeoJSONPoint      `bson:"location" json:"-"`                                      // Derived from Destinations.Coordinates for geo queries (2dsphere)
+	PublishAt      *time.Time         `bson:"publish_at" json:"publishAt,omitempty"`                  // Scheduled publish time for drafts
+	ArchivedAt     *time.Time         `bson:"archived_at" json:"archivedAt,omitempty"`
+	UnarchivedAt   *time.Time         `bson:"unarchived_at,omitempty" json:"-"`                       // Set when the owner unarchives, the sweeper leaves such trips alone
+	StatusBeforeArchive *string       `bson:"status_before_archive" json:"-"`                         // Restored on unarchive
 
 	// Cached counts from Interaction collection
 	ViewCount      int     `bson:"view_count" json:"viewCount"`
@@ -35,7 +45,22 @@ type Trip struct {
 	Level          *string `bson:"level,omitempty" json:"level,omitempty"` // Easy, Moderate, Hard, Expert
 
 	// Aggregated data (populated via lookup)
-	Owner *TripUserInfo `bson:"owner,omitempty" json:"owner,omitempty"`
+	Owner      *TripUserInfo `bson:"owner,omitempty" json:"owner,omitempty"`
+	DistanceKm *float64      `bson:"distance_km,omitempty" json:"distanceKm,omitempty"` // Populated by geo queries
+}
+
+// GeoJSONPoint is a GeoJSON point usable with a 2dsphere index
+type GeoJSONPoint struct {
+	Type        string    `bson:"type" json:"type"`               // "Point"
+	Coordinates []float64 `bson:"coordinates" json:"coordinates"` // [longitude, latitude]
+}
+
+// NewGeoJSONPoint returns nil when the coordinates are out of range
+func NewGeoJSONPoint(lat, lng float64) *GeoJSONPoint {
+	if lat < -90 || lat > 90 || lng < -180 || lng > 180 {
+		return nil
+	}
+	return &GeoJSONPoint{Type: "Point", Coordinates: []float64{lng, lat}}
 }
 
 // TripUserInfo represents user information in aggregated responses
@@ -75,6 +100,14 @@ type Coordinates struct {
 	Lng float64 `bson:"lng" json:"lng"`
 }
 
+// GeoJSON returns the destination as a GeoJSON point, nil when it has no valid coordinates
+func (d *TripDestination) GeoJSON() *GeoJSONPoint {
+	if d.Coordinates == nil {
+		return nil
+	}
+	return NewGeoJSONPoint(d.Coordinates.Lat, d.Coordinates.Lng)
+}
+
 // TripMember represents embedded member information
 type TripMember struct {
 	ID       primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
@@ -96,6 +129,13 @@ const (
 	TripTypeGuide = "guide"
 )
 
+// Constants for Trip visibility
+const (
+	TripVisibilityPrivate  = "private"  // Members only
+	TripVisibilityUnlisted = "unlisted" // Anyone with the direct link, never listed
+	TripVisibilityPublic   = "public"   // Listed and searchable
+)
+
 // Constants for Trip level
 const (
 	TripLevelEasy     = "Easy"
@@ -128,9 +168,60 @@ func (t *Trip) Creating() error {
 		t.Type = TripTypeTrip
 	}
 
+	// Set default visibility if not set
+	if t.Visibility == "" {
+		t.Visibility = TripVisibilityPrivate
+	}
+
 	return nil
 }
 
+// Saving keeps the GeoJSON location in sync with the destination coordinates and
+// pins the visibility of trips created before visibility existed, so a whole-document
+// update never stores an empty one
+func (t *Trip) Saving() error {
+	t.Location = t.Destinations.GeoJSON()
+	t.Visibility = t.EffectiveVisibility()
+	return t.DefaultModel.Saving()
+}
+
+// EffectiveVisibility returns the trip visibility, falling back for trips created before
+// visibility existed: published trips were public, everything else private
+func (t *Trip) EffectiveVisibility() string {
+	if t.Visibility != "" {
+		return t.Visibility
+	}
+	if t.Status == TripStatusPublished {
+		return TripVisibilityPublic
+	}
+	return TripVisibilityPrivate
+}
+
+// IsArchived checks if trip is archived, archived trips are read-only
+func (t *Trip) IsArchived() bool {
+	return t.Status == TripStatusArchived
+}
+
+// Archive marks the trip archived and remembers its status for Unarchive
+func (t *Trip) Archive(now time.Time) {
+	status := t.Status
+	t.StatusBeforeArchive = &status
+	t.Status = TripStatusArchived
+	t.ArchivedAt = &now
+	t.PublishAt = nil
+}
+
+// Unarchive restores the status the trip had before it was archived
+func (t *Trip) Unarchive(now time.Time) {
+	t.Status = TripStatusDraft
+	if t.StatusBeforeArchive != nil && *t.StatusBeforeArchive != TripStatusArchived {
+		t.Status = *t.StatusBeforeArchive
+	}
+	t.StatusBeforeArchive = nil
+	t.ArchivedAt = nil
+	t.UnarchivedAt = &now
+}
+
 // IsDeleted checks if trip is soft deleted
 func (t *Trip) IsDeleted() bool {
 	return t.DeletedAt != nil
@@ -142,6 +233,11 @@ func (t *Trip) SoftDelete() {
 	t.DeletedAt = &now
 }
 
+// Restore takes the trip out of the trash
+func (t *Trip) Restore() {
+	t.DeletedAt = nil
+}
+
 // IncrementViews increments the view count
 func (t *Trip) IncrementViews() {
 	t.ViewCount++
@@ -35,8 +35,9 @@ type User struct {
 
 // UserSettings represents user preferences
 type UserSettings struct {
-	Language      string `bson:"language" json:"language"`           // en, th
-	Notifications bool   `bson:"notifications" json:"notifications"` // enable/disable notifications
+	Language             string `bson:"language" json:"language"`                                                                            // en, th
+	Notifications        bool   `bson:"notifications" json:"notifications"`                                                                  // enable/disable notifications
+	AutoArchiveAfterDays *int   `bson:"auto_archive_after_days,omitempty" json:"autoArchiveAfterDays,omitempty" binding:"omitempty,min=0,max=3650"` // Days after a trip ends before it's archived (null = server default, 0 = never)
 }
 
 // Constants for User role
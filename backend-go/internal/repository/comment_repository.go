@@ -448,6 +448,64 @@ func (r *CommentRepository) Delete(ctx context.Context, id string) error {
 	return nil
 }
 
+// DeleteByTargetID hard deletes every comment and reply on a target, soft deleted ones included
+// Returns the deleted comment IDs so interactions on them can be removed too
+func (r *CommentRepository) DeleteByTargetID(ctx context.Context, targetID, targetType string) ([]primitive.ObjectID, error) {
+	ctx, span := r.tracer.Start(ctx, "CommentRepository.DeleteByTargetID")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	logger.Input(map[string]interface{}{
+		"targetID":   targetID,
+		"targetType": targetType,
+	})
+
+	objectID, err := primitive.ObjectIDFromHex(targetID)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	filter := bson.M{
+		"target_id":   objectID,
+		"target_type": targetType,
+	}
+
+	cursor, err := mgm.Coll(&models.Comment{}).Find(ctx, filter, options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var results []struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	ids := make([]primitive.ObjectID, 0, len(results))
+	for _, result := range results {
+		ids = append(ids, result.ID)
+	}
+	if len(ids) == 0 {
+		return ids, nil
+	}
+
+	result, err := mgm.Coll(&models.Comment{}).DeleteMany(ctx, bson.M{"_id": bson.M{"$in": ids}})
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	logger.Output(map[string]interface{}{
+		"deletedCount": result.DeletedCount,
+	})
+	return ids, nil
+}
+
 // CountByTargetID counts comments for a target
 func (r *CommentRepository) CountByTargetID(ctx context.Context, targetID, targetType string) (int64, error) {
 	ctx, span := r.tracer.Start(ctx, "CommentRepository.CountByTargetID")
@@ -512,3 +570,100 @@ func (r *CommentRepository) CountReplies(ctx context.Context, parentID string) (
 	})
 	return count, nil
 }
+
+// CountRepliesByParents counts non-deleted replies per parent comment for a batch of comments
+func (r *CommentRepository) CountRepliesByParents(ctx context.Context, parentIDs []primitive.ObjectID) (map[primitive.ObjectID]int, error) {
+	ctx, span := r.tracer.Start(ctx, "CommentRepository.CountRepliesByParents")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	logger.Input(map[string]interface{}{
+		"parents": len(parentIDs),
+	})
+
+	pipeline := bson.A{
+		bson.M{"$match": bson.M{
+			"parent_id":  bson.M{"$in": parentIDs},
+			"deleted_at": nil,
+		}},
+		bson.M{"$group": bson.M{
+			"_id":   "$parent_id",
+			"count": bson.M{"$sum": 1},
+		}},
+	}
+
+	cursor, err := mgm.Coll(&models.Comment{}).Aggregate(ctx, pipeline)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var groups []struct {
+		ID    primitive.ObjectID `bson:"_id"`
+		Count int                `bson:"count"`
+	}
+	if err := cursor.All(ctx, &groups); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	counts := make(map[primitive.ObjectID]int, len(groups))
+	for _, group := range groups {
+		counts[group.ID] = group.Count
+	}
+
+	logger.Output(map[string]interface{}{
+		"count": len(counts),
+	})
+	return counts, nil
+}
+
+// FindCountersBatch reads the cached counters of up to limit comments after afterID, soft deleted comments included
+func (r *CommentRepository) FindCountersBatch(ctx context.Context, afterID primitive.ObjectID, limit int64) ([]*models.Comment, error) {
+	ctx, span := r.tracer.Start(ctx, "CommentRepository.FindCountersBatch")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	logger.Input(map[string]interface{}{
+		"afterID": afterID.Hex(),
+		"limit":   limit,
+	})
+
+	comments := []*models.Comment{}
+	err := findBatchAfter(ctx, mgm.Coll(&models.Comment{}), &comments, afterID, limit, bson.M{
+		CommentCounterReactions: 1,
+		CommentCounterReplies:   1,
+	})
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	logger.Output(map[string]interface{}{
+		"count": len(comments),
+	})
+	return comments, nil
+}
+
+// SetCounters overwrites cached counters of many comments, keyed by comment ID then counter field
+func (r *CommentRepository) SetCounters(ctx context.Context, counters map[primitive.ObjectID]map[string]int) (int64, error) {
+	ctx, span := r.tracer.Start(ctx, "CommentRepository.SetCounters")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	logger.Input(map[string]interface{}{
+		"comments": len(counters),
+	})
+
+	modified, err := setCounters(ctx, mgm.Coll(&models.Comment{}), counters)
+	if err != nil {
+		logger.Error(err)
+		return 0, err
+	}
+
+	logger.Output(map[string]interface{}{
+		"modified": modified,
+	})
+	return modified, nil
+}
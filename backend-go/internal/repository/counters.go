@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/kamva/mgm/v3"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Cached counters on comments
+const (
+	CommentCounterReactions = "reactions_count"
+	CommentCounterReplies   = "replies_count"
+)
+
+// setCounters overwrites cached counters in one unordered bulk write, updated_at is left alone
+func setCounters(ctx context.Context, coll *mgm.Collection, counters map[primitive.ObjectID]map[string]int) (int64, error) {
+	if len(counters) == 0 {
+		return 0, nil
+	}
+
+	writes := make([]mongo.WriteModel, 0, len(counters))
+	for id, fields := range counters {
+		set := bson.M{}
+		for field, value := range fields {
+			set[field] = value
+		}
+		writes = append(writes, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": id}).
+			SetUpdate(bson.M{"$set": set}))
+	}
+
+	result, err := coll.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false))
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
+// findBatchAfter reads documents (soft deleted included) in _id order, starting after afterID
+// Only the projected fields are decoded, which keeps batches over large collections cheap
+func findBatchAfter(ctx context.Context, coll *mgm.Collection, results interface{}, afterID primitive.ObjectID, limit int64, projection bson.M) error {
+	filter := bson.M{}
+	if !afterID.IsZero() {
+		filter["_id"] = bson.M{"$gt": afterID}
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "_id", Value: 1}}).
+		SetLimit(limit).
+		SetProjection(projection)
+
+	return coll.SimpleFindWithCtx(ctx, results, filter, opts)
+}
@@ -449,6 +449,38 @@ func (r *ExpenseRepository) DeleteByTripID(ctx context.Context, tripID string) e
 	return nil
 }
 
+// DetachFromEntries unlinks expenses from the given entries, they stay on their trip
+func (r *ExpenseRepository) DetachFromEntries(ctx context.Context, entryIDs []primitive.ObjectID) error {
+	ctx, span := r.tracer.Start(ctx, "ExpenseRepository.DetachFromEntries")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	logger.Input(map[string]interface{}{
+		"entries": len(entryIDs),
+	})
+
+	if len(entryIDs) == 0 {
+		return nil
+	}
+
+	result, err := mgm.Coll(&models.Expense{}).UpdateMany(ctx,
+		bson.M{"entry_id": bson.M{"$in": entryIDs}},
+		bson.M{
+			"$unset": bson.M{"entry_id": ""},
+			"$set":   bson.M{"updated_at": time.Now()},
+		},
+	)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	logger.Output(map[string]interface{}{
+		"modifiedCount": result.ModifiedCount,
+	})
+	return nil
+}
+
 // CountByTripID counts total expenses for a trip
 func (r *ExpenseRepository) CountByTripID(ctx context.Context, tripID string) (int64, error) {
 	ctx, span := r.tracer.Start(ctx, "ExpenseRepository.CountByTripID")
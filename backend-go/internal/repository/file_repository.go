@@ -84,6 +84,40 @@ func (r *FileRepository) FindByID(ctx context.Context, id string) (*models.File,
 	return file, nil
 }
 
+// FindByIDs finds files by IDs, missing IDs are skipped
+func (r *FileRepository) FindByIDs(ctx context.Context, ids []primitive.ObjectID) ([]*models.File, error) {
+	ctx, span := r.tracer.Start(ctx, "FileRepository.FindByIDs")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	logger.Input(map[string]interface{}{
+		"count": len(ids),
+	})
+
+	files := []*models.File{}
+	if len(ids) == 0 {
+		return files, nil
+	}
+
+	cursor, err := mgm.Coll(&models.File{}).Find(ctx, bson.M{"_id": bson.M{"$in": ids}})
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	err = cursor.All(ctx, &files)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	logger.Output(map[string]interface{}{
+		"count": len(files),
+	})
+	return files, nil
+}
+
 // FindByOwnerID finds all files by owner ID
 func (r *FileRepository) FindByOwnerID(ctx context.Context, ownerID string, skip, limit int64) ([]*models.File, error) {
 	ctx, span := r.tracer.Start(ctx, "FileRepository.FindByOwnerID")
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/kamva/mgm/v3"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"backend-go/internal/models"
+)
+
+// EnsureIndexes creates the indexes the repositories rely on (no-op when they already exist)
+func EnsureIndexes(ctx context.Context) error {
+	// Full-text search over trips, a collection can only have one text index
+	_, err := mgm.Coll(&models.Trip{}).Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "title", Value: "text"},
+				{Key: "description", Value: "text"},
+				{Key: "tags", Value: "text"},
+				{Key: "destinations.name", Value: "text"},
+				{Key: "destinations.country", Value: "text"},
+				{Key: "entry_titles", Value: "text"},
+			},
+			Options: options.Index().
+				SetName("trip_text_search").
+				SetDefaultLanguage("none"). // Thai and mixed-language content shouldn't be stemmed as English
+				SetWeights(bson.D{
+					{Key: "title", Value: 10},
+					{Key: "tags", Value: 5},
+					{Key: "destinations.name", Value: 5},
+					{Key: "destinations.country", Value: 3},
+					{Key: "description", Value: 2},
+					{Key: "entry_titles", Value: 1},
+				}),
+		},
+		{
+			Keys:    bson.D{{Key: "location", Value: "2dsphere"}},
+			Options: options.Index().SetName("trip_location_2dsphere"),
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	// Interaction lookups by viewer state and "my bookmarks"
+	_, err = mgm.Coll(&models.Interaction{}).Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "user_id", Value: 1},
+				{Key: "target_id", Value: 1},
+				{Key: "target_type", Value: 1},
+				{Key: "action_type", Value: 1},
+			},
+			Options: options.Index().SetName("interaction_user_target_action"),
+		},
+		{
+			Keys: bson.D{
+				{Key: "user_id", Value: 1},
+				{Key: "target_type", Value: 1},
+				{Key: "action_type", Value: 1},
+				{Key: "created_at", Value: -1},
+			},
+			Options: options.Index().SetName("interaction_user_action_recent"),
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	// Trip document vault listings and entry cascades
+	_, err = mgm.Coll(&models.TripDocument{}).Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "trip_id", Value: 1},
+				{Key: "created_at", Value: -1},
+			},
+			Options: options.Index().SetName("trip_document_trip_recent"),
+		},
+		{
+			Keys:    bson.D{{Key: "entry_id", Value: 1}},
+			Options: options.Index().SetName("trip_document_entry").SetSparse(true),
+		},
+		{
+			Keys:    bson.D{{Key: "file_id", Value: 1}},
+			Options: options.Index().SetName("trip_document_file"),
+		},
+	})
+	return err
+}
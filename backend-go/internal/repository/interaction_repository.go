@@ -267,6 +267,64 @@ func (r *InteractionRepository) CountReactionsByTarget(ctx context.Context, targ
 	return count, nil
 }
 
+// CountByTargets counts interactions per target and action for a batch of targets
+func (r *InteractionRepository) CountByTargets(ctx context.Context, targetType models.InteractionTarget, targetIDs []primitive.ObjectID) (map[primitive.ObjectID]map[models.InteractionAction]int, error) {
+	ctx, span := r.tracer.Start(ctx, "InteractionRepository.CountByTargets")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	logger.Input(map[string]interface{}{
+		"targetType": targetType,
+		"targets":    len(targetIDs),
+	})
+
+	pipeline := bson.A{
+		bson.M{"$match": bson.M{
+			"target_type": targetType,
+			"target_id":   bson.M{"$in": targetIDs},
+		}},
+		bson.M{"$group": bson.M{
+			"_id": bson.M{
+				"target_id":   "$target_id",
+				"action_type": "$action_type",
+			},
+			"count": bson.M{"$sum": 1},
+		}},
+	}
+
+	cursor, err := mgm.Coll(&models.Interaction{}).Aggregate(ctx, pipeline)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var groups []struct {
+		ID struct {
+			TargetID   primitive.ObjectID       `bson:"target_id"`
+			ActionType models.InteractionAction `bson:"action_type"`
+		} `bson:"_id"`
+		Count int `bson:"count"`
+	}
+	if err := cursor.All(ctx, &groups); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	counts := make(map[primitive.ObjectID]map[models.InteractionAction]int, len(targetIDs))
+	for _, group := range groups {
+		if counts[group.ID.TargetID] == nil {
+			counts[group.ID.TargetID] = map[models.InteractionAction]int{}
+		}
+		counts[group.ID.TargetID][group.ID.ActionType] = group.Count
+	}
+
+	logger.Output(map[string]interface{}{
+		"groups": len(groups),
+	})
+	return counts, nil
+}
+
 // FindByUser finds all interactions by a user for a specific action
 func (r *InteractionRepository) FindByUser(ctx context.Context, userID string, actionType models.InteractionAction) ([]*models.Interaction, error) {
 	ctx, span := r.tracer.Start(ctx, "InteractionRepository.FindByUser")
@@ -346,8 +404,39 @@ func (r *InteractionRepository) Delete(ctx context.Context, id string) error {
 	return nil
 }
 
+// DeleteByTargets deletes every interaction on the given targets
+func (r *InteractionRepository) DeleteByTargets(ctx context.Context, targetType models.InteractionTarget, targetIDs []primitive.ObjectID) (int64, error) {
+	ctx, span := r.tracer.Start(ctx, "InteractionRepository.DeleteByTargets")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	logger.Input(map[string]interface{}{
+		"targetType":  targetType,
+		"targetCount": len(targetIDs),
+	})
+
+	if len(targetIDs) == 0 {
+		return 0, nil
+	}
+
+	result, err := mgm.Coll(&models.Interaction{}).DeleteMany(ctx, bson.M{
+		"target_id":   bson.M{"$in": targetIDs},
+		"target_type": targetType,
+	})
+	if err != nil {
+		logger.Error(err)
+		return 0, err
+	}
+
+	logger.Output(map[string]interface{}{
+		"deletedCount": result.DeletedCount,
+	})
+	return result.DeletedCount, nil
+}
+
 // DeleteByUserAndTarget deletes an interaction by user, target, and action
-func (r *InteractionRepository) DeleteByUserAndTarget(ctx context.Context, userID, targetID string, targetType models.InteractionTarget, actionType models.InteractionAction) error {
+// Reports whether an interaction was actually removed so callers can keep cached counts exact
+func (r *InteractionRepository) DeleteByUserAndTarget(ctx context.Context, userID, targetID string, targetType models.InteractionTarget, actionType models.InteractionAction) (bool, error) {
 	ctx, span := r.tracer.Start(ctx, "InteractionRepository.DeleteByUserAndTarget")
 	defer span.End()
 	logger := utils.NewTraceLogger(ctx, span)
@@ -362,16 +451,16 @@ func (r *InteractionRepository) DeleteByUserAndTarget(ctx context.Context, userI
 	userObjectID, err := primitive.ObjectIDFromHex(userID)
 	if err != nil {
 		logger.Error(err)
-		return err
+		return false, err
 	}
 
 	targetObjectID, err := primitive.ObjectIDFromHex(targetID)
 	if err != nil {
 		logger.Error(err)
-		return err
+		return false, err
 	}
 
-	_, err = mgm.Coll(&models.Interaction{}).DeleteOne(ctx, bson.M{
+	result, err := mgm.Coll(&models.Interaction{}).DeleteOne(ctx, bson.M{
 		"user_id":     userObjectID,
 		"target_id":   targetObjectID,
 		"target_type": targetType,
@@ -379,16 +468,18 @@ func (r *InteractionRepository) DeleteByUserAndTarget(ctx context.Context, userI
 	})
 	if err != nil {
 		logger.Error(err)
-		return err
+		return false, err
 	}
 
-	logger.Info("Interaction deleted successfully")
-	return nil
+	logger.Output(map[string]interface{}{
+		"deleted": result.DeletedCount,
+	})
+	return result.DeletedCount > 0, nil
 }
 
 // UpsertUnique upserts a unique interaction (for reactions and bookmarks)
-// This prevents duplicate unique interactions
-func (r *InteractionRepository) UpsertUnique(ctx context.Context, interaction *models.Interaction) error {
+// This prevents duplicate unique interactions, reports whether a new interaction was inserted
+func (r *InteractionRepository) UpsertUnique(ctx context.Context, interaction *models.Interaction) (bool, error) {
 	ctx, span := r.tracer.Start(ctx, "InteractionRepository.UpsertUnique")
 	defer span.End()
 	logger := utils.NewTraceLogger(ctx, span)
@@ -407,6 +498,13 @@ func (r *InteractionRepository) UpsertUnique(ctx context.Context, interaction *m
 		"action_type": interaction.ActionType,
 	}
 
+	// Set timestamps manually
+	if interaction.CreatedAt.IsZero() {
+		now := time.Now()
+		interaction.CreatedAt = now
+		interaction.UpdatedAt = now
+	}
+
 	update := bson.M{
 		"$set": bson.M{
 			"user_id":     interaction.UserID,
@@ -421,12 +519,167 @@ func (r *InteractionRepository) UpsertUnique(ctx context.Context, interaction *m
 	}
 
 	opts := options.Update().SetUpsert(true)
-	_, err := mgm.Coll(interaction).UpdateOne(ctx, filter, update, opts)
+	result, err := mgm.Coll(interaction).UpdateOne(ctx, filter, update, opts)
 	if err != nil {
 		logger.Error(err)
-		return err
+		return false, err
 	}
 
-	logger.Info("Interaction upserted successfully")
-	return nil
+	inserted := result.UpsertedCount > 0
+	logger.Output(map[string]interface{}{
+		"inserted": inserted,
+	})
+	return inserted, nil
+}
+
+// DeleteReactions deletes the user's reactions on a target except the kept one (empty keeps none)
+// Returns the number of reactions removed
+func (r *InteractionRepository) DeleteReactions(ctx context.Context, userID, targetID string, targetType models.InteractionTarget, keep models.InteractionAction) (int64, error) {
+	ctx, span := r.tracer.Start(ctx, "InteractionRepository.DeleteReactions")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	logger.Input(map[string]interface{}{
+		"userID":     userID,
+		"targetID":   targetID,
+		"targetType": targetType,
+		"keep":       keep,
+	})
+
+	userObjectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		logger.Error(err)
+		return 0, err
+	}
+
+	targetObjectID, err := primitive.ObjectIDFromHex(targetID)
+	if err != nil {
+		logger.Error(err)
+		return 0, err
+	}
+
+	reactions := []models.InteractionAction{}
+	for _, action := range []models.InteractionAction{models.ActionLike, models.ActionLove, models.ActionAngry} {
+		if action != keep {
+			reactions = append(reactions, action)
+		}
+	}
+
+	result, err := mgm.Coll(&models.Interaction{}).DeleteMany(ctx, bson.M{
+		"user_id":     userObjectID,
+		"target_id":   targetObjectID,
+		"target_type": targetType,
+		"action_type": bson.M{"$in": reactions},
+	})
+	if err != nil {
+		logger.Error(err)
+		return 0, err
+	}
+
+	logger.Output(map[string]interface{}{
+		"deleted": result.DeletedCount,
+	})
+	return result.DeletedCount, nil
+}
+
+// FindUniqueActions returns the user's reactions and bookmark on a target
+func (r *InteractionRepository) FindUniqueActions(ctx context.Context, userID, targetID string, targetType models.InteractionTarget) ([]models.InteractionAction, error) {
+	ctx, span := r.tracer.Start(ctx, "InteractionRepository.FindUniqueActions")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	logger.Input(map[string]interface{}{
+		"userID":     userID,
+		"targetID":   targetID,
+		"targetType": targetType,
+	})
+
+	userObjectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	targetObjectID, err := primitive.ObjectIDFromHex(targetID)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	interactions := []*models.Interaction{}
+	err = mgm.Coll(&models.Interaction{}).SimpleFindWithCtx(ctx, &interactions, bson.M{
+		"user_id":     userObjectID,
+		"target_id":   targetObjectID,
+		"target_type": targetType,
+		"action_type": bson.M{
+			"$in": []models.InteractionAction{
+				models.ActionLike,
+				models.ActionLove,
+				models.ActionAngry,
+				models.ActionBookmark,
+			},
+		},
+	})
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	actions := make([]models.InteractionAction, 0, len(interactions))
+	for _, interaction := range interactions {
+		actions = append(actions, interaction.ActionType)
+	}
+
+	logger.Output(map[string]interface{}{
+		"actions": actions,
+	})
+	return actions, nil
+}
+
+// FindTargetIDsByUser returns target IDs the user acted on, most recent first
+func (r *InteractionRepository) FindTargetIDsByUser(ctx context.Context, userID string, targetType models.InteractionTarget, actionType models.InteractionAction, skip, limit int64) ([]primitive.ObjectID, error) {
+	ctx, span := r.tracer.Start(ctx, "InteractionRepository.FindTargetIDsByUser")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	logger.Input(map[string]interface{}{
+		"userID":     userID,
+		"targetType": targetType,
+		"actionType": actionType,
+		"skip":       skip,
+		"limit":      limit,
+	})
+
+	objectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}}).
+		SetSkip(skip).
+		SetLimit(limit).
+		SetProjection(bson.M{"target_id": 1})
+
+	interactions := []*models.Interaction{}
+	err = mgm.Coll(&models.Interaction{}).SimpleFindWithCtx(ctx, &interactions, bson.M{
+		"user_id":     objectID,
+		"target_type": targetType,
+		"action_type": actionType,
+	}, opts)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	ids := make([]primitive.ObjectID, 0, len(interactions))
+	for _, interaction := range interactions {
+		ids = append(ids, interaction.TargetID)
+	}
+
+	logger.Output(map[string]interface{}{
+		"count": len(ids),
+	})
+	return ids, nil
 }
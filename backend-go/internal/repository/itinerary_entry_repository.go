@@ -259,7 +259,7 @@ func (r *ItineraryEntryRepository) FindByType(ctx context.Context, itineraryID s
 	return entries, nil
 }
 
-// Update updates an entry
+// Update updates an entry, failing with ErrVersionConflict if it changed since it was read
 func (r *ItineraryEntryRepository) Update(ctx context.Context, entry *models.ItineraryEntry) error {
 	ctx, span := r.tracer.Start(ctx, "ItineraryEntryRepository.Update")
 	defer span.End()
@@ -269,7 +269,7 @@ func (r *ItineraryEntryRepository) Update(ctx context.Context, entry *models.Iti
 		"entryID": entry.ID.Hex(),
 	})
 
-	err := mgm.Coll(entry).UpdateWithCtx(ctx, entry)
+	err := updateVersioned(ctx, entry, &entry.Version)
 	if err != nil {
 		logger.Error(err)
 		return err
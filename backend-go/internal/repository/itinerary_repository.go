@@ -135,7 +135,7 @@ func (r *ItineraryRepository) FindByTripID(ctx context.Context, tripID string) (
 	return itineraries, nil
 }
 
-// Update updates an itinerary
+// Update updates an itinerary, failing with ErrVersionConflict if it changed since it was read
 func (r *ItineraryRepository) Update(ctx context.Context, itinerary *models.Itinerary) error {
 	ctx, span := r.tracer.Start(ctx, "ItineraryRepository.Update")
 	defer span.End()
@@ -145,7 +145,7 @@ func (r *ItineraryRepository) Update(ctx context.Context, itinerary *models.Itin
 		"itineraryID": itinerary.ID.Hex(),
 	})
 
-	err := mgm.Coll(itinerary).UpdateWithCtx(ctx, itinerary)
+	err := updateVersioned(ctx, itinerary, &itinerary.Version)
 	if err != nil {
 		logger.Error(err)
 		return err
@@ -209,9 +209,9 @@ func (r *ItineraryRepository) DeleteByTripID(ctx context.Context, tripID string)
 	return nil
 }
 
-// Count counts total itineraries for a trip
-func (r *ItineraryRepository) CountByTripID(ctx context.Context, tripID string) (int64, error) {
-	ctx, span := r.tracer.Start(ctx, "ItineraryRepository.CountByTripID")
+// CountDaysByTripID counts the days of a trip, the unscheduled bucket is not a day
+func (r *ItineraryRepository) CountDaysByTripID(ctx context.Context, tripID string) (int64, error) {
+	ctx, span := r.tracer.Start(ctx, "ItineraryRepository.CountDaysByTripID")
 	defer span.End()
 	logger := utils.NewTraceLogger(ctx, span)
 
@@ -225,7 +225,7 @@ func (r *ItineraryRepository) CountByTripID(ctx context.Context, tripID string)
 		return 0, err
 	}
 
-	count, err := mgm.Coll(&models.Itinerary{}).CountDocuments(ctx, bson.M{"trip_id": objectID})
+	count, err := mgm.Coll(&models.Itinerary{}).CountDocuments(ctx, bson.M{"trip_id": objectID, "unscheduled": bson.M{"$ne": true}})
 	if err != nil {
 		logger.Error(err)
 		return 0, err
@@ -0,0 +1,308 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/kamva/mgm/v3"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+
+	"backend-go/internal/models"
+	"backend-go/pkg/utils"
+)
+
+type TripDocumentRepository struct {
+	tracer trace.Tracer
+}
+
+func NewTripDocumentRepository() *TripDocumentRepository {
+	return &TripDocumentRepository{
+		tracer: otel.Tracer("trip-document-repository"),
+	}
+}
+
+// Helper methods to encapsulate ObjectID logic
+
+// NewDocument creates a new TripDocument with ObjectIDs from strings
+func (r *TripDocumentRepository) NewDocument(tripID, fileID, addedBy string) (*models.TripDocument, error) {
+	tripObjID, err := primitive.ObjectIDFromHex(tripID)
+	if err != nil {
+		return nil, err
+	}
+
+	fileObjID, err := primitive.ObjectIDFromHex(fileID)
+	if err != nil {
+		return nil, err
+	}
+
+	addedByObjID, err := primitive.ObjectIDFromHex(addedBy)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.TripDocument{
+		TripID:  tripObjID,
+		FileID:  fileObjID,
+		AddedBy: addedByObjID,
+	}, nil
+}
+
+// SetEntryID sets EntryID from string, an empty string attaches the document to the trip itself
+func (r *TripDocumentRepository) SetEntryID(document *models.TripDocument, entryID string) error {
+	if entryID == "" {
+		document.EntryID = nil
+		return nil
+	}
+
+	entryObjID, err := primitive.ObjectIDFromHex(entryID)
+	if err != nil {
+		return err
+	}
+
+	document.EntryID = &entryObjID
+	return nil
+}
+
+// Create creates a new document link
+func (r *TripDocumentRepository) Create(ctx context.Context, document *models.TripDocument) error {
+	ctx, span := r.tracer.Start(ctx, "TripDocumentRepository.Create")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	logger.Input(map[string]interface{}{
+		"tripID":   document.TripID.Hex(),
+		"fileID":   document.FileID.Hex(),
+		"category": document.Category,
+	})
+
+	// Set timestamps manually
+	now := time.Now()
+	document.CreatedAt = now
+	document.UpdatedAt = now
+
+	err := mgm.Coll(document).CreateWithCtx(ctx, document)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	logger.Output(map[string]interface{}{
+		"documentID": document.ID.Hex(),
+	})
+	return nil
+}
+
+// FindByID finds a document link by ID
+func (r *TripDocumentRepository) FindByID(ctx context.Context, id string) (*models.TripDocument, error) {
+	ctx, span := r.tracer.Start(ctx, "TripDocumentRepository.FindByID")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	logger.Input(map[string]interface{}{
+		"documentID": id,
+	})
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	document := &models.TripDocument{}
+	err = mgm.Coll(document).FindByIDWithCtx(ctx, objectID, document)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	logger.Output(map[string]interface{}{
+		"tripID": document.TripID.Hex(),
+		"fileID": document.FileID.Hex(),
+	})
+	return document, nil
+}
+
+// FindByTripID finds the documents of a trip, newest first
+// A non-empty entryID keeps only that entry's documents, a non-empty category only that category
+func (r *TripDocumentRepository) FindByTripID(ctx context.Context, tripID, entryID, category string) ([]*models.TripDocument, error) {
+	ctx, span := r.tracer.Start(ctx, "TripDocumentRepository.FindByTripID")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	logger.Input(map[string]interface{}{
+		"tripID":   tripID,
+		"entryID":  entryID,
+		"category": category,
+	})
+
+	objectID, err := primitive.ObjectIDFromHex(tripID)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	filter := bson.M{"trip_id": objectID}
+	if entryID != "" {
+		entryObjID, err := primitive.ObjectIDFromHex(entryID)
+		if err != nil {
+			logger.Error(err)
+			return nil, err
+		}
+		filter["entry_id"] = entryObjID
+	}
+	if category != "" {
+		filter["category"] = category
+	}
+
+	documents := []*models.TripDocument{}
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	cursor, err := mgm.Coll(&models.TripDocument{}).Find(ctx, filter, opts)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	if err := cursor.All(ctx, &documents); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	logger.Output(map[string]interface{}{
+		"count": len(documents),
+	})
+	return documents, nil
+}
+
+// Update updates a document link
+func (r *TripDocumentRepository) Update(ctx context.Context, document *models.TripDocument) error {
+	ctx, span := r.tracer.Start(ctx, "TripDocumentRepository.Update")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	logger.Input(map[string]interface{}{
+		"documentID": document.ID.Hex(),
+		"category":   document.Category,
+	})
+
+	err := mgm.Coll(document).UpdateWithCtx(ctx, document)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	logger.Info("Document updated successfully")
+	return nil
+}
+
+// Delete removes a document link, the file itself is left alone
+func (r *TripDocumentRepository) Delete(ctx context.Context, id string) error {
+	ctx, span := r.tracer.Start(ctx, "TripDocumentRepository.Delete")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	logger.Input(map[string]interface{}{
+		"documentID": id,
+	})
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	_, err = mgm.Coll(&models.TripDocument{}).DeleteOne(ctx, bson.M{"_id": objectID})
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	logger.Info("Document deleted successfully")
+	return nil
+}
+
+// DetachFromEntries moves the documents of the given entries up to their trip, used when entries are deleted
+func (r *TripDocumentRepository) DetachFromEntries(ctx context.Context, entryIDs []primitive.ObjectID) error {
+	ctx, span := r.tracer.Start(ctx, "TripDocumentRepository.DetachFromEntries")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	logger.Input(map[string]interface{}{
+		"entries": len(entryIDs),
+	})
+
+	if len(entryIDs) == 0 {
+		return nil
+	}
+
+	result, err := mgm.Coll(&models.TripDocument{}).UpdateMany(ctx,
+		bson.M{"entry_id": bson.M{"$in": entryIDs}},
+		bson.M{
+			"$unset": bson.M{"entry_id": ""},
+			"$set":   bson.M{"updated_at": time.Now()},
+		},
+	)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	logger.Output(map[string]interface{}{
+		"modifiedCount": result.ModifiedCount,
+	})
+	return nil
+}
+
+// DeleteByTripID deletes all document links of a trip
+func (r *TripDocumentRepository) DeleteByTripID(ctx context.Context, tripID string) error {
+	ctx, span := r.tracer.Start(ctx, "TripDocumentRepository.DeleteByTripID")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	logger.Input(map[string]interface{}{
+		"tripID": tripID,
+	})
+
+	objectID, err := primitive.ObjectIDFromHex(tripID)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	result, err := mgm.Coll(&models.TripDocument{}).DeleteMany(ctx, bson.M{"trip_id": objectID})
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	logger.Output(map[string]interface{}{
+		"deletedCount": result.DeletedCount,
+	})
+	return nil
+}
+
+// DeleteByFileID deletes every link to a file, used when the file itself is deleted
+func (r *TripDocumentRepository) DeleteByFileID(ctx context.Context, fileID primitive.ObjectID) error {
+	ctx, span := r.tracer.Start(ctx, "TripDocumentRepository.DeleteByFileID")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	logger.Input(map[string]interface{}{
+		"fileID": fileID.Hex(),
+	})
+
+	result, err := mgm.Coll(&models.TripDocument{}).DeleteMany(ctx, bson.M{"file_id": fileID})
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	logger.Output(map[string]interface{}{
+		"deletedCount": result.DeletedCount,
+	})
+	return nil
+}
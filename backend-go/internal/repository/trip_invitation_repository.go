@@ -0,0 +1,382 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/kamva/mgm/v3"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+
+	"backend-go/internal/models"
+	"backend-go/pkg/utils"
+)
+
+type TripInvitationRepository struct {
+	tracer trace.Tracer
+}
+
+func NewTripInvitationRepository() *TripInvitationRepository {
+	return &TripInvitationRepository{
+		tracer: otel.Tracer("trip-invitation-repository"),
+	}
+}
+
+// Helper methods to encapsulate ObjectID logic
+
+// NewInvitation creates a new TripInvitation with ObjectIDs from strings
+func (r *TripInvitationRepository) NewInvitation(tripID, invitedBy string) (*models.TripInvitation, error) {
+	tripObjID, err := primitive.ObjectIDFromHex(tripID)
+	if err != nil {
+		return nil, err
+	}
+
+	invitedByObjID, err := primitive.ObjectIDFromHex(invitedBy)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.TripInvitation{
+		TripID:    tripObjID,
+		InvitedBy: invitedByObjID,
+		Status:    models.InvitationStatusPending,
+	}, nil
+}
+
+// SetInviteeID sets InviteeID from string
+func (r *TripInvitationRepository) SetInviteeID(invitation *models.TripInvitation, inviteeID string) error {
+	inviteeObjID, err := primitive.ObjectIDFromHex(inviteeID)
+	if err != nil {
+		return err
+	}
+
+	invitation.InviteeID = &inviteeObjID
+	return nil
+}
+
+// IsInvitee checks if userID is the invitee of a user invitation
+func (r *TripInvitationRepository) IsInvitee(invitation *models.TripInvitation, userID string) bool {
+	return invitation.InviteeID != nil && invitation.InviteeID.Hex() == userID
+}
+
+// Create creates a new invitation
+func (r *TripInvitationRepository) Create(ctx context.Context, invitation *models.TripInvitation) error {
+	ctx, span := r.tracer.Start(ctx, "TripInvitationRepository.Create")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	logger.Input(map[string]interface{}{
+		"tripID": invitation.TripID.Hex(),
+		"type":   invitation.Type,
+		"role":   invitation.Role,
+	})
+
+	// Set timestamps manually
+	now := time.Now()
+	invitation.CreatedAt = now
+	invitation.UpdatedAt = now
+
+	err := mgm.Coll(invitation).CreateWithCtx(ctx, invitation)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	logger.Output(map[string]interface{}{
+		"invitationID": invitation.ID.Hex(),
+	})
+	return nil
+}
+
+// FindByID finds an invitation by ID
+func (r *TripInvitationRepository) FindByID(ctx context.Context, id string) (*models.TripInvitation, error) {
+	ctx, span := r.tracer.Start(ctx, "TripInvitationRepository.FindByID")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	logger.Input(map[string]interface{}{
+		"invitationID": id,
+	})
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	invitation := &models.TripInvitation{}
+	err = mgm.Coll(invitation).FindByIDWithCtx(ctx, objectID, invitation)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	logger.Output(map[string]interface{}{
+		"tripID": invitation.TripID.Hex(),
+		"status": invitation.Status,
+	})
+	return invitation, nil
+}
+
+// FindByToken finds a link invitation by its token
+func (r *TripInvitationRepository) FindByToken(ctx context.Context, token string) (*models.TripInvitation, error) {
+	ctx, span := r.tracer.Start(ctx, "TripInvitationRepository.FindByToken")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	invitation := &models.TripInvitation{}
+	err := mgm.Coll(invitation).FirstWithCtx(ctx, bson.M{
+		"token": token,
+		"type":  models.InvitationTypeLink,
+	}, invitation)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	logger.Output(map[string]interface{}{
+		"invitationID": invitation.ID.Hex(),
+		"tripID":       invitation.TripID.Hex(),
+	})
+	return invitation, nil
+}
+
+// FindPendingByTripID finds all pending invitations for a trip
+func (r *TripInvitationRepository) FindPendingByTripID(ctx context.Context, tripID string) ([]*models.TripInvitation, error) {
+	ctx, span := r.tracer.Start(ctx, "TripInvitationRepository.FindPendingByTripID")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	logger.Input(map[string]interface{}{
+		"tripID": tripID,
+	})
+
+	objectID, err := primitive.ObjectIDFromHex(tripID)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	invitations, err := r.findPending(ctx, bson.M{"trip_id": objectID})
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	logger.Output(map[string]interface{}{
+		"count": len(invitations),
+	})
+	return invitations, nil
+}
+
+// FindPendingByInvitee finds all pending user invitations addressed to a user
+func (r *TripInvitationRepository) FindPendingByInvitee(ctx context.Context, userID string) ([]*models.TripInvitation, error) {
+	ctx, span := r.tracer.Start(ctx, "TripInvitationRepository.FindPendingByInvitee")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	logger.Input(map[string]interface{}{
+		"userID": userID,
+	})
+
+	objectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	invitations, err := r.findPending(ctx, bson.M{"invitee_id": objectID})
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	logger.Output(map[string]interface{}{
+		"count": len(invitations),
+	})
+	return invitations, nil
+}
+
+// findPending finds non-expired pending invitations matching filter, newest first
+func (r *TripInvitationRepository) findPending(ctx context.Context, filter bson.M) ([]*models.TripInvitation, error) {
+	filter["status"] = models.InvitationStatusPending
+	filter["$or"] = notExpiredFilter()
+
+	invitations := []*models.TripInvitation{}
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+
+	cursor, err := mgm.Coll(&models.TripInvitation{}).Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	if err = cursor.All(ctx, &invitations); err != nil {
+		return nil, err
+	}
+
+	return invitations, nil
+}
+
+// notExpiredFilter matches invitations without expiry or with expiry in the future
+func notExpiredFilter() bson.A {
+	return bson.A{
+		bson.M{"expires_at": nil},
+		bson.M{"expires_at": bson.M{"$gt": time.Now()}},
+	}
+}
+
+// ExistsPendingForInvitee checks if a trip already has a pending invitation for the user
+func (r *TripInvitationRepository) ExistsPendingForInvitee(ctx context.Context, tripID, userID string) (bool, error) {
+	ctx, span := r.tracer.Start(ctx, "TripInvitationRepository.ExistsPendingForInvitee")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	logger.Input(map[string]interface{}{
+		"tripID": tripID,
+		"userID": userID,
+	})
+
+	tripObjID, err := primitive.ObjectIDFromHex(tripID)
+	if err != nil {
+		logger.Error(err)
+		return false, err
+	}
+
+	userObjID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		logger.Error(err)
+		return false, err
+	}
+
+	count, err := mgm.Coll(&models.TripInvitation{}).CountDocuments(ctx, bson.M{
+		"trip_id":    tripObjID,
+		"invitee_id": userObjID,
+		"status":     models.InvitationStatusPending,
+		"$or":        notExpiredFilter(),
+	})
+	if err != nil {
+		logger.Error(err)
+		return false, err
+	}
+
+	logger.Output(map[string]interface{}{
+		"exists": count > 0,
+	})
+	return count > 0, nil
+}
+
+// ClaimUse atomically increments the use count of a usable link invitation
+// Returns false when the invitation is no longer pending, expired or used up
+func (r *TripInvitationRepository) ClaimUse(ctx context.Context, invitation *models.TripInvitation) (bool, error) {
+	ctx, span := r.tracer.Start(ctx, "TripInvitationRepository.ClaimUse")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	logger.Input(map[string]interface{}{
+		"invitationID": invitation.ID.Hex(),
+	})
+
+	now := time.Now()
+	result, err := mgm.Coll(invitation).UpdateOne(ctx, bson.M{
+		"_id":    invitation.ID,
+		"status": models.InvitationStatusPending,
+		"$and": bson.A{
+			bson.M{"$or": notExpiredFilter()},
+			bson.M{"$or": bson.A{
+				bson.M{"max_uses": nil},
+				bson.M{"$expr": bson.M{"$lt": bson.A{"$use_count", "$max_uses"}}},
+			}},
+		},
+	}, bson.M{
+		"$inc": bson.M{"use_count": 1},
+		"$set": bson.M{"updated_at": now},
+	})
+	if err != nil {
+		logger.Error(err)
+		return false, err
+	}
+
+	logger.Output(map[string]interface{}{
+		"claimed": result.ModifiedCount > 0,
+	})
+	return result.ModifiedCount > 0, nil
+}
+
+// ReleaseUse gives back a use claimed by ClaimUse when the join it was for failed
+func (r *TripInvitationRepository) ReleaseUse(ctx context.Context, invitation *models.TripInvitation) error {
+	ctx, span := r.tracer.Start(ctx, "TripInvitationRepository.ReleaseUse")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	logger.Input(map[string]interface{}{
+		"invitationID": invitation.ID.Hex(),
+	})
+
+	_, err := mgm.Coll(invitation).UpdateOne(ctx, bson.M{
+		"_id":       invitation.ID,
+		"use_count": bson.M{"$gt": 0},
+	}, bson.M{
+		"$inc": bson.M{"use_count": -1},
+		"$set": bson.M{"updated_at": time.Now()},
+	})
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	logger.Info("Invitation use released")
+	return nil
+}
+
+// Update updates an invitation
+func (r *TripInvitationRepository) Update(ctx context.Context, invitation *models.TripInvitation) error {
+	ctx, span := r.tracer.Start(ctx, "TripInvitationRepository.Update")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	logger.Input(map[string]interface{}{
+		"invitationID": invitation.ID.Hex(),
+		"status":       invitation.Status,
+	})
+
+	err := mgm.Coll(invitation).UpdateWithCtx(ctx, invitation)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	logger.Info("Invitation updated successfully")
+	return nil
+}
+
+// DeleteByTripID deletes all invitations for a trip
+func (r *TripInvitationRepository) DeleteByTripID(ctx context.Context, tripID string) error {
+	ctx, span := r.tracer.Start(ctx, "TripInvitationRepository.DeleteByTripID")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	logger.Input(map[string]interface{}{
+		"tripID": tripID,
+	})
+
+	objectID, err := primitive.ObjectIDFromHex(tripID)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	result, err := mgm.Coll(&models.TripInvitation{}).DeleteMany(ctx, bson.M{"trip_id": objectID})
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	logger.Output(map[string]interface{}{
+		"deletedCount": result.DeletedCount,
+	})
+	return nil
+}
@@ -4,11 +4,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
 	"time"
 
 	"github.com/kamva/mgm/v3"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/trace"
@@ -29,10 +31,69 @@ type TripFilter struct {
 	OwnerID  *string
 	MemberID *string
 	Tags     []string
+	IDs      []primitive.ObjectID // Restricts results to these trips
+	ViewerID *string              // Restricts results to trips the viewer may see in listings
 	Limit    int64
 	Offset   int64
 }
 
+// Cached interaction counters on trips
+const (
+	TripCounterViews     = "view_count"
+	TripCounterReactions = "reactions_count"
+	TripCounterBookmarks = "bookmark_count"
+	TripCounterShares    = "share_count"
+)
+
+// tripManagedFields are kept out of whole-trip updates, counters change through IncrementCounters and
+// SetCounters, entry titles through RefreshEntryTitles and the calendar feed token through
+// ClaimCalendarToken and ReplaceCalendarToken, none of which bump the version, so a stale trip
+// read earlier must not write them back
+var tripManagedFields = []string{
+	TripCounterViews,
+	TripCounterReactions,
+	TripCounterBookmarks,
+	TripCounterShares,
+	"entry_titles",
+	"calendar_token",
+}
+
+// TripGeoFilter combines listing filters with a geo constraint, results are sorted by distance from Lat/Lng
+type TripGeoFilter struct {
+	TripFilter
+	Lat           float64
+	Lng           float64
+	MaxDistanceKm *float64 // Radius search
+	Box           *GeoBox  // Bounding-box search
+}
+
+// GeoBox is a map viewport, SWLng may be greater than NELng when the box crosses the antimeridian
+type GeoBox struct {
+	SWLat float64
+	SWLng float64
+	NELat float64
+	NELng float64
+}
+
+// TripSearchFilter represents full-text search options
+type TripSearchFilter struct {
+	Query    string
+	Type     *string
+	Level    *string
+	Country  *string
+	Tags     []string
+	ViewerID *string // Restricts results to trips the viewer may see in listings
+	Limit    int64
+	Offset   int64
+}
+
+// TripSearchResult is a page of ranked trips with facet counts over all matches
+type TripSearchResult struct {
+	Trips  []*models.Trip
+	Total  int64
+	Facets schemas.TripSearchFacets
+}
+
 func NewTripRepository() *TripRepository {
 	return &TripRepository{
 		tracer: otel.Tracer("trip-repository"),
@@ -131,6 +192,98 @@ func (r *TripRepository) IsMemberExists(trip *models.Trip, userID string) bool {
 	return false
 }
 
+// GetMemberRole returns the trip role of userID, or empty string if the user is not a member
+func (r *TripRepository) GetMemberRole(trip *models.Trip, userID string) string {
+	// Owner always has owner role even if missing from trip_members
+	if r.IsOwner(trip, userID) {
+		return models.MemberRoleOwner
+	}
+
+	objID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return ""
+	}
+
+	for _, member := range trip.TripMembers {
+		if member.UserID == objID {
+			return member.Role
+		}
+	}
+
+	return ""
+}
+
+// HasRole checks if userID has one of the given roles in the trip
+func (r *TripRepository) HasRole(trip *models.Trip, userID string, roles ...string) bool {
+	role := r.GetMemberRole(trip, userID)
+	if role == "" {
+		return false
+	}
+
+	for _, allowed := range roles {
+		if role == allowed {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CanView checks if userID (empty for anonymous) can open the trip by direct link
+// Members always can, drafts are members only, unlisted and public trips are open to anyone
+func (r *TripRepository) CanView(trip *models.Trip, userID string) bool {
+	if userID != "" && r.GetMemberRole(trip, userID) != "" {
+		return true
+	}
+
+	if trip.Status == models.TripStatusDraft {
+		return false
+	}
+
+	return trip.EffectiveVisibility() != models.TripVisibilityPrivate
+}
+
+// EffectiveVisibilityExpr is models.Trip.EffectiveVisibility as an aggregation expression,
+// trips created before visibility existed (missing or empty) fall back on their status
+func EffectiveVisibilityExpr() bson.M {
+	return bson.M{
+		"$cond": bson.A{
+			bson.M{"$eq": bson.A{bson.M{"$ifNull": bson.A{"$visibility", ""}}, ""}},
+			bson.M{"$cond": bson.A{
+				bson.M{"$eq": bson.A{"$status", models.TripStatusPublished}},
+				models.TripVisibilityPublic,
+				models.TripVisibilityPrivate,
+			}},
+			"$visibility",
+		},
+	}
+}
+
+// listableFilter matches trips that may appear in listings for the viewer:
+// public non-draft trips (including published trips created before visibility existed)
+// plus every trip the viewer owns or is a member of
+func listableFilter(viewerID *primitive.ObjectID) bson.M {
+	conditions := bson.A{
+		bson.M{
+			"visibility": models.TripVisibilityPublic,
+			"status":     bson.M{"$ne": models.TripStatusDraft},
+		},
+		bson.M{
+			"visibility": bson.M{"$in": bson.A{nil, ""}},
+			"status":     models.TripStatusPublished,
+		},
+	}
+
+	if viewerID != nil {
+		conditions = append(conditions,
+			bson.M{"owner_id": *viewerID},
+			bson.M{"trip_members.user_id": *viewerID},
+		)
+	}
+
+	return bson.M{"$or": conditions}
+}
+
 // FindByID finds a trip by ID (excluding soft deleted)
 func (r *TripRepository) FindByID(ctx context.Context, id string) (*models.Trip, error) {
 	ctx, span := r.tracer.Start(ctx, "TripRepository.FindByID")
@@ -164,6 +317,96 @@ func (r *TripRepository) FindByID(ctx context.Context, id string) (*models.Trip,
 	return trip, nil
 }
 
+// FindByCalendarToken finds a trip by its calendar feed token (excluding soft deleted)
+func (r *TripRepository) FindByCalendarToken(ctx context.Context, token string) (*models.Trip, error) {
+	ctx, span := r.tracer.Start(ctx, "TripRepository.FindByCalendarToken")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	trip := &models.Trip{}
+	err := mgm.Coll(trip).FirstWithCtx(ctx, bson.M{
+		"calendar_token": token,
+		"deleted_at":     nil,
+	}, trip)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	logger.Output(map[string]interface{}{
+		"tripID": trip.ID.Hex(),
+	})
+	return trip, nil
+}
+
+// ClaimCalendarToken stores token as the trip calendar feed token unless the trip already has one,
+// and returns the token the trip ends up with. The feed token is not part of the edited trip and
+// does not bump the version.
+func (r *TripRepository) ClaimCalendarToken(ctx context.Context, tripID primitive.ObjectID, token string) (string, error) {
+	ctx, span := r.tracer.Start(ctx, "TripRepository.ClaimCalendarToken")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	logger.Input(map[string]interface{}{
+		"tripID": tripID.Hex(),
+	})
+
+	result, err := mgm.Coll(&models.Trip{}).UpdateOne(ctx,
+		bson.M{"_id": tripID, "calendar_token": nil},
+		bson.M{"$set": bson.M{"calendar_token": token}},
+	)
+	if err != nil {
+		logger.Error(err)
+		return "", err
+	}
+	if result.MatchedCount == 1 {
+		logger.Output("token claimed")
+		return token, nil
+	}
+
+	// Someone else claimed one first
+	trip := &models.Trip{}
+	if err := mgm.Coll(trip).FindByIDWithCtx(ctx, tripID, trip); err != nil {
+		logger.Error(err)
+		return "", err
+	}
+	if trip.CalendarToken == nil {
+		err := errors.New("calendar token not set")
+		logger.Error(err)
+		return "", err
+	}
+
+	logger.Output("existing token")
+	return *trip.CalendarToken, nil
+}
+
+// ReplaceCalendarToken swaps the calendar feed token of a trip that has one, nil revokes the feed
+// Returns false when the trip had no feed. Like ClaimCalendarToken it does not bump the version.
+func (r *TripRepository) ReplaceCalendarToken(ctx context.Context, tripID primitive.ObjectID, token *string) (bool, error) {
+	ctx, span := r.tracer.Start(ctx, "TripRepository.ReplaceCalendarToken")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	logger.Input(map[string]interface{}{
+		"tripID": tripID.Hex(),
+		"revoke": token == nil,
+	})
+
+	result, err := mgm.Coll(&models.Trip{}).UpdateOne(ctx,
+		bson.M{"_id": tripID, "calendar_token": bson.M{"$ne": nil}},
+		bson.M{"$set": bson.M{"calendar_token": token}},
+	)
+	if err != nil {
+		logger.Error(err)
+		return false, err
+	}
+
+	logger.Output(map[string]interface{}{
+		"replaced": result.MatchedCount == 1,
+	})
+	return result.MatchedCount == 1, nil
+}
+
 // Find finds trips using dynamic filters
 func (r *TripRepository) Find(ctx context.Context, filter *TripFilter) ([]*models.Trip, error) {
 	ctx, span := r.tracer.Start(ctx, "TripRepository.Find")
@@ -180,6 +423,55 @@ func (r *TripRepository) Find(ctx context.Context, filter *TripFilter) ([]*model
 		"offset":   filter.Offset,
 	})
 
+	mongoFilter, err := buildTripFilter(filter)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	// Set default limit if not provided
+	limit := filter.Limit
+	if limit == 0 {
+		limit = 20
+	}
+
+	// Build aggregation pipeline with owner lookup
+	pipeline := bson.A{
+		// Match stage
+		bson.M{"$match": mongoFilter},
+	}
+	pipeline = append(pipeline, tripLookupStages()...)
+	pipeline = append(pipeline,
+		// Sort by created_at descending
+		bson.M{"$sort": bson.M{"created_at": -1}},
+
+		// Pagination
+		bson.M{"$skip": filter.Offset},
+		bson.M{"$limit": limit},
+	)
+
+	// Execute aggregation
+	trips := []*models.Trip{}
+	cursor, err := mgm.Coll(&models.Trip{}).Aggregate(ctx, pipeline)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	if err = cursor.All(ctx, &trips); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	logger.Output(map[string]interface{}{
+		"count": len(trips),
+	})
+	return trips, nil
+}
+
+// buildTripFilter builds the match stage shared by listing and geo queries
+func buildTripFilter(filter *TripFilter) (bson.M, error) {
 	// Build MongoDB filter dynamically
 	mongoFilter := bson.M{
 		"deleted_at": nil, // Exclude soft deleted
@@ -196,7 +488,6 @@ func (r *TripRepository) Find(ctx context.Context, filter *TripFilter) ([]*model
 	if filter.OwnerID != nil {
 		ownerObjID, err := primitive.ObjectIDFromHex(*filter.OwnerID)
 		if err != nil {
-			logger.Error(err)
 			return nil, fmt.Errorf("invalid owner ID: %w", err)
 		}
 		mongoFilter["owner_id"] = ownerObjID
@@ -205,7 +496,6 @@ func (r *TripRepository) Find(ctx context.Context, filter *TripFilter) ([]*model
 	if filter.MemberID != nil {
 		memberObjID, err := primitive.ObjectIDFromHex(*filter.MemberID)
 		if err != nil {
-			logger.Error(err)
 			return nil, fmt.Errorf("invalid member ID: %w", err)
 		}
 		mongoFilter["trip_members.user_id"] = memberObjID
@@ -215,17 +505,27 @@ func (r *TripRepository) Find(ctx context.Context, filter *TripFilter) ([]*model
 		mongoFilter["tags"] = bson.M{"$in": filter.Tags}
 	}
 
-	// Set default limit if not provided
-	limit := filter.Limit
-	if limit == 0 {
-		limit = 20
+	if filter.IDs != nil {
+		mongoFilter["_id"] = bson.M{"$in": filter.IDs}
 	}
 
-	// Build aggregation pipeline with owner lookup
-	pipeline := bson.A{
-		// Match stage
-		bson.M{"$match": mongoFilter},
+	// Visibility rules: anonymous viewers only see listable public trips
+	var viewerObjID *primitive.ObjectID
+	if filter.ViewerID != nil && *filter.ViewerID != "" {
+		objID, err := primitive.ObjectIDFromHex(*filter.ViewerID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid viewer ID: %w", err)
+		}
+		viewerObjID = &objID
+	}
+	mongoFilter["$and"] = bson.A{listableFilter(viewerObjID)}
 
+	return mongoFilter, nil
+}
+
+// tripLookupStages populates owner and member user data on trips
+func tripLookupStages() bson.A {
+	return bson.A{
 		// Lookup owner user data
 		bson.M{"$lookup": bson.M{
 			"from":         "users",
@@ -295,16 +595,66 @@ func (r *TripRepository) Find(ctx context.Context, filter *TripFilter) ([]*model
 		bson.M{"$project": bson.M{
 			"member_users": 0,
 		}},
+	}
+}
 
-		// Sort by created_at descending
-		bson.M{"$sort": bson.M{"created_at": -1}},
+// FindByGeo finds trips near a point or inside a bounding box, nearest first
+func (r *TripRepository) FindByGeo(ctx context.Context, filter *TripGeoFilter) ([]*models.Trip, error) {
+	ctx, span := r.tracer.Start(ctx, "TripRepository.FindByGeo")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
 
-		// Pagination
+	logger.Input(map[string]interface{}{
+		"lat":           filter.Lat,
+		"lng":           filter.Lng,
+		"maxDistanceKm": filter.MaxDistanceKm,
+		"box":           filter.Box,
+		"type":          filter.Type,
+		"status":        filter.Status,
+		"tags":          filter.Tags,
+		"limit":         filter.Limit,
+		"offset":        filter.Offset,
+	})
+
+	mongoFilter, err := buildTripFilter(&filter.TripFilter)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	if filter.Box != nil {
+		within := bson.A{}
+		for _, polygon := range boxPolygons(filter.Box) {
+			within = append(within, bson.M{"location": bson.M{"$geoWithin": bson.M{"$geometry": polygon}}})
+		}
+		mongoFilter["$and"] = append(mongoFilter["$and"].(bson.A), bson.M{"$or": within})
+	}
+
+	geoNear := bson.M{
+		"near":               bson.M{"type": "Point", "coordinates": bson.A{filter.Lng, filter.Lat}},
+		"key":                "location",
+		"distanceField":      "distance_km",
+		"distanceMultiplier": 0.001, // meters to km
+		"spherical":          true,
+		"query":              mongoFilter,
+	}
+	if filter.MaxDistanceKm != nil {
+		geoNear["maxDistance"] = *filter.MaxDistanceKm * 1000
+	}
+
+	limit := filter.Limit
+	if limit == 0 {
+		limit = 20
+	}
+
+	// $geoNear must be the first stage and already sorts by distance
+	pipeline := bson.A{
+		bson.M{"$geoNear": geoNear},
 		bson.M{"$skip": filter.Offset},
 		bson.M{"$limit": limit},
 	}
+	pipeline = append(pipeline, tripLookupStages()...)
 
-	// Execute aggregation
 	trips := []*models.Trip{}
 	cursor, err := mgm.Coll(&models.Trip{}).Aggregate(ctx, pipeline)
 	if err != nil {
@@ -313,36 +663,311 @@ func (r *TripRepository) Find(ctx context.Context, filter *TripFilter) ([]*model
 	}
 	defer cursor.Close(ctx)
 
-	if err = cursor.All(ctx, &trips); err != nil {
+	if err = cursor.All(ctx, &trips); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	logger.Output(map[string]interface{}{
+		"count": len(trips),
+	})
+	return trips, nil
+}
+
+// boxPolygons splits a viewport into GeoJSON polygons at most 90° wide
+// Spherical polygons must be smaller than a hemisphere and can't cross the antimeridian,
+// narrow chunks also keep the geodesic edges close to the viewport parallels
+func boxPolygons(box *GeoBox) []bson.M {
+	west, east := box.SWLng, box.NELng
+	if east <= west {
+		east += 360
+	}
+
+	segments := [][2]float64{{west, east}}
+	if east > 180 {
+		segments = [][2]float64{{west, 180}, {-180, east - 360}}
+	}
+
+	polygons := []bson.M{}
+	for _, segment := range segments {
+		for w := segment[0]; w < segment[1]; w += 90 {
+			e := math.Min(w+90, segment[1])
+			polygons = append(polygons, bson.M{
+				"type": "Polygon",
+				"coordinates": bson.A{bson.A{
+					bson.A{w, box.SWLat},
+					bson.A{e, box.SWLat},
+					bson.A{e, box.NELat},
+					bson.A{w, box.NELat},
+					bson.A{w, box.SWLat},
+				}},
+			})
+		}
+	}
+	return polygons
+}
+
+// BackfillLocations derives the GeoJSON location of trips saved before geo queries existed
+// Trips with out-of-range coordinates are left out so the 2dsphere index can be built
+func (r *TripRepository) BackfillLocations(ctx context.Context) (int64, error) {
+	ctx, span := r.tracer.Start(ctx, "TripRepository.BackfillLocations")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	filter := bson.M{
+		"location":                     bson.M{"$exists": false},
+		"destinations.coordinates.lat": bson.M{"$gte": -90, "$lte": 90},
+		"destinations.coordinates.lng": bson.M{"$gte": -180, "$lte": 180},
+	}
+	update := mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{
+			"location": bson.M{
+				"type":        "Point",
+				"coordinates": bson.A{"$destinations.coordinates.lng", "$destinations.coordinates.lat"},
+			},
+		}}},
+	}
+
+	result, err := mgm.Coll(&models.Trip{}).UpdateMany(ctx, filter, update)
+	if err != nil {
+		logger.Error(err)
+		return 0, err
+	}
+
+	logger.Output(map[string]interface{}{
+		"updated": result.ModifiedCount,
+	})
+	return result.ModifiedCount, nil
+}
+
+// Update updates a trip, failing with ErrVersionConflict if it changed since it was read
+func (r *TripRepository) Update(ctx context.Context, trip *models.Trip) error {
+	ctx, span := r.tracer.Start(ctx, "TripRepository.Update")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	logger.Input(map[string]interface{}{
+		"tripID": trip.ID.Hex(),
+		"title":  trip.Title,
+	})
+
+	err := updateVersioned(ctx, trip, &trip.Version, tripManagedFields...)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	logger.Info("Trip updated successfully")
+	return nil
+}
+
+// AddMember atomically appends a member unless the user already is one
+// Returns false when the user was already a member, concurrent adds can't duplicate or drop members.
+func (r *TripRepository) AddMember(ctx context.Context, tripID primitive.ObjectID, member *models.TripMember) (bool, error) {
+	ctx, span := r.tracer.Start(ctx, "TripRepository.AddMember")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	logger.Input(map[string]interface{}{
+		"tripID": tripID.Hex(),
+		"userID": member.UserID.Hex(),
+		"role":   member.Role,
+	})
+
+	result, err := mgm.Coll(&models.Trip{}).UpdateOne(ctx, bson.M{
+		"_id":                  tripID,
+		"deleted_at":           nil,
+		"trip_members.user_id": bson.M{"$ne": member.UserID},
+	}, bson.M{
+		"$push": bson.M{"trip_members": member},
+		"$inc":  bson.M{"version": 1},
+		"$set":  bson.M{"updated_at": time.Now()},
+	})
+	if err != nil {
+		logger.Error(err)
+		return false, err
+	}
+
+	logger.Output(map[string]interface{}{
+		"added": result.ModifiedCount > 0,
+	})
+	return result.ModifiedCount > 0, nil
+}
+
+// IncrementCounters atomically adds deltas to cached interaction counters and returns the updated trip
+// Counters never go below zero, and updated_at is left alone since counters aren't edits
+func (r *TripRepository) IncrementCounters(ctx context.Context, tripID primitive.ObjectID, deltas map[string]int) (*models.Trip, error) {
+	ctx, span := r.tracer.Start(ctx, "TripRepository.IncrementCounters")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	logger.Input(map[string]interface{}{
+		"tripID": tripID.Hex(),
+		"deltas": deltas,
+	})
+
+	set := bson.M{}
+	for field, delta := range deltas {
+		set[field] = bson.M{"$max": bson.A{
+			0,
+			bson.M{"$add": bson.A{bson.M{"$ifNull": bson.A{"$" + field, 0}}, delta}},
+		}}
+	}
+
+	trip := &models.Trip{}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	err := mgm.Coll(trip).FindOneAndUpdate(ctx,
+		bson.M{"_id": tripID, "deleted_at": nil},
+		bson.A{bson.M{"$set": set}},
+		opts,
+	).Decode(trip)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	logger.Output(map[string]interface{}{
+		"viewCount":      trip.ViewCount,
+		"reactionsCount": trip.ReactionsCount,
+		"bookmarkCount":  trip.BookmarkCount,
+		"shareCount":     trip.ShareCount,
+	})
+	return trip, nil
+}
+
+// FindCountersBatch reads the cached counters of up to limit trips after afterID, soft deleted trips included
+func (r *TripRepository) FindCountersBatch(ctx context.Context, afterID primitive.ObjectID, limit int64) ([]*models.Trip, error) {
+	ctx, span := r.tracer.Start(ctx, "TripRepository.FindCountersBatch")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	logger.Input(map[string]interface{}{
+		"afterID": afterID.Hex(),
+		"limit":   limit,
+	})
+
+	trips := []*models.Trip{}
+	err := findBatchAfter(ctx, mgm.Coll(&models.Trip{}), &trips, afterID, limit, bson.M{
+		TripCounterViews:     1,
+		TripCounterReactions: 1,
+		TripCounterBookmarks: 1,
+		TripCounterShares:    1,
+	})
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	logger.Output(map[string]interface{}{
+		"count": len(trips),
+	})
+	return trips, nil
+}
+
+// SetCounters overwrites cached counters of many trips, keyed by trip ID then counter field
+func (r *TripRepository) SetCounters(ctx context.Context, counters map[primitive.ObjectID]map[string]int) (int64, error) {
+	ctx, span := r.tracer.Start(ctx, "TripRepository.SetCounters")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	logger.Input(map[string]interface{}{
+		"trips": len(counters),
+	})
+
+	modified, err := setCounters(ctx, mgm.Coll(&models.Trip{}), counters)
+	if err != nil {
+		logger.Error(err)
+		return 0, err
+	}
+
+	logger.Output(map[string]interface{}{
+		"modified": modified,
+	})
+	return modified, nil
+}
+
+// PublishScheduled publishes drafts whose scheduled publish time has passed
+func (r *TripRepository) PublishScheduled(ctx context.Context, now time.Time) (int64, error) {
+	ctx, span := r.tracer.Start(ctx, "TripRepository.PublishScheduled")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	logger.Input(map[string]interface{}{
+		"now": now,
+	})
+
+	result, err := mgm.Coll(&models.Trip{}).UpdateMany(ctx, bson.M{
+		"status":     models.TripStatusDraft,
+		"publish_at": bson.M{"$lte": now},
+		"deleted_at": nil,
+	}, bson.M{
+		"$set": bson.M{
+			"status":     models.TripStatusPublished,
+			"publish_at": nil,
+			"updated_at": now,
+		},
+		"$inc": bson.M{"version": 1},
+	})
+	if err != nil {
 		logger.Error(err)
-		return nil, err
+		return 0, err
 	}
 
 	logger.Output(map[string]interface{}{
-		"count": len(trips),
+		"published": result.ModifiedCount,
 	})
-	return trips, nil
+	return result.ModifiedCount, nil
 }
 
-// Update updates a trip
-func (r *TripRepository) Update(ctx context.Context, trip *models.Trip) error {
-	ctx, span := r.tracer.Start(ctx, "TripRepository.Update")
+// ArchiveEndedBefore archives trips that ended before cutoff
+// The owner filter limits the sweep to ownerIDs, or to everyone else when excludeOwners is set.
+// Guides, trips without dates, scheduled drafts and trips the owner unarchived are left alone.
+func (r *TripRepository) ArchiveEndedBefore(ctx context.Context, cutoff, now time.Time, ownerIDs []primitive.ObjectID, excludeOwners bool) (int64, error) {
+	ctx, span := r.tracer.Start(ctx, "TripRepository.ArchiveEndedBefore")
 	defer span.End()
 	logger := utils.NewTraceLogger(ctx, span)
 
 	logger.Input(map[string]interface{}{
-		"tripID": trip.ID.Hex(),
-		"title":  trip.Title,
+		"cutoff":        cutoff,
+		"owners":        len(ownerIDs),
+		"excludeOwners": excludeOwners,
 	})
 
-	err := mgm.Coll(trip).UpdateWithCtx(ctx, trip)
+	filter := bson.M{
+		"type":          models.TripTypeTrip,
+		"status":        bson.M{"$in": bson.A{models.TripStatusDraft, models.TripStatusPublished}},
+		"end_date":      bson.M{"$gt": time.Time{}, "$lt": cutoff},
+		"publish_at":    nil,
+		"unarchived_at": bson.M{"$exists": false},
+		"deleted_at":    nil,
+	}
+	if excludeOwners {
+		filter["owner_id"] = bson.M{"$nin": ownerIDs}
+	} else {
+		filter["owner_id"] = bson.M{"$in": ownerIDs}
+	}
+
+	update := mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{
+			"status_before_archive": "$status",
+			"visibility":            EffectiveVisibilityExpr(),
+			"status":                models.TripStatusArchived,
+			"archived_at":           now,
+			"updated_at":            now,
+			"version":               bson.M{"$add": bson.A{bson.M{"$ifNull": bson.A{"$version", 0}}, 1}},
+		}}},
+	}
+
+	result, err := mgm.Coll(&models.Trip{}).UpdateMany(ctx, filter, update)
 	if err != nil {
 		logger.Error(err)
-		return err
+		return 0, err
 	}
 
-	logger.Info("Trip updated successfully")
-	return nil
+	logger.Output(map[string]interface{}{
+		"archived": result.ModifiedCount,
+	})
+	return result.ModifiedCount, nil
 }
 
 // SoftDelete soft deletes a trip
@@ -369,7 +994,7 @@ func (r *TripRepository) SoftDelete(ctx context.Context, id string) error {
 	}
 
 	trip.SoftDelete()
-	err = mgm.Coll(trip).UpdateWithCtx(ctx, trip)
+	err = updateVersioned(ctx, trip, &trip.Version, tripManagedFields...)
 	if err != nil {
 		logger.Error(err)
 		return err
@@ -379,6 +1004,122 @@ func (r *TripRepository) SoftDelete(ctx context.Context, id string) error {
 	return nil
 }
 
+// FindDeletedByOwner lists an owner's soft deleted trips, most recently deleted first
+func (r *TripRepository) FindDeletedByOwner(ctx context.Context, ownerID string, skip, limit int64) ([]*models.Trip, error) {
+	ctx, span := r.tracer.Start(ctx, "TripRepository.FindDeletedByOwner")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	logger.Input(map[string]interface{}{
+		"ownerID": ownerID,
+		"skip":    skip,
+		"limit":   limit,
+	})
+
+	objectID, err := primitive.ObjectIDFromHex(ownerID)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "deleted_at", Value: -1}}).
+		SetSkip(skip).
+		SetLimit(limit)
+
+	trips := []*models.Trip{}
+	err = mgm.Coll(&models.Trip{}).SimpleFindWithCtx(ctx, &trips, bson.M{
+		"owner_id":   objectID,
+		"deleted_at": bson.M{"$ne": nil},
+	}, opts)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	logger.Output(map[string]interface{}{
+		"count": len(trips),
+	})
+	return trips, nil
+}
+
+// FindDeletedByID finds a soft deleted trip by ID
+func (r *TripRepository) FindDeletedByID(ctx context.Context, id string) (*models.Trip, error) {
+	ctx, span := r.tracer.Start(ctx, "TripRepository.FindDeletedByID")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	logger.Input(map[string]interface{}{
+		"tripID": id,
+	})
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	trip := &models.Trip{}
+	err = mgm.Coll(trip).FirstWithCtx(ctx, bson.M{
+		"_id":        objectID,
+		"deleted_at": bson.M{"$ne": nil},
+	}, trip)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	logger.Output(map[string]interface{}{
+		"title":     trip.Title,
+		"deletedAt": trip.DeletedAt,
+	})
+	return trip, nil
+}
+
+// FindIDsDeletedBefore finds up to limit trips soft deleted before cutoff
+func (r *TripRepository) FindIDsDeletedBefore(ctx context.Context, cutoff time.Time, limit int64) ([]primitive.ObjectID, error) {
+	ctx, span := r.tracer.Start(ctx, "TripRepository.FindIDsDeletedBefore")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	logger.Input(map[string]interface{}{
+		"cutoff": cutoff,
+		"limit":  limit,
+	})
+
+	opts := options.Find().
+		SetProjection(bson.M{"_id": 1}).
+		SetSort(bson.D{{Key: "deleted_at", Value: 1}}).
+		SetLimit(limit)
+
+	cursor, err := mgm.Coll(&models.Trip{}).Find(ctx, bson.M{
+		"deleted_at": bson.M{"$ne": nil, "$lt": cutoff},
+	}, opts)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var results []struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	ids := make([]primitive.ObjectID, 0, len(results))
+	for _, result := range results {
+		ids = append(ids, result.ID)
+	}
+
+	logger.Output(map[string]interface{}{
+		"count": len(ids),
+	})
+	return ids, nil
+}
+
 // Delete hard deletes a trip
 func (r *TripRepository) Delete(ctx context.Context, id string) error {
 	ctx, span := r.tracer.Start(ctx, "TripRepository.Delete")
@@ -405,6 +1146,239 @@ func (r *TripRepository) Delete(ctx context.Context, id string) error {
 	return nil
 }
 
+// RefreshEntryTitles recomputes the denormalized entry titles used by the full-text index
+func (r *TripRepository) RefreshEntryTitles(ctx context.Context, tripID primitive.ObjectID) error {
+	ctx, span := r.tracer.Start(ctx, "TripRepository.RefreshEntryTitles")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	logger.Input(map[string]interface{}{
+		"tripID": tripID.Hex(),
+	})
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"trip_id": tripID}}},
+		{{Key: "$lookup", Value: bson.M{
+			"from":         "itinerary_entries",
+			"localField":   "_id",
+			"foreignField": "itinerary_id",
+			"as":           "entries",
+		}}},
+		{{Key: "$unwind", Value: "$entries"}},
+		{{Key: "$group", Value: bson.M{
+			"_id":    nil,
+			"titles": bson.M{"$addToSet": "$entries.title"},
+		}}},
+	}
+
+	cursor, err := mgm.Coll(&models.Itinerary{}).Aggregate(ctx, pipeline)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	var results []struct {
+		Titles []string `bson:"titles"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	titles := []string{}
+	if len(results) > 0 {
+		titles = results[0].Titles
+	}
+
+	_, err = mgm.Coll(&models.Trip{}).UpdateOne(ctx,
+		bson.M{"_id": tripID},
+		bson.M{"$set": bson.M{"entry_titles": titles}},
+	)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	logger.Output(map[string]interface{}{
+		"titles": len(titles),
+	})
+	return nil
+}
+
+// FindIDsMissingEntryTitles returns trips created before entry titles were denormalized
+func (r *TripRepository) FindIDsMissingEntryTitles(ctx context.Context, limit int64) ([]primitive.ObjectID, error) {
+	ctx, span := r.tracer.Start(ctx, "TripRepository.FindIDsMissingEntryTitles")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	opts := options.Find().SetProjection(bson.M{"_id": 1}).SetLimit(limit)
+	cursor, err := mgm.Coll(&models.Trip{}).Find(ctx, bson.M{
+		"entry_titles": bson.M{"$exists": false},
+	}, opts)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var results []struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	ids := make([]primitive.ObjectID, 0, len(results))
+	for _, result := range results {
+		ids = append(ids, result.ID)
+	}
+
+	logger.Output(map[string]interface{}{
+		"count": len(ids),
+	})
+	return ids, nil
+}
+
+// Search runs a ranked full-text search over title, description, tags, destination and entry titles
+// Facets are counted over every match, not only the returned page
+func (r *TripRepository) Search(ctx context.Context, filter *TripSearchFilter) (*TripSearchResult, error) {
+	ctx, span := r.tracer.Start(ctx, "TripRepository.Search")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	logger.Input(map[string]interface{}{
+		"query":  filter.Query,
+		"type":   filter.Type,
+		"level":  filter.Level,
+		"tags":   filter.Tags,
+		"limit":  filter.Limit,
+		"offset": filter.Offset,
+	})
+
+	var viewerObjID *primitive.ObjectID
+	if filter.ViewerID != nil && *filter.ViewerID != "" {
+		objID, err := primitive.ObjectIDFromHex(*filter.ViewerID)
+		if err != nil {
+			logger.Error(err)
+			return nil, fmt.Errorf("invalid viewer ID: %w", err)
+		}
+		viewerObjID = &objID
+	}
+
+	match := bson.M{
+		"$text":      bson.M{"$search": filter.Query},
+		"deleted_at": nil,
+		"$and":       bson.A{listableFilter(viewerObjID)},
+	}
+	if filter.Type != nil {
+		match["type"] = *filter.Type
+	}
+	if filter.Level != nil {
+		match["level"] = *filter.Level
+	}
+	if filter.Country != nil {
+		match["destinations.country"] = *filter.Country
+	}
+	if len(filter.Tags) > 0 {
+		match["tags"] = bson.M{"$all": filter.Tags}
+	}
+
+	limit := filter.Limit
+	if limit == 0 {
+		limit = 20
+	}
+
+	facetCount := func(field string, max int) bson.A {
+		return bson.A{
+			bson.M{"$match": bson.M{field: bson.M{"$nin": bson.A{nil, ""}}}},
+			bson.M{"$group": bson.M{"_id": "$" + field, "count": bson.M{"$sum": 1}}},
+			bson.M{"$sort": bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}},
+			bson.M{"$limit": max},
+		}
+	}
+
+	pipeline := bson.A{
+		bson.M{"$match": match},
+		bson.M{"$addFields": bson.M{"score": bson.M{"$meta": "textScore"}}},
+		bson.M{"$facet": bson.M{
+			"trips": bson.A{
+				bson.M{"$sort": bson.D{{Key: "score", Value: -1}, {Key: "created_at", Value: -1}}},
+				bson.M{"$skip": filter.Offset},
+				bson.M{"$limit": limit},
+				bson.M{"$lookup": bson.M{
+					"from":         "users",
+					"localField":   "owner_id",
+					"foreignField": "_id",
+					"as":           "owner",
+					"pipeline": bson.A{
+						bson.M{"$project": bson.M{
+							"_id":       1,
+							"name":      1,
+							"photo_url": 1,
+						}},
+					},
+				}},
+				bson.M{"$unwind": bson.M{
+					"path":                       "$owner",
+					"preserveNullAndEmptyArrays": true,
+				}},
+			},
+			"total":   bson.A{bson.M{"$count": "count"}},
+			"type":    facetCount("type", 10),
+			"level":   facetCount("level", 10),
+			"country": facetCount("destinations.country", 20),
+			"tags": append(bson.A{
+				bson.M{"$unwind": "$tags"},
+			}, facetCount("tags", 20)...),
+		}},
+	}
+
+	cursor, err := mgm.Coll(&models.Trip{}).Aggregate(ctx, pipeline)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var results []struct {
+		Trips []*models.Trip `bson:"trips"`
+		Total []struct {
+			Count int64 `bson:"count"`
+		} `bson:"total"`
+		schemas.TripSearchFacets `bson:",inline"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	result := &TripSearchResult{Trips: []*models.Trip{}}
+	if len(results) > 0 {
+		if results[0].Trips != nil {
+			result.Trips = results[0].Trips
+		}
+		if len(results[0].Total) > 0 {
+			result.Total = results[0].Total[0].Count
+		}
+		result.Facets = results[0].TripSearchFacets
+	}
+
+	// Always return arrays so clients don't need null checks
+	for _, facet := range []*[]schemas.FacetCount{&result.Facets.Type, &result.Facets.Level, &result.Facets.Country, &result.Facets.Tags} {
+		if *facet == nil {
+			*facet = []schemas.FacetCount{}
+		}
+	}
+
+	logger.Output(map[string]interface{}{
+		"count": len(result.Trips),
+		"total": result.Total,
+	})
+	return result, nil
+}
+
 // SearchByTitle searches trips by title
 func (r *TripRepository) SearchByTitle(ctx context.Context, query string, skip, limit int64) ([]*models.Trip, error) {
 	ctx, span := r.tracer.Start(ctx, "TripRepository.SearchByTitle")
@@ -464,23 +1438,9 @@ func (r *TripRepository) Count(ctx context.Context) (int64, error) {
 	return count, nil
 }
 
-// FindByIDWithFullData gets trip with itineraries, entries, expenses, owner, and members in 1 query
-func (r *TripRepository) FindByIDWithFullData(ctx context.Context, id string) (*schemas.TripDetailResponse, error) {
-	ctx, span := r.tracer.Start(ctx, "TripRepository.FindByIDWithFullData")
-	defer span.End()
-	logger := utils.NewTraceLogger(ctx, span)
-
-	logger.Input(map[string]interface{}{
-		"tripID": id,
-	})
-
-	objectID, err := primitive.ObjectIDFromHex(id)
-	if err != nil {
-		logger.Error(err)
-		return nil, err
-	}
-
-	pipeline := bson.A{
+// tripDetailPipeline loads a trip with owner, members, days, entries and expenses as a TripDetailResponse
+func tripDetailPipeline(objectID primitive.ObjectID) bson.A {
+	return bson.A{
 		// Match trip (exclude soft deleted)
 		bson.M{"$match": bson.M{
 			"_id":        objectID,
@@ -630,13 +1590,23 @@ func (r *TripRepository) FindByIDWithFullData(ctx context.Context, id string) (*
 			"tags":             1,
 			"status":           1,
 			"type":             1,
+			"visibility":       EffectiveVisibilityExpr(),
 			"level":            1,
 			"view_count":       1,
 			"reactions_count":  1,
 			"bookmark_count":   1,
 			"share_count":      1,
+			"forked_from_id": bson.M{
+				"$cond": bson.A{
+					bson.M{"$ifNull": bson.A{"$forked_from_id", false}},
+					bson.M{"$toString": "$forked_from_id"},
+					"$$REMOVE",
+				},
+			},
 			"created_at":       1,
 			"updated_at":       1,
+			// Sent as the ETag, If-Match on later edits compares against it
+			"version": 1,
 			// Convert owner._id to string
 			"owner": bson.M{
 				"_id":       bson.M{"$toString": "$owner._id"},
@@ -679,6 +1649,7 @@ func (r *TripRepository) FindByIDWithFullData(ctx context.Context, id string) (*
 						"date":       "$$itin.date",
 						"title":      "$$itin.title",
 						"order":      "$$itin.order",
+						"version":    "$$itin.version",
 						"created_at": "$$itin.created_at",
 						"updated_at": "$$itin.updated_at",
 						// Convert entries IDs to strings
@@ -708,6 +1679,7 @@ func (r *TripRepository) FindByIDWithFullData(ctx context.Context, id string) (*
 									"unsplash_photos": "$$entry.unsplash_photos",
 									"order":           "$$entry.order",
 									"todos":           "$$entry.todos",
+									"version":         "$$entry.version",
 									"created_at":      "$$entry.created_at",
 									"updated_at":      "$$entry.updated_at",
 								},
@@ -741,6 +1713,25 @@ func (r *TripRepository) FindByIDWithFullData(ctx context.Context, id string) (*
 			},
 		}},
 	}
+}
+
+// FindByIDWithFullData gets trip with itineraries, entries, expenses, owner, and members in 1 query
+func (r *TripRepository) FindByIDWithFullData(ctx context.Context, id string) (*schemas.TripDetailResponse, error) {
+	ctx, span := r.tracer.Start(ctx, "TripRepository.FindByIDWithFullData")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	logger.Input(map[string]interface{}{
+		"tripID": id,
+	})
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	pipeline := tripDetailPipeline(objectID)
 
 	cursor, err := mgm.Coll(&models.Trip{}).Aggregate(ctx, pipeline)
 	if err != nil {
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"testing"
+
+	"backend-go/internal/schemas"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// The trip detail feeds GET /trips/:id's ETag, without version it always read 0 and every
+// If-Match sent back after the first edit failed
+func TestTripDetailPipelineProjectsVersions(t *testing.T) {
+	pipeline := tripDetailPipeline(primitive.NewObjectID())
+	project := pipeline[len(pipeline)-1].(bson.M)["$project"].(bson.M)
+
+	if _, ok := project["version"]; !ok {
+		t.Fatal("trip projection is missing version")
+	}
+
+	itinerary := project["itineraries"].(bson.M)["$map"].(bson.M)["in"].(bson.M)
+	if itinerary["version"] != "$$itin.version" {
+		t.Fatalf("itinerary projection version = %v", itinerary["version"])
+	}
+
+	entry := itinerary["entries"].(bson.M)["$map"].(bson.M)["in"].(bson.M)
+	if entry["version"] != "$$entry.version" {
+		t.Fatalf("entry projection version = %v", entry["version"])
+	}
+}
+
+func TestTripDetailDecodesVersions(t *testing.T) {
+	data, err := bson.Marshal(bson.M{
+		"_id":     primitive.NewObjectID().Hex(),
+		"version": int64(3),
+		"itineraries": bson.A{bson.M{
+			"version": int64(2),
+			"entries": bson.A{bson.M{"version": int64(5)}},
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var detail schemas.TripDetailResponse
+	if err := bson.Unmarshal(data, &detail); err != nil {
+		t.Fatal(err)
+	}
+
+	if detail.Version != 3 {
+		t.Fatalf("trip version = %d, want 3", detail.Version)
+	}
+	if got := detail.Itineraries[0].Version; got != 2 {
+		t.Fatalf("itinerary version = %d, want 2", got)
+	}
+	if got := detail.Itineraries[0].Entries[0].Version; got != 5 {
+		t.Fatalf("entry version = %d, want 5", got)
+	}
+}
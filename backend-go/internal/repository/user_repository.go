@@ -275,3 +275,32 @@ func (r *UserRepository) Search(ctx context.Context, query string, limit int64)
 	})
 	return users, nil
 }
+
+// FindAutoArchiveOverrides returns the owners who set their own auto-archive delay, keyed by user ID
+func (r *UserRepository) FindAutoArchiveOverrides(ctx context.Context) (map[primitive.ObjectID]int, error) {
+	ctx, span := r.tracer.Start(ctx, "UserRepository.FindAutoArchiveOverrides")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	users := []*models.User{}
+	opts := options.Find().SetProjection(bson.M{"settings.auto_archive_after_days": 1})
+	err := mgm.Coll(&models.User{}).SimpleFindWithCtx(ctx, &users, bson.M{
+		"settings.auto_archive_after_days": bson.M{"$type": "number"},
+	}, opts)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	overrides := make(map[primitive.ObjectID]int, len(users))
+	for _, user := range users {
+		if user.Settings != nil && user.Settings.AutoArchiveAfterDays != nil {
+			overrides[user.ID] = *user.Settings.AutoArchiveAfterDays
+		}
+	}
+
+	logger.Output(map[string]interface{}{
+		"count": len(overrides),
+	})
+	return overrides, nil
+}
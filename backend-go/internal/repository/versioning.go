@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/kamva/mgm/v3"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// ErrVersionConflict means the document changed after it was read, the caller should reload and retry
+var ErrVersionConflict = errors.New("version conflict")
+
+// updateVersioned saves model only while the stored version still equals *version, then bumps *version
+// Documents written before versioning have no version field and count as version 0.
+// managedFields are left as stored, they are only written by their own targeted updates.
+func updateVersioned(ctx context.Context, model mgm.Model, version *int64, managedFields ...string) error {
+	// Same hooks mgm runs on Update (timestamps, derived fields)
+	if hook, ok := model.(mgm.UpdatingHook); ok {
+		if err := hook.Updating(); err != nil {
+			return err
+		}
+	}
+	if hook, ok := model.(mgm.SavingHook); ok {
+		if err := hook.Saving(); err != nil {
+			return err
+		}
+	}
+
+	expected := *version
+	filter := bson.M{"_id": model.GetID(), "version": expected}
+	if expected == 0 {
+		filter["version"] = bson.M{"$in": bson.A{0, nil}}
+	}
+
+	*version = expected + 1
+	set, err := setDocument(model, managedFields)
+	if err != nil {
+		*version = expected
+		return err
+	}
+
+	result, err := mgm.Coll(model).UpdateOne(ctx, filter, bson.M{"$set": set})
+	if err != nil {
+		*version = expected
+		return err
+	}
+	if result.MatchedCount == 0 {
+		*version = expected
+		return ErrVersionConflict
+	}
+	return nil
+}
+
+// setDocument is model as a $set document without the managed fields
+func setDocument(model mgm.Model, managedFields []string) (interface{}, error) {
+	if len(managedFields) == 0 {
+		return model, nil
+	}
+
+	data, err := bson.Marshal(model)
+	if err != nil {
+		return nil, err
+	}
+	set := bson.M{}
+	if err := bson.Unmarshal(data, &set); err != nil {
+		return nil, err
+	}
+	for _, field := range managedFields {
+		delete(set, field)
+	}
+	return set, nil
+}
@@ -1,6 +1,10 @@
 package schemas
 
-import "backend-go/internal/models"
+import (
+	"encoding/json"
+
+	"backend-go/internal/models"
+)
 
 // Itinerary schemas
 type CreateItineraryRequest struct {
@@ -70,6 +74,123 @@ type ReorderEntriesRequest struct {
 	EntryIDs []string `json:"entryIds" binding:"required,min=1"`
 }
 
+// MoveEntryRequest picks the day an entry is moved or copied to, it may belong to another trip the user can edit
+type MoveEntryRequest struct {
+	TargetItineraryID string `json:"targetItineraryId" binding:"required"`
+	Order             *int   `json:"order,omitempty" binding:"omitempty,min=0"` // Position in the target day, appended when omitted
+}
+
+// OptimizeRouteRequest picks the entries that must stay first and last, both optional
+// Bound from the query string for previews and from the body when applying
+type OptimizeRouteRequest struct {
+	StartEntryID string `json:"startEntryId" form:"startEntryId"`
+	EndEntryID   string `json:"endEntryId" form:"endEntryId"`
+}
+
+// RouteSuggestionResponse is a suggested entry order for a day with the travel distance before and after
+type RouteSuggestionResponse struct {
+	ItineraryID         string                   `json:"itineraryId"`
+	EntryIDs            []string                 `json:"entryIds"`
+	Entries             []*models.ItineraryEntry `json:"entries"`
+	CurrentDistanceKm   float64                  `json:"currentDistanceKm"`
+	SuggestedDistanceKm float64                  `json:"suggestedDistanceKm"`
+	SavedKm             float64                  `json:"savedKm"`
+	Changed             bool                     `json:"changed"`
+	Applied             bool                     `json:"applied"`
+}
+
+// FillTimesRequest configures filling a day's missing times, bound from the query string for previews and from the body when applying
+type FillTimesRequest struct {
+	DayStart        string `json:"dayStart" form:"dayStart" binding:"required"`                               // HH:MM the first entry starts at
+	BufferMinutes   int    `json:"bufferMinutes" form:"bufferMinutes" binding:"min=0,max=240"`                // Slack after each entry
+	DefaultDuration int    `json:"defaultDuration" form:"defaultDuration" binding:"omitempty,min=1,max=1440"` // For places without a duration, 60 when omitted
+	TravelMode      string `json:"travelMode" form:"travelMode" binding:"omitempty,oneof=walk transit drive"` // Used for travel between places, transit when omitted
+}
+
+// FilledEntryTime is the start and end assigned to one entry
+type FilledEntryTime struct {
+	EntryID       string `json:"entryId"`
+	Title         string `json:"title"`
+	StartTime     string `json:"startTime"`
+	EndTime       string `json:"endTime"`
+	TravelMinutes int    `json:"travelMinutes"` // Travel from the previous place, included before the start
+	Fixed         bool   `json:"fixed"`         // Already had a start time and was kept
+	Overlaps      bool   `json:"overlaps"`      // Runs into the next fixed entry
+}
+
+// FillTimesResponse is the filled schedule of a day
+type FillTimesResponse struct {
+	ItineraryID string            `json:"itineraryId"`
+	Entries     []FilledEntryTime `json:"entries"`
+	Applied     bool              `json:"applied"`
+}
+
+// Batch operation names, each mirrors the single endpoint of the same change
+const (
+	BatchOpItineraryCreate = "itinerary.create"
+	BatchOpItineraryUpdate = "itinerary.update"
+	BatchOpItineraryDelete = "itinerary.delete"
+	BatchOpEntryCreate     = "entry.create"
+	BatchOpEntryUpdate     = "entry.update"
+	BatchOpEntryDelete     = "entry.delete"
+	BatchOpEntryMove       = "entry.move"
+	BatchOpEntryCopy       = "entry.copy"
+	BatchOpEntriesReorder  = "entries.reorder"
+	BatchOpTodoCreate      = "todo.create"
+	BatchOpTodoUpdate      = "todo.update"
+	BatchOpTodoToggle      = "todo.toggle"
+	BatchOpTodoDelete      = "todo.delete"
+)
+
+// BatchOperation is one change of a batch, Data holds the body the single endpoint takes
+// ID fields may be "$<ref>" to name what an earlier operation with that ref created.
+type BatchOperation struct {
+	Op          string          `json:"op" binding:"required,oneof=itinerary.create itinerary.update itinerary.delete entry.create entry.update entry.delete entry.move entry.copy entries.reorder todo.create todo.update todo.toggle todo.delete"`
+	Ref         string          `json:"ref,omitempty" binding:"omitempty,max=64"`
+	ItineraryID string          `json:"itineraryId,omitempty"`
+	EntryID     string          `json:"entryId,omitempty"`
+	TodoID      string          `json:"todoId,omitempty"`
+	Version     *int64          `json:"version,omitempty"` // Checked like If-Match, required where the single endpoint requires it unless the target is a ref
+	Data        json.RawMessage `json:"data,omitempty"`
+
+	// Payload is Data bound to the request type of Op by the handler
+	Payload interface{} `json:"-"`
+}
+
+type BatchRequest struct {
+	Operations []BatchOperation `json:"operations" binding:"required,min=1,max=100,dive"`
+}
+
+// Statuses of a BatchOperationResult
+const (
+	BatchStatusOK         = "ok"
+	BatchStatusFailed     = "failed"
+	BatchStatusRolledBack = "rolled_back" // Succeeded, then undone because a later operation failed
+	BatchStatusSkipped    = "skipped"     // Not run because an earlier operation failed
+)
+
+type BatchOperationResult struct {
+	Index  int         `json:"index"`
+	Op     string      `json:"op"`
+	Ref    string      `json:"ref,omitempty"`
+	Status string      `json:"status"`
+	Result interface{} `json:"result,omitempty"`
+	Error  string      `json:"error,omitempty"`
+}
+
+// BatchResponse reports every operation, nothing is saved unless Committed
+type BatchResponse struct {
+	Committed bool                   `json:"committed"`
+	Error     string                 `json:"error,omitempty"` // The failed operation's error
+	Results   []BatchOperationResult `json:"results"`
+}
+
+// ScheduleValidationResponse lists the schedule problems of a day or a whole trip
+type ScheduleValidationResponse struct {
+	Valid    bool                     `json:"valid"`
+	Warnings []models.ScheduleWarning `json:"warnings"`
+}
+
 // Helper function to convert model to response
 func ToItineraryEntryResponse(entry *models.ItineraryEntry) map[string]interface{} {
 	todos := make([]map[string]interface{}, 0, len(entry.Todos))
@@ -95,6 +216,7 @@ func ToItineraryEntryResponse(entry *models.ItineraryEntry) map[string]interface
 		"budget":      entry.Budget,
 		"order":       entry.Order,
 		"todos":       todos,
+		"version":     entry.Version,
 		"createdAt":   entry.CreatedAt,
 		"updatedAt":   entry.UpdatedAt,
 	}
@@ -0,0 +1,19 @@
+package schemas
+
+type AttachTripDocumentRequest struct {
+	FileID   string  `json:"fileId" binding:"required"`
+	EntryID  *string `json:"entryId,omitempty"`
+	Category string  `json:"category" binding:"required,oneof=ticket booking insurance id other"`
+	Title    string  `json:"title" binding:"max=200"`
+}
+
+type UpdateTripDocumentRequest struct {
+	EntryID  *string `json:"entryId,omitempty"` // Empty string moves the document from its entry to the trip
+	Category *string `json:"category,omitempty" binding:"omitempty,oneof=ticket booking insurance id other"`
+	Title    *string `json:"title,omitempty" binding:"omitempty,max=200"`
+}
+
+type ListTripDocumentsQuery struct {
+	EntryID  string `form:"entryId"`
+	Category string `form:"category" binding:"omitempty,oneof=ticket booking insurance id other"`
+}
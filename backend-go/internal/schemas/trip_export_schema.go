@@ -0,0 +1,7 @@
+package schemas
+
+type CalendarFeedResponse struct {
+	Token     string `json:"token"`
+	WebcalURL string `json:"webcalUrl"` // Subscribe link for calendar apps
+	URL       string `json:"url"`       // Same feed over http(s)
+}
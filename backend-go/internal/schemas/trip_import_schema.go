@@ -0,0 +1,28 @@
+package schemas
+
+// Import row statuses
+const (
+	ImportStatusImported = "imported"
+	ImportStatusSkipped  = "skipped"
+	ImportStatusFailed   = "failed"
+)
+
+type ImportRowResult struct {
+	Row       int     `json:"row"` // 1-based row (CSV line, ICS event or JSON entry)
+	Status    string  `json:"status"`
+	Title     string  `json:"title,omitempty"`
+	DayNumber int     `json:"dayNumber,omitempty"`
+	EntryID   *string `json:"entryId,omitempty"`
+	Message   string  `json:"message,omitempty"`
+}
+
+type ImportReport struct {
+	Format      string            `json:"format"`
+	Imported    int               `json:"imported"`
+	Skipped     int               `json:"skipped"`
+	Failed      int               `json:"failed"`
+	CreatedDays int               `json:"createdDays"`
+	StartDate   string            `json:"startDate"` // Trip dates after import, extended when needed
+	EndDate     string            `json:"endDate"`
+	Rows        []ImportRowResult `json:"rows"`
+}
@@ -1,10 +1,16 @@
 package schemas
 
-type AddTripMemberRequest struct {
+type UpdateTripMemberRequest struct {
+	Role string `json:"role" binding:"required,oneof=owner editor viewer"`
+}
+
+type CreateTripInvitationRequest struct {
 	UserID string `json:"userId" binding:"required"`
-	Role   string `json:"role" binding:"required,oneof=owner editor viewer"`
+	Role   string `json:"role" binding:"required,oneof=editor viewer"`
 }
 
-type UpdateTripMemberRequest struct {
-	Role string `json:"role" binding:"required,oneof=owner editor viewer"`
+type CreateInviteLinkRequest struct {
+	Role           string `json:"role" binding:"required,oneof=editor viewer"`
+	ExpiresInHours int    `json:"expiresInHours,omitempty" binding:"omitempty,min=1,max=720"` // Default 72 hours
+	MaxUses        *int   `json:"maxUses,omitempty" binding:"omitempty,min=1"`                // nil = unlimited
 }
@@ -17,6 +17,7 @@ type CreateTripRequest struct {
 	CoverPhoto     *string             `json:"coverPhoto,omitempty" binding:"omitempty,url"`
 	Tags           []string            `json:"tags,omitempty" binding:"omitempty,dive,min=2,max=30"`
 	Type           *string             `json:"type,omitempty" binding:"omitempty,oneof=trip guide"`
+	Visibility     *string             `json:"visibility,omitempty" binding:"omitempty,oneof=private unlisted public"`
 	Level          *string             `json:"level,omitempty" binding:"omitempty,oneof=Easy Moderate Hard Expert"`
 }
 
@@ -31,7 +32,14 @@ type UpdateTripRequest struct {
 	CoverPhoto     *string             `json:"coverPhoto,omitempty" binding:"omitempty,url"`
 	Tags           []string            `json:"tags,omitempty" binding:"omitempty,dive,min=2,max=30"`
 	Status         *string             `json:"status,omitempty" binding:"omitempty,oneof=draft published"`
+	Visibility     *string             `json:"visibility,omitempty" binding:"omitempty,oneof=private unlisted public"`
 	Level          *string             `json:"level,omitempty" binding:"omitempty,oneof=Easy Moderate Hard Expert"`
+	ShrinkMode     *string             `json:"shrinkMode,omitempty" binding:"omitempty,oneof=refuse unscheduled"` // What to do with entries on days cut off by new dates, defaults to refuse
+}
+
+type ForkTripRequest struct {
+	StartDate string  `json:"startDate" binding:"required"` // Date-only format "YYYY-MM-DD"
+	Title     *string `json:"title,omitempty" binding:"omitempty,min=3,max=200"`
 }
 
 type DestinationRequest struct {
@@ -49,7 +57,7 @@ type Coordinates struct {
 
 type ListTripsQuery struct {
 	Type     string `form:"type" binding:"omitempty,oneof=trip guide"`
-	Status   string `form:"status" binding:"omitempty,oneof=draft published"`
+	Status   string `form:"status" binding:"omitempty,oneof=draft published archived"`
 	OwnerID  string `form:"ownerId" binding:"omitempty"`
 	MemberID string `form:"memberId" binding:"omitempty"`
 	Tags     string `form:"tags" binding:"omitempty"`
@@ -132,6 +140,7 @@ type ItineraryEntryResponse struct {
 	UnsplashPhotos []UnsplashPhotoResponse  `json:"unsplashPhotos,omitempty" bson:"unsplash_photos,omitempty"`
 	Order          int                      `json:"order" bson:"order"`
 	Todos          []TodoResponse           `json:"todos,omitempty" bson:"todos,omitempty"`
+	Version        int64                    `json:"version" bson:"version"`
 	CreatedAt      time.Time                `json:"createdAt" bson:"created_at"`
 	UpdatedAt      time.Time                `json:"updatedAt" bson:"updated_at"`
 }
@@ -143,7 +152,9 @@ type ItineraryResponse struct {
 	Date      string                    `json:"date" bson:"date"`
 	Title     string                    `json:"title" bson:"title"`
 	Order     int                       `json:"order" bson:"order"`
+	Unscheduled bool                    `json:"unscheduled,omitempty" bson:"unscheduled,omitempty"`
 	Entries   []ItineraryEntryResponse  `json:"entries,omitempty" bson:"entries,omitempty"`
+	Version   int64                     `json:"version" bson:"version"`
 	CreatedAt time.Time                 `json:"createdAt" bson:"created_at"`
 	UpdatedAt time.Time                 `json:"updatedAt" bson:"updated_at"`
 }
@@ -187,11 +198,14 @@ type TripDetailResponse struct {
 	Tags             []string              `json:"tags,omitempty" bson:"tags,omitempty"`
 	Status           string                `json:"status" bson:"status"`
 	Type             string                `json:"type" bson:"type"`
+	Visibility       string                `json:"visibility" bson:"visibility"`
 	Level            *string               `json:"level,omitempty" bson:"level,omitempty"`
 	ViewCount        int                   `json:"viewCount" bson:"view_count"`
 	ReactionsCount   int                   `json:"reactionsCount" bson:"reactions_count"`
 	BookmarkCount    int                   `json:"bookmarkCount" bson:"bookmark_count"`
 	ShareCount       int                   `json:"shareCount" bson:"share_count"`
+	ForkedFromID     *string               `json:"forkedFromId,omitempty" bson:"forked_from_id,omitempty"`
+	Version          int64                 `json:"version" bson:"version"`
 	CreatedAt        time.Time             `json:"createdAt" bson:"created_at"`
 	UpdatedAt        time.Time             `json:"updatedAt" bson:"updated_at"`
 
@@ -200,4 +214,78 @@ type TripDetailResponse struct {
 	TripMembers  []TripMemberResponse   `json:"tripMembers,omitempty" bson:"trip_members,omitempty"`
 	Itineraries  []ItineraryResponse    `json:"itineraries,omitempty" bson:"itineraries,omitempty"`
 	Expenses     []ExpenseResponse      `json:"expenses,omitempty" bson:"expenses,omitempty"`
+
+	// Caller's own reaction and bookmark, omitted for anonymous viewers
+	ViewerState *TripViewerState `json:"viewerState,omitempty" bson:"-"`
+}
+
+// TripViewerState is the caller's reaction and bookmark on a trip
+type TripViewerState struct {
+	Reaction   *string `json:"reaction"` // like, love, angry or null
+	Bookmarked bool    `json:"bookmarked"`
+}
+
+type ReactToTripRequest struct {
+	Type string `json:"type" binding:"required,oneof=like love angry"`
+}
+
+// TripInteractionResponse returns the updated counters with the caller's state
+type TripInteractionResponse struct {
+	ViewCount      int              `json:"viewCount"`
+	ReactionsCount int              `json:"reactionsCount"`
+	BookmarkCount  int              `json:"bookmarkCount"`
+	ShareCount     int              `json:"shareCount"`
+	ViewerState    *TripViewerState `json:"viewerState,omitempty"`
+}
+
+type ListBookmarkedTripsQuery struct {
+	Limit  int `form:"limit" binding:"omitempty,min=1,max=100"`
+	Offset int `form:"offset" binding:"omitempty,min=0"`
+}
+
+type ListTrashQuery struct {
+	Limit  int `form:"limit" binding:"omitempty,min=1,max=100"`
+	Offset int `form:"offset" binding:"omitempty,min=0"`
+}
+
+type SearchTripsQuery struct {
+	Q       string `form:"q" binding:"required,min=2,max=100"`
+	Type    string `form:"type" binding:"omitempty,oneof=trip guide"`
+	Level   string `form:"level" binding:"omitempty,oneof=Easy Moderate Hard Expert"`
+	Country string `form:"country" binding:"omitempty,max=100"`
+	Tags    string `form:"tags" binding:"omitempty"`
+	Limit   int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	Offset  int    `form:"offset" binding:"omitempty,min=0"`
+}
+
+type FacetCount struct {
+	Value string `json:"value" bson:"_id"`
+	Count int    `json:"count" bson:"count"`
+}
+
+type TripSearchFacets struct {
+	Type    []FacetCount `json:"type" bson:"type"`
+	Level   []FacetCount `json:"level" bson:"level"`
+	Country []FacetCount `json:"country" bson:"country"`
+	Tags    []FacetCount `json:"tags" bson:"tags"`
+}
+
+type NearbyTripsQuery struct {
+	ListTripsQuery
+	Lat      *float64 `form:"lat" binding:"required,min=-90,max=90"`
+	Lng      *float64 `form:"lng" binding:"required,min=-180,max=180"`
+	RadiusKm float64  `form:"radiusKm" binding:"omitempty,gt=0,max=1000"` // Default 50km
+}
+
+type WithinTripsQuery struct {
+	ListTripsQuery
+	SWLat *float64 `form:"swLat" binding:"required,min=-90,max=90"`
+	SWLng *float64 `form:"swLng" binding:"required,min=-180,max=180"`
+	NELat *float64 `form:"neLat" binding:"required,min=-90,max=90"`
+	NELng *float64 `form:"neLng" binding:"required,min=-180,max=180"`
+}
+
+// PublishTripRequest publishes a draft now, or at PublishAt when it's in the future
+type PublishTripRequest struct {
+	PublishAt *time.Time `json:"publishAt,omitempty"` // RFC 3339
 }
@@ -0,0 +1,35 @@
+package schemas
+
+// TripStatsResponse summarizes a trip for the planning dashboard
+type TripStatsResponse struct {
+	TripID         string          `json:"tripId"`
+	DayCount       int             `json:"dayCount"`
+	EntryCount     int             `json:"entryCount"` // Includes entries in the unscheduled bucket
+	PlaceCount     int             `json:"placeCount"` // Distinct places across all entries
+	DistanceKm     float64         `json:"distanceKm"` // Straight-line distance between consecutive places, summed over days
+	ScheduledHours float64         `json:"scheduledHours"`
+	FreeHours      float64         `json:"freeHours"`
+	Days           []TripDayStats  `json:"days"`
+	Budget         TripBudgetStats `json:"budget"`
+}
+
+// TripDayStats holds the per-day figures, in itinerary order
+type TripDayStats struct {
+	ItineraryID    string  `json:"itineraryId"`
+	DayNumber      int     `json:"dayNumber"`
+	Date           string  `json:"date,omitempty"`
+	EntryCount     int     `json:"entryCount"`
+	PlaceCount     int     `json:"placeCount"`
+	DistanceKm     float64 `json:"distanceKm"`
+	ScheduledHours float64 `json:"scheduledHours"` // Time covered by timed entries, overlaps counted once
+	FreeHours      float64 `json:"freeHours"`      // Rest of the planning window not covered by entries
+}
+
+// TripBudgetStats compares the planned budget with actual spend
+type TripBudgetStats struct {
+	Currency   *string            `json:"currency,omitempty"`
+	Planned    *float64           `json:"planned,omitempty"`
+	Spent      float64            `json:"spent"`
+	Remaining  *float64           `json:"remaining,omitempty"` // Negative when over budget, omitted without a planned budget
+	ByCategory map[string]float64 `json:"byCategory"`
+}
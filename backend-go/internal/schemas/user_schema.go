@@ -12,4 +12,5 @@ type UpdateUserRequest struct {
 type UserSettings struct {
 	Language      *string `json:"language,omitempty" binding:"omitempty,oneof=en th"`
 	Notifications *bool   `json:"notifications,omitempty"`
+	AutoArchiveAfterDays *int `json:"autoArchiveAfterDays,omitempty" binding:"omitempty,min=0,max=3650"`
 }
@@ -48,6 +48,7 @@ type OverviewStats struct {
 	TotalTrips             int64 `json:"totalTrips"`
 	PublicTrips            int64 `json:"publicTrips"`
 	PrivateTrips           int64 `json:"privateTrips"`
+	UnlistedTrips          int64 `json:"unlistedTrips"`
 	TripsCreatedThisMonth  int64 `json:"tripsCreatedThisMonth"`
 
 	// Content Stats
@@ -114,25 +115,36 @@ func (s *AdminService) GetOverviewStats(ctx context.Context) (*OverviewStats, er
 	}
 	stats.TotalTrips = totalTrips
 
-	// Public Trips
-	publicTrips, err := s.db.Collection("trips").CountDocuments(ctx, bson.M{
-		"visibility": "public",
+	// Trips by visibility, trips created before visibility existed are counted by their status
+	cursor, err := s.db.Collection("trips").Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$group", Value: bson.M{
+			"_id":   repository.EffectiveVisibilityExpr(),
+			"count": bson.M{"$sum": 1},
+		}}},
 	})
 	if err != nil {
 		logger.Error(err)
 		return nil, err
 	}
-	stats.PublicTrips = publicTrips
-
-	// Private Trips
-	privateTrips, err := s.db.Collection("trips").CountDocuments(ctx, bson.M{
-		"visibility": bson.M{"$in": []string{"private", "members_only"}},
-	})
-	if err != nil {
+	var visibilityCounts []struct {
+		Visibility string `bson:"_id"`
+		Count      int64  `bson:"count"`
+	}
+	if err := cursor.All(ctx, &visibilityCounts); err != nil {
 		logger.Error(err)
 		return nil, err
 	}
-	stats.PrivateTrips = privateTrips
+	for _, bucket := range visibilityCounts {
+		switch bucket.Visibility {
+		case models.TripVisibilityPublic:
+			stats.PublicTrips += bucket.Count
+		case models.TripVisibilityUnlisted:
+			stats.UnlistedTrips += bucket.Count
+		default:
+			// Includes the legacy members_only value
+			stats.PrivateTrips += bucket.Count
+		}
+	}
 
 	// Trips Created This Month
 	tripsThisMonth, err := s.db.Collection("trips").CountDocuments(ctx, bson.M{
@@ -438,7 +450,7 @@ func (s *AdminService) UnbanUser(ctx context.Context, userID, adminID string) er
 
 type TripListFilter struct {
 	Search     string // Search by title
-	Visibility string // public, private, members_only
+	Visibility string // public, unlisted, private
 	CreatorID  string // Filter by creator
 	Type       string // trip, guide
 	Status     string // draft, published, archived
@@ -471,7 +483,7 @@ func (s *AdminService) GetTripList(ctx context.Context, filter TripListFilter) (
 
 	// Visibility filter
 	if filter.Visibility != "" {
-		query["visibility"] = filter.Visibility
+		query["$expr"] = bson.M{"$eq": bson.A{repository.EffectiveVisibilityExpr(), filter.Visibility}}
 	}
 
 	// Creator filter
@@ -15,6 +15,7 @@ import (
 type CommentService struct {
 	commentRepo     *repository.CommentRepository
 	interactionRepo *repository.InteractionRepository
+	tripRepo        *repository.TripRepository
 	tracer          trace.Tracer
 }
 
@@ -22,6 +23,7 @@ func NewCommentService() *CommentService {
 	return &CommentService{
 		commentRepo:     repository.NewCommentRepository(),
 		interactionRepo: repository.NewInteractionRepository(),
+		tripRepo:        repository.NewTripRepository(),
 		tracer:          otel.Tracer("comment-service"),
 	}
 }
@@ -338,7 +340,7 @@ func (s *CommentService) UnlikeComment(ctx context.Context, commentID, userID st
 	}
 
 	// Delete interaction record
-	if err := s.interactionRepo.DeleteByUserAndTarget(ctx, userID, commentID, models.InteractionTargetComment, models.ActionLike); err != nil {
+	if _, err := s.interactionRepo.DeleteByUserAndTarget(ctx, userID, commentID, models.InteractionTargetComment, models.ActionLike); err != nil {
 		logger.Error(err)
 		return err
 	}
@@ -409,6 +411,42 @@ func (s *CommentService) GetCommentByID(ctx context.Context, commentID string) (
 
 // GetTripByID retrieves a trip by ID (for notification purposes)
 func (s *CommentService) GetTripByID(ctx context.Context, tripID string) (*models.Trip, error) {
-	tripRepo := repository.NewTripRepository()
-	return tripRepo.FindByID(ctx, tripID)
+	return s.tripRepo.FindByID(ctx, tripID)
+}
+
+// CanViewComment checks if userID (empty for anonymous) can see a comment
+// Comments on trips follow the trip visibility, other targets are public
+func (s *CommentService) CanViewComment(ctx context.Context, commentID, userID string) (bool, error) {
+	ctx, span := s.tracer.Start(ctx, "CommentService.CanViewComment")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	logger.Input(map[string]interface{}{
+		"commentID": commentID,
+		"userID":    userID,
+	})
+
+	comment, err := s.commentRepo.FindByID(ctx, commentID)
+	if err != nil {
+		err := errors.New("comment not found")
+		logger.Error(err)
+		return false, err
+	}
+
+	if comment.TargetType != models.CommentTargetTrip {
+		return true, nil
+	}
+
+	trip, err := s.tripRepo.FindByID(ctx, comment.TargetID.Hex())
+	if err != nil {
+		err := errors.New("trip not found")
+		logger.Error(err)
+		return false, err
+	}
+
+	canView := s.tripRepo.CanView(trip, userID)
+	logger.Output(map[string]interface{}{
+		"canView": canView,
+	})
+	return canView, nil
 }
@@ -0,0 +1,249 @@
+package services
+
+import (
+	"context"
+
+	"backend-go/internal/models"
+	"backend-go/internal/repository"
+	"backend-go/pkg/utils"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	defaultReconcileBatchSize = 500
+	maxReconcileBatchSize     = 5000
+	maxReconcileDifferences   = 1000 // Differences listed in the report, the totals always cover everything
+)
+
+// CounterReconcileService recomputes denormalized engagement counters from the interactions and comments collections
+type CounterReconcileService struct {
+	tripRepo        *repository.TripRepository
+	commentRepo     *repository.CommentRepository
+	interactionRepo *repository.InteractionRepository
+	tracer          trace.Tracer
+}
+
+func NewCounterReconcileService() *CounterReconcileService {
+	return &CounterReconcileService{
+		tripRepo:        repository.NewTripRepository(),
+		commentRepo:     repository.NewCommentRepository(),
+		interactionRepo: repository.NewInteractionRepository(),
+		tracer:          otel.Tracer("counter-reconcile-service"),
+	}
+}
+
+type ReconcileOptions struct {
+	DryRun    bool // Report differences without writing
+	BatchSize int  // Documents read per batch, defaults to 500
+}
+
+// CounterDifference is one cached counter that doesn't match its source
+type CounterDifference struct {
+	Collection string `json:"collection"` // trips, comments
+	ID         string `json:"id"`
+	Field      string `json:"field"`
+	Cached     int    `json:"cached"`
+	Actual     int    `json:"actual"`
+}
+
+type ReconcileReport struct {
+	DryRun               bool                `json:"dryRun"`
+	TripsScanned         int                 `json:"tripsScanned"`
+	TripsMismatched      int                 `json:"tripsMismatched"`
+	CommentsScanned      int                 `json:"commentsScanned"`
+	CommentsMismatched   int                 `json:"commentsMismatched"`
+	Updated              int64               `json:"updated"`
+	Differences          []CounterDifference `json:"differences"`
+	DifferencesTruncated bool                `json:"differencesTruncated"`
+}
+
+// Reconcile recomputes trip and comment counters batch by batch
+// Each batch is read, compared and (unless dry-run) written before the next one, so memory stays bounded
+func (s *CounterReconcileService) Reconcile(ctx context.Context, opts ReconcileOptions) (*ReconcileReport, error) {
+	ctx, span := s.tracer.Start(ctx, "CounterReconcileService.Reconcile")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	logger.Input(opts)
+
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = defaultReconcileBatchSize
+	}
+	if opts.BatchSize > maxReconcileBatchSize {
+		opts.BatchSize = maxReconcileBatchSize
+	}
+
+	report := &ReconcileReport{
+		DryRun:      opts.DryRun,
+		Differences: []CounterDifference{},
+	}
+
+	if err := s.reconcileTrips(ctx, opts, report); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	if err := s.reconcileComments(ctx, opts, report); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	logger.Output(map[string]interface{}{
+		"tripsScanned":       report.TripsScanned,
+		"tripsMismatched":    report.TripsMismatched,
+		"commentsScanned":    report.CommentsScanned,
+		"commentsMismatched": report.CommentsMismatched,
+		"updated":            report.Updated,
+	})
+	return report, nil
+}
+
+func (s *CounterReconcileService) reconcileTrips(ctx context.Context, opts ReconcileOptions, report *ReconcileReport) error {
+	var afterID primitive.ObjectID
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		trips, err := s.tripRepo.FindCountersBatch(ctx, afterID, int64(opts.BatchSize))
+		if err != nil {
+			return err
+		}
+		if len(trips) == 0 {
+			return nil
+		}
+		afterID = trips[len(trips)-1].ID
+
+		ids := make([]primitive.ObjectID, len(trips))
+		for i, trip := range trips {
+			ids[i] = trip.ID
+		}
+
+		counts, err := s.interactionRepo.CountByTargets(ctx, models.InteractionTargetTrip, ids)
+		if err != nil {
+			return err
+		}
+
+		fixes := map[primitive.ObjectID]map[string]int{}
+		for _, trip := range trips {
+			actions := counts[trip.ID]
+			actual := map[string]int{
+				repository.TripCounterViews:     actions[models.ActionView],
+				repository.TripCounterReactions: actions[models.ActionLike] + actions[models.ActionLove] + actions[models.ActionAngry],
+				repository.TripCounterBookmarks: actions[models.ActionBookmark],
+				repository.TripCounterShares:    actions[models.ActionShare],
+			}
+			cached := map[string]int{
+				repository.TripCounterViews:     trip.ViewCount,
+				repository.TripCounterReactions: trip.ReactionsCount,
+				repository.TripCounterBookmarks: trip.BookmarkCount,
+				repository.TripCounterShares:    trip.ShareCount,
+			}
+
+			if diff := report.compare("trips", trip.ID, cached, actual); diff != nil {
+				fixes[trip.ID] = diff
+				report.TripsMismatched++
+			}
+		}
+		report.TripsScanned += len(trips)
+
+		if !opts.DryRun {
+			updated, err := s.tripRepo.SetCounters(ctx, fixes)
+			if err != nil {
+				return err
+			}
+			report.Updated += updated
+		}
+	}
+}
+
+func (s *CounterReconcileService) reconcileComments(ctx context.Context, opts ReconcileOptions, report *ReconcileReport) error {
+	var afterID primitive.ObjectID
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		comments, err := s.commentRepo.FindCountersBatch(ctx, afterID, int64(opts.BatchSize))
+		if err != nil {
+			return err
+		}
+		if len(comments) == 0 {
+			return nil
+		}
+		afterID = comments[len(comments)-1].ID
+
+		ids := make([]primitive.ObjectID, len(comments))
+		for i, comment := range comments {
+			ids[i] = comment.ID
+		}
+
+		counts, err := s.interactionRepo.CountByTargets(ctx, models.InteractionTargetComment, ids)
+		if err != nil {
+			return err
+		}
+
+		replies, err := s.commentRepo.CountRepliesByParents(ctx, ids)
+		if err != nil {
+			return err
+		}
+
+		fixes := map[primitive.ObjectID]map[string]int{}
+		for _, comment := range comments {
+			actions := counts[comment.ID]
+			actual := map[string]int{
+				repository.CommentCounterReactions: actions[models.ActionLike] + actions[models.ActionLove] + actions[models.ActionAngry],
+				repository.CommentCounterReplies:   replies[comment.ID],
+			}
+			cached := map[string]int{
+				repository.CommentCounterReactions: comment.ReactionsCount,
+				repository.CommentCounterReplies:   comment.RepliesCount,
+			}
+
+			if diff := report.compare("comments", comment.ID, cached, actual); diff != nil {
+				fixes[comment.ID] = diff
+				report.CommentsMismatched++
+			}
+		}
+		report.CommentsScanned += len(comments)
+
+		if !opts.DryRun {
+			updated, err := s.commentRepo.SetCounters(ctx, fixes)
+			if err != nil {
+				return err
+			}
+			report.Updated += updated
+		}
+	}
+}
+
+// compare records differing counters in the report and returns the corrected values, nil when all match
+func (r *ReconcileReport) compare(collection string, id primitive.ObjectID, cached, actual map[string]int) map[string]int {
+	var fixes map[string]int
+	for field, value := range actual {
+		if cached[field] == value {
+			continue
+		}
+
+		if fixes == nil {
+			fixes = map[string]int{}
+		}
+		fixes[field] = value
+
+		if len(r.Differences) < maxReconcileDifferences {
+			r.Differences = append(r.Differences, CounterDifference{
+				Collection: collection,
+				ID:         id.Hex(),
+				Field:      field,
+				Cached:     cached[field],
+				Actual:     value,
+			})
+		} else {
+			r.DifferencesTruncated = true
+		}
+	}
+	return fixes
+}
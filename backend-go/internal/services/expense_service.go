@@ -7,6 +7,7 @@ import (
 
 	"backend-go/internal/models"
 	"backend-go/internal/repository"
+	"backend-go/pkg/sse"
 	"backend-go/pkg/utils"
 
 	"go.opentelemetry.io/otel"
@@ -15,12 +16,16 @@ import (
 
 type ExpenseService struct {
 	expenseRepo *repository.ExpenseRepository
+	tripRepo    *repository.TripRepository
+	tripHub     *sse.TripHub
 	tracer      trace.Tracer
 }
 
-func NewExpenseService() *ExpenseService {
+func NewExpenseService(tripHub *sse.TripHub) *ExpenseService {
 	return &ExpenseService{
 		expenseRepo: repository.NewExpenseRepository(),
+		tripRepo:    repository.NewTripRepository(),
+		tripHub:     tripHub,
 		tracer:      otel.Tracer("expense-service"),
 	}
 }
@@ -103,6 +108,11 @@ func (s *ExpenseService) CreateExpense(
 		return nil, err
 	}
 
+	if err := s.ensureTripWritable(ctx, tripID); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
 	// Set other fields
 	expense.Amount = amount
 	expense.Currency = currency
@@ -127,6 +137,8 @@ func (s *ExpenseService) CreateExpense(
 		return nil, err
 	}
 
+	publishTripEvent(s.tripHub, expense.TripID, sse.TripEventExpenseCreated, expense)
+
 	logger.Output(map[string]interface{}{
 		"expenseID": expense.ID.Hex(),
 	})
@@ -159,6 +171,11 @@ func (s *ExpenseService) UpdateExpense(
 		return nil, err
 	}
 
+	if err := s.ensureTripWritable(ctx, expense.TripID.Hex()); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
 	if amount != nil {
 		expense.Amount = *amount
 	}
@@ -183,6 +200,8 @@ func (s *ExpenseService) UpdateExpense(
 		return nil, err
 	}
 
+	publishTripEvent(s.tripHub, expense.TripID, sse.TripEventExpenseUpdated, expense)
+
 	logger.Output(expense)
 	return expense, nil
 }
@@ -197,12 +216,26 @@ func (s *ExpenseService) DeleteExpense(ctx context.Context, expenseID string) er
 		"expenseID": expenseID,
 	})
 
-	err := s.expenseRepo.Delete(ctx, expenseID)
+	expense, err := s.expenseRepo.FindByID(ctx, expenseID)
+	if err != nil {
+		err := errors.New("expense not found")
+		logger.Error(err)
+		return err
+	}
+
+	if err := s.ensureTripWritable(ctx, expense.TripID.Hex()); err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	err = s.expenseRepo.Delete(ctx, expenseID)
 	if err != nil {
 		logger.Error(err)
 		return err
 	}
 
+	publishTripEvent(s.tripHub, expense.TripID, sse.TripEventExpenseDeleted, deletedEvent{ID: expenseID})
+
 	logger.Info("Expense deleted successfully")
 	return nil
 }
@@ -214,9 +247,30 @@ func (s *ExpenseService) MarkExpenseAsSettled(ctx context.Context, expenseID str
 		return errors.New("expense not found")
 	}
 
+	if err := s.ensureTripWritable(ctx, expense.TripID.Hex()); err != nil {
+		return err
+	}
+
 	expense.Status = models.ExpenseStatusSettled
 
-	return s.expenseRepo.Update(ctx, expense)
+	if err := s.expenseRepo.Update(ctx, expense); err != nil {
+		return err
+	}
+
+	publishTripEvent(s.tripHub, expense.TripID, sse.TripEventExpenseUpdated, expense)
+	return nil
+}
+
+// ensureTripWritable rejects changes to expenses of archived trips
+func (s *ExpenseService) ensureTripWritable(ctx context.Context, tripID string) error {
+	trip, err := s.tripRepo.FindByID(ctx, tripID)
+	if err != nil {
+		return errors.New("trip not found")
+	}
+	if trip.IsArchived() {
+		return errors.New("trip is archived")
+	}
+	return nil
 }
 
 // GetTotalExpensesByTrip calculates total expenses for a trip
@@ -18,6 +18,7 @@ import (
 
 type FileService struct {
 	fileRepo       *repository.FileRepository
+	documentRepo   *repository.TripDocumentRepository
 	storageService *StorageService
 	tracer         trace.Tracer
 }
@@ -30,6 +31,7 @@ func NewFileService(cfg *config.R2Config) (*FileService, error) {
 
 	return &FileService{
 		fileRepo:       repository.NewFileRepository(),
+		documentRepo:   repository.NewTripDocumentRepository(),
 		storageService: storage,
 		tracer:         otel.Tracer("file-service"),
 	}, nil
@@ -213,6 +215,12 @@ func (s *FileService) DeleteFile(ctx context.Context, fileID, userID string) err
 		return err
 	}
 
+	// Remove it from the trip document vaults it was attached to
+	if err := s.documentRepo.DeleteByFileID(ctx, file.ID); err != nil {
+		logger.Error(err)
+		return err
+	}
+
 	logger.Info("File deleted successfully")
 	return nil
 }
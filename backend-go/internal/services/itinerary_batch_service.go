@@ -0,0 +1,380 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"backend-go/internal/config"
+	"backend-go/internal/models"
+	"backend-go/internal/repository"
+	"backend-go/internal/schemas"
+	"backend-go/pkg/sse"
+	"backend-go/pkg/utils"
+
+	"github.com/kamva/mgm/v3"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ItineraryBatchService applies itinerary, entry and todo changes all or nothing
+type ItineraryBatchService struct {
+	// itineraryService runs each operation with events held back until the batch commits
+	itineraryService *ItineraryService
+	itineraryRepo    *repository.ItineraryRepository
+	entryRepo        *repository.ItineraryEntryRepository
+	tripRepo         *repository.TripRepository
+	tripHub          *sse.TripHub
+	tracer           trace.Tracer
+}
+
+func NewItineraryBatchService(tripHub *sse.TripHub, travel *config.TravelConfig, schedule *config.ScheduleConfig) *ItineraryBatchService {
+	itineraryService := NewItineraryService(tripHub, travel, schedule)
+	itineraryService.deferEvents = true
+
+	return &ItineraryBatchService{
+		itineraryService: itineraryService,
+		itineraryRepo:    repository.NewItineraryRepository(),
+		entryRepo:        repository.NewItineraryEntryRepository(),
+		tripRepo:         repository.NewTripRepository(),
+		tripHub:          tripHub,
+		tracer:           otel.Tracer("itinerary-batch-service"),
+	}
+}
+
+// batchState is what operations of one batch share
+type batchState struct {
+	tripID primitive.ObjectID
+	userID string
+	// refs maps an operation's ref to the ID of what it created
+	refs map[string]string
+	// touchedTrips are the trips to announce after commit, move and copy may reach other trips
+	touchedTrips map[primitive.ObjectID]bool
+	removedIDs   []string
+}
+
+// ApplyBatch runs the operations in order inside one MongoDB transaction
+// The first failing operation aborts the transaction, its error is returned along with the
+// per-operation results, so nothing is saved unless the response is committed.
+func (s *ItineraryBatchService) ApplyBatch(ctx context.Context, tripID, userID string, operations []schemas.BatchOperation) (*schemas.BatchResponse, error) {
+	ctx, span := s.tracer.Start(ctx, "ItineraryBatchService.ApplyBatch")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	logger.Input(map[string]interface{}{
+		"tripID":     tripID,
+		"userID":     userID,
+		"operations": len(operations),
+	})
+
+	response := &schemas.BatchResponse{
+		Results: make([]schemas.BatchOperationResult, len(operations)),
+	}
+	for i, operation := range operations {
+		response.Results[i] = schemas.BatchOperationResult{
+			Index:  i,
+			Op:     operation.Op,
+			Ref:    operation.Ref,
+			Status: schemas.BatchStatusSkipped,
+		}
+	}
+
+	trip, err := s.tripRepo.FindByID(ctx, tripID)
+	if err != nil {
+		err := errors.New("trip not found")
+		logger.Error(err)
+		return response, err
+	}
+
+	state := &batchState{
+		tripID:       trip.ID,
+		userID:       userID,
+		refs:         map[string]string{},
+		touchedTrips: map[primitive.ObjectID]bool{trip.ID: true},
+	}
+
+	err = mgm.TransactionWithCtx(ctx, func(session mongo.Session, sc mongo.SessionContext) error {
+		for i, operation := range operations {
+			result, createdID, err := s.applyOperation(sc, state, operation)
+			if err != nil {
+				response.Results[i].Status = schemas.BatchStatusFailed
+				response.Results[i].Error = err.Error()
+				_ = session.AbortTransaction(sc)
+				return err
+			}
+
+			response.Results[i].Status = schemas.BatchStatusOK
+			response.Results[i].Result = result
+			if operation.Ref != "" {
+				state.refs[operation.Ref] = createdID
+			}
+		}
+		return session.CommitTransaction(sc)
+	})
+	if err != nil {
+		// Everything that ran before the failure was undone with the transaction
+		for i := range response.Results {
+			if response.Results[i].Status == schemas.BatchStatusOK {
+				response.Results[i].Status = schemas.BatchStatusRolledBack
+				response.Results[i].Result = nil
+			}
+		}
+		logger.Error(err)
+		return response, err
+	}
+	response.Committed = true
+
+	// One full refresh per trip instead of the events of every operation
+	for touchedTripID := range state.touchedTrips {
+		if touchedTripID == trip.ID {
+			publishDaysChanged(ctx, s.tripHub, s.itineraryRepo, trip, state.removedIDs...)
+			continue
+		}
+		if touched, err := s.tripRepo.FindByID(ctx, touchedTripID.Hex()); err == nil {
+			publishDaysChanged(ctx, s.tripHub, s.itineraryRepo, touched)
+		}
+	}
+
+	logger.Output(map[string]interface{}{
+		"operations": len(operations),
+		"trips":      len(state.touchedTrips),
+	})
+	return response, nil
+}
+
+// applyOperation runs one operation and returns its result and the ID a ref names
+func (s *ItineraryBatchService) applyOperation(ctx context.Context, state *batchState, operation schemas.BatchOperation) (interface{}, string, error) {
+	switch operation.Op {
+	case schemas.BatchOpItineraryCreate:
+		req := operation.Payload.(*schemas.CreateItineraryRequest)
+		itinerary, err := s.itineraryService.CreateItinerary(ctx, state.tripID.Hex(), req.DayNumber, req.Date, req.Title, req.Order)
+		if err != nil {
+			return nil, "", err
+		}
+		return itinerary, itinerary.ID.Hex(), nil
+
+	case schemas.BatchOpItineraryUpdate:
+		itineraryID, err := s.resolveItinerary(ctx, state, operation.ItineraryID)
+		if err != nil {
+			return nil, "", err
+		}
+		req := operation.Payload.(*schemas.UpdateItineraryRequest)
+		itinerary, err := s.itineraryService.UpdateItinerary(ctx, itineraryID, req.Title, req.Date, req.Order, operation.Version)
+		if err != nil {
+			return nil, "", err
+		}
+		return itinerary, itineraryID, nil
+
+	case schemas.BatchOpItineraryDelete:
+		itineraryID, err := s.resolveItinerary(ctx, state, operation.ItineraryID)
+		if err != nil {
+			return nil, "", err
+		}
+		if err := s.itineraryService.DeleteItinerary(ctx, itineraryID, operation.Version); err != nil {
+			return nil, "", err
+		}
+		state.removedIDs = append(state.removedIDs, itineraryID)
+		return deletedEvent{ID: itineraryID}, itineraryID, nil
+
+	case schemas.BatchOpEntryCreate:
+		itineraryID, err := s.resolveItinerary(ctx, state, operation.ItineraryID)
+		if err != nil {
+			return nil, "", err
+		}
+		req := operation.Payload.(*schemas.CreateEntryRequest)
+		description := ""
+		if req.Description != nil {
+			description = *req.Description
+		}
+		entry, err := s.itineraryService.CreateEntry(
+			ctx,
+			itineraryID,
+			models.EntryType(req.Type),
+			req.Title,
+			description,
+			req.PlaceID,
+			req.Place,
+			req.StartTime,
+			req.EndTime,
+			req.Order,
+			req.Todos,
+		)
+		if err != nil {
+			return nil, "", err
+		}
+		return entry, entry.ID.Hex(), nil
+
+	case schemas.BatchOpEntryUpdate:
+		entryID, err := s.resolveEntry(ctx, state, operation.EntryID)
+		if err != nil {
+			return nil, "", err
+		}
+		req := operation.Payload.(*schemas.UpdateEntryRequest)
+		entry, err := s.itineraryService.UpdateEntry(
+			ctx,
+			entryID,
+			req.Title,
+			req.Description,
+			req.StartTime,
+			req.EndTime,
+			req.Order,
+			req.Todos,
+			operation.Version,
+		)
+		if err != nil {
+			return nil, "", err
+		}
+		return entry, entryID, nil
+
+	case schemas.BatchOpEntryDelete:
+		entryID, err := s.resolveEntry(ctx, state, operation.EntryID)
+		if err != nil {
+			return nil, "", err
+		}
+		if err := s.itineraryService.DeleteEntry(ctx, entryID, operation.Version); err != nil {
+			return nil, "", err
+		}
+		return deletedEvent{ID: entryID}, entryID, nil
+
+	case schemas.BatchOpEntryMove, schemas.BatchOpEntryCopy:
+		entryID, err := s.resolveEntry(ctx, state, operation.EntryID)
+		if err != nil {
+			return nil, "", err
+		}
+		req := operation.Payload.(*schemas.MoveEntryRequest)
+		// The target may be in another trip, the service checks the user can edit it
+		targetID, err := resolveRef(state, req.TargetItineraryID)
+		if err != nil {
+			return nil, "", err
+		}
+
+		var entry *models.ItineraryEntry
+		if operation.Op == schemas.BatchOpEntryMove {
+			entry, err = s.itineraryService.MoveEntry(ctx, entryID, state.userID, targetID, req.Order, operation.Version)
+		} else {
+			entry, err = s.itineraryService.CopyEntry(ctx, entryID, state.userID, targetID, req.Order)
+		}
+		if err != nil {
+			return nil, "", err
+		}
+
+		if target, err := s.itineraryRepo.FindByID(ctx, targetID); err == nil {
+			state.touchedTrips[target.TripID] = true
+		}
+		return entry, entry.ID.Hex(), nil
+
+	case schemas.BatchOpEntriesReorder:
+		itineraryID, err := s.resolveItinerary(ctx, state, operation.ItineraryID)
+		if err != nil {
+			return nil, "", err
+		}
+		req := operation.Payload.(*schemas.ReorderEntriesRequest)
+		entryIDs := make([]string, 0, len(req.EntryIDs))
+		for _, id := range req.EntryIDs {
+			entryID, err := resolveRef(state, id)
+			if err != nil {
+				return nil, "", err
+			}
+			entryIDs = append(entryIDs, entryID)
+		}
+		itinerary, err := s.itineraryService.ReorderEntries(ctx, itineraryID, entryIDs, operation.Version)
+		if err != nil {
+			return nil, "", err
+		}
+		return itinerary, itineraryID, nil
+
+	case schemas.BatchOpTodoCreate:
+		entryID, err := s.resolveEntry(ctx, state, operation.EntryID)
+		if err != nil {
+			return nil, "", err
+		}
+		req := operation.Payload.(*schemas.CreateTodoRequest)
+		todo, err := s.itineraryService.CreateTodo(ctx, entryID, req.Title, *req.Order)
+		if err != nil {
+			return nil, "", err
+		}
+		return todo, todo.ID, nil
+
+	case schemas.BatchOpTodoUpdate, schemas.BatchOpTodoToggle, schemas.BatchOpTodoDelete:
+		entryID, err := s.resolveEntry(ctx, state, operation.EntryID)
+		if err != nil {
+			return nil, "", err
+		}
+		todoID, err := resolveRef(state, operation.TodoID)
+		if err != nil {
+			return nil, "", err
+		}
+		if todoID == "" {
+			return nil, "", errors.New("todoId is required")
+		}
+
+		var entry *models.ItineraryEntry
+		switch operation.Op {
+		case schemas.BatchOpTodoUpdate:
+			req := operation.Payload.(*schemas.UpdateTodoRequest)
+			entry, err = s.itineraryService.UpdateTodo(ctx, entryID, todoID, *req.Title, operation.Version)
+		case schemas.BatchOpTodoToggle:
+			entry, err = s.itineraryService.ToggleTodo(ctx, entryID, todoID)
+		default:
+			entry, err = s.itineraryService.DeleteTodo(ctx, entryID, todoID, operation.Version)
+		}
+		if err != nil {
+			return nil, "", err
+		}
+		return entry, todoID, nil
+	}
+
+	return nil, "", errors.New("unknown operation " + operation.Op)
+}
+
+// resolveItinerary resolves a ref and rejects days of other trips
+func (s *ItineraryBatchService) resolveItinerary(ctx context.Context, state *batchState, id string) (string, error) {
+	itineraryID, err := resolveRef(state, id)
+	if err != nil {
+		return "", err
+	}
+	if itineraryID == "" {
+		return "", errors.New("itineraryId is required")
+	}
+
+	itinerary, err := s.itineraryRepo.FindByID(ctx, itineraryID)
+	if err != nil || itinerary.TripID != state.tripID {
+		return "", errors.New("itinerary not found")
+	}
+	return itineraryID, nil
+}
+
+// resolveEntry resolves a ref and rejects entries of other trips
+func (s *ItineraryBatchService) resolveEntry(ctx context.Context, state *batchState, id string) (string, error) {
+	entryID, err := resolveRef(state, id)
+	if err != nil {
+		return "", err
+	}
+	if entryID == "" {
+		return "", errors.New("entryId is required")
+	}
+
+	entry, err := s.entryRepo.FindByID(ctx, entryID)
+	if err != nil {
+		return "", errors.New("entry not found")
+	}
+	itinerary, err := s.itineraryRepo.FindByID(ctx, entry.ItineraryID.Hex())
+	if err != nil || itinerary.TripID != state.tripID {
+		return "", errors.New("entry not found")
+	}
+	return entryID, nil
+}
+
+// resolveRef turns "$<ref>" into the ID an earlier operation created, other IDs are kept as they are
+func resolveRef(state *batchState, id string) (string, error) {
+	if !strings.HasPrefix(id, "$") {
+		return id, nil
+	}
+	resolved, ok := state.refs[strings.TrimPrefix(id, "$")]
+	if !ok {
+		return "", errors.New("unknown ref " + id)
+	}
+	return resolved, nil
+}
@@ -7,9 +7,12 @@ import (
 	"strconv"
 	"time"
 
+	"backend-go/internal/config"
 	"backend-go/internal/models"
 	"backend-go/internal/repository"
 	"backend-go/internal/schemas"
+	"backend-go/pkg/geo"
+	"backend-go/pkg/sse"
 	"backend-go/pkg/utils"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -22,15 +25,28 @@ type ItineraryService struct {
 	entryRepo     *repository.ItineraryEntryRepository
 	placeRepo     *repository.PlaceRepository
 	tripRepo      *repository.TripRepository
+	documentRepo  *repository.TripDocumentRepository
+	expenseRepo   *repository.ExpenseRepository
+	tripHub       *sse.TripHub
+	travel        *config.TravelConfig
+	schedule      *config.ScheduleConfig
 	tracer        trace.Tracer
+
+	// deferEvents stops publishing per change, a batch announces its changes once committed
+	deferEvents bool
 }
 
-func NewItineraryService() *ItineraryService {
+func NewItineraryService(tripHub *sse.TripHub, travel *config.TravelConfig, schedule *config.ScheduleConfig) *ItineraryService {
 	return &ItineraryService{
 		itineraryRepo: repository.NewItineraryRepository(),
 		entryRepo:     repository.NewItineraryEntryRepository(),
 		placeRepo:     repository.NewPlaceRepository(),
 		tripRepo:      repository.NewTripRepository(),
+		documentRepo:  repository.NewTripDocumentRepository(),
+		expenseRepo:   repository.NewExpenseRepository(),
+		tripHub:       tripHub,
+		travel:        travel,
+		schedule:      schedule,
 		tracer:        otel.Tracer("itinerary-service"),
 	}
 }
@@ -50,6 +66,8 @@ func (s *ItineraryService) GetItinerariesByTripID(ctx context.Context, tripID st
 		return nil, err
 	}
 
+	attachTravelLegs(itineraries, s.travel)
+
 	logger.Output(map[string]interface{}{"count": len(itineraries)})
 	return itineraries, nil
 }
@@ -93,6 +111,11 @@ func (s *ItineraryService) CreateItinerary(ctx context.Context, tripID string, d
 		return nil, err
 	}
 
+	if err := s.ensureTripWritable(ctx, tripID); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
 	// Set other fields
 	itinerary.DayNumber = dayNumber
 	itinerary.Date = date
@@ -104,12 +127,14 @@ func (s *ItineraryService) CreateItinerary(ctx context.Context, tripID string, d
 		return nil, err
 	}
 
+	s.publishTripEvent(itinerary.TripID, sse.TripEventDayCreated, itinerary)
+
 	logger.Output(map[string]interface{}{"itineraryID": itinerary.ID.Hex()})
 	return itinerary, nil
 }
 
-// UpdateItinerary updates an itinerary (day)
-func (s *ItineraryService) UpdateItinerary(ctx context.Context, itineraryID string, title *string, date *string, order *int) (*models.Itinerary, error) {
+// UpdateItinerary updates an itinerary (day), expectedVersion is the client's If-Match version
+func (s *ItineraryService) UpdateItinerary(ctx context.Context, itineraryID string, title *string, date *string, order *int, expectedVersion *int64) (*models.Itinerary, error) {
 	ctx, span := s.tracer.Start(ctx, "ItineraryService.UpdateItinerary")
 	defer span.End()
 	logger := utils.NewTraceLogger(ctx, span)
@@ -123,6 +148,16 @@ func (s *ItineraryService) UpdateItinerary(ctx context.Context, itineraryID stri
 		return nil, err
 	}
 
+	if err := s.ensureTripWritable(ctx, itinerary.TripID.Hex()); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	if err := checkVersion(itinerary.Version, expectedVersion); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
 	if title != nil {
 		itinerary.Title = *title
 	}
@@ -138,13 +173,15 @@ func (s *ItineraryService) UpdateItinerary(ctx context.Context, itineraryID stri
 		return nil, err
 	}
 
+	s.publishTripEvent(itinerary.TripID, sse.TripEventDayUpdated, itinerary)
+
 	logger.Output(itinerary)
 	return itinerary, nil
 }
 
 // DeleteItinerary deletes an itinerary (day) and all its entries
 // It also shifts remaining itineraries and updates trip dates accordingly
-func (s *ItineraryService) DeleteItinerary(ctx context.Context, itineraryID string) error {
+func (s *ItineraryService) DeleteItinerary(ctx context.Context, itineraryID string, expectedVersion *int64) error {
 	ctx, span := s.tracer.Start(ctx, "ItineraryService.DeleteItinerary")
 	defer span.End()
 	logger := utils.NewTraceLogger(ctx, span)
@@ -159,14 +196,24 @@ func (s *ItineraryService) DeleteItinerary(ctx context.Context, itineraryID stri
 		return err
 	}
 
-	// 2. Validation: Prevent deleting last remaining day
-	totalItineraries, err := s.itineraryRepo.CountByTripID(ctx, itinerary.TripID.Hex())
+	if err := s.ensureTripWritable(ctx, itinerary.TripID.Hex()); err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	if err := checkVersion(itinerary.Version, expectedVersion); err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	// 2. Validation: Prevent deleting last remaining day, the unscheduled bucket doesn't count as one
+	totalDays, err := s.itineraryRepo.CountDaysByTripID(ctx, itinerary.TripID.Hex())
 	if err != nil {
 		logger.Error(err)
 		return err
 	}
 
-	if totalItineraries <= 1 {
+	if totalDays <= 1 && !itinerary.Unscheduled {
 		err := errors.New("cannot delete the last day of a trip")
 		logger.Error(err)
 		return err
@@ -180,7 +227,21 @@ func (s *ItineraryService) DeleteItinerary(ctx context.Context, itineraryID stri
 		return err
 	}
 
-	// 4. Delete all entries first
+	// 4. Delete all entries first, their documents stay in the trip's vault
+	entries, err := s.entryRepo.FindByItineraryID(ctx, itineraryID)
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+	entryIDs := make([]primitive.ObjectID, 0, len(entries))
+	for _, entry := range entries {
+		entryIDs = append(entryIDs, entry.ID)
+	}
+	if err := s.documentRepo.DetachFromEntries(ctx, entryIDs); err != nil {
+		logger.Error(err)
+		return err
+	}
+
 	if err := s.entryRepo.DeleteByItineraryID(ctx, itineraryID); err != nil {
 		logger.Error(err)
 		return err
@@ -192,6 +253,16 @@ func (s *ItineraryService) DeleteItinerary(ctx context.Context, itineraryID stri
 		return err
 	}
 
+	// The unscheduled bucket isn't a day, nothing to shift
+	if itinerary.Unscheduled {
+		if err := s.tripRepo.RefreshEntryTitles(ctx, itinerary.TripID); err != nil {
+			logger.Error(err)
+		}
+		s.publishTripEvent(itinerary.TripID, sse.TripEventDayDeleted, deletedEvent{ID: itineraryID})
+		logger.Info("Unscheduled bucket deleted")
+		return nil
+	}
+
 	// 6. Get all remaining itineraries and sort by order
 	allItineraries, err := s.itineraryRepo.FindByTripID(ctx, itinerary.TripID.Hex())
 	if err != nil {
@@ -248,6 +319,12 @@ func (s *ItineraryService) DeleteItinerary(ctx context.Context, itineraryID stri
 		return err
 	}
 
+	if err := s.tripRepo.RefreshEntryTitles(ctx, itinerary.TripID); err != nil {
+		logger.Error(err)
+	}
+
+	s.publishDaysChanged(ctx, trip, itineraryID)
+
 	logger.Output(map[string]interface{}{
 		"deletedDayNumber": itinerary.DayNumber,
 		"shiftedDays":      len(allItineraries),
@@ -322,6 +399,12 @@ func (s *ItineraryService) CreateEntry(
 		return nil, err
 	}
 
+	tripID, err := s.ensureItineraryWritable(ctx, itineraryID)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
 	// Set other fields
 	entry.Type = entryType
 	entry.Title = title
@@ -382,6 +465,12 @@ func (s *ItineraryService) CreateEntry(
 		return nil, err
 	}
 
+	if err := s.refreshEntryTitles(ctx, entry.ItineraryID); err != nil {
+		logger.Error(err)
+	}
+
+	s.publishTripEvent(tripID, sse.TripEventEntryCreated, entry)
+
 	logger.Output(map[string]interface{}{"entryID": entry.ID.Hex()})
 	return entry, nil
 }
@@ -416,7 +505,7 @@ func (s *ItineraryService) convertPlaceDataToModel(data *schemas.PlaceData) *mod
 	return place
 }
 
-// UpdateEntry updates an entry
+// UpdateEntry updates an entry, expectedVersion is the client's If-Match version
 func (s *ItineraryService) UpdateEntry(
 	ctx context.Context,
 	entryID string,
@@ -424,6 +513,7 @@ func (s *ItineraryService) UpdateEntry(
 	startTime, endTime *string,
 	order *int,
 	todos *[]models.Todo,
+	expectedVersion *int64,
 ) (*models.ItineraryEntry, error) {
 	ctx, span := s.tracer.Start(ctx, "ItineraryService.UpdateEntry")
 	defer span.End()
@@ -438,6 +528,17 @@ func (s *ItineraryService) UpdateEntry(
 		return nil, err
 	}
 
+	tripID, err := s.ensureItineraryWritable(ctx, entry.ItineraryID.Hex())
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	if err := checkVersion(entry.Version, expectedVersion); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
 	if title != nil {
 		entry.Title = *title
 	}
@@ -462,245 +563,663 @@ func (s *ItineraryService) UpdateEntry(
 		return nil, err
 	}
 
+	if title != nil {
+		if err := s.refreshEntryTitles(ctx, entry.ItineraryID); err != nil {
+			logger.Error(err)
+		}
+	}
+
+	s.publishTripEvent(tripID, sse.TripEventEntryUpdated, entry)
+
 	logger.Output(entry)
 	return entry, nil
 }
 
 // DeleteEntry deletes an entry
-func (s *ItineraryService) DeleteEntry(ctx context.Context, entryID string) error {
+func (s *ItineraryService) DeleteEntry(ctx context.Context, entryID string, expectedVersion *int64) error {
 	ctx, span := s.tracer.Start(ctx, "ItineraryService.DeleteEntry")
 	defer span.End()
 	logger := utils.NewTraceLogger(ctx, span)
 
 	logger.Input(map[string]interface{}{"entryID": entryID})
 
-	err := s.entryRepo.Delete(ctx, entryID)
+	entry, err := s.entryRepo.FindByID(ctx, entryID)
 	if err != nil {
+		err := errors.New("entry not found")
 		logger.Error(err)
 		return err
 	}
 
-	logger.Info("Entry deleted successfully")
-	return nil
-}
-
-// UpdateTodos updates todos for an entry
-func (s *ItineraryService) UpdateTodos(ctx context.Context, entryID string, todos []models.Todo) error {
-	ctx, span := s.tracer.Start(ctx, "ItineraryService.UpdateTodos")
-	defer span.End()
-	logger := utils.NewTraceLogger(ctx, span)
-
-	logger.Input(map[string]interface{}{
-		"entryID":    entryID,
-		"todosCount": len(todos),
-	})
-
-	entry, err := s.entryRepo.FindByID(ctx, entryID)
+	tripID, err := s.ensureItineraryWritable(ctx, entry.ItineraryID.Hex())
 	if err != nil {
-		err := errors.New("entry not found")
 		logger.Error(err)
 		return err
 	}
 
-	// Generate IDs for new todos (ones without ID)
-	for i := range todos {
-		if todos[i].ID == "" {
-			todos[i].ID = primitive.NewObjectID().Hex()
-		}
+	if err := checkVersion(entry.Version, expectedVersion); err != nil {
+		logger.Error(err)
+		return err
 	}
 
-	entry.Todos = todos
-
-	err = s.entryRepo.Update(ctx, entry)
+	err = s.entryRepo.Delete(ctx, entryID)
 	if err != nil {
 		logger.Error(err)
 		return err
 	}
 
-	logger.Info("Todos updated successfully")
+	// Documents of the entry stay in the trip's vault
+	if err := s.documentRepo.DetachFromEntries(ctx, []primitive.ObjectID{entry.ID}); err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	if err := s.refreshEntryTitles(ctx, entry.ItineraryID); err != nil {
+		logger.Error(err)
+	}
+
+	s.publishTripEvent(tripID, sse.TripEventEntryDeleted, entryDeletedEvent{
+		ID:          entry.ID.Hex(),
+		ItineraryID: entry.ItineraryID.Hex(),
+	})
+
+	logger.Info("Entry deleted successfully")
 	return nil
 }
 
-// ToggleTodo toggles a todo's completed status by ID
-func (s *ItineraryService) ToggleTodo(ctx context.Context, entryID string, todoID string) error {
-	ctx, span := s.tracer.Start(ctx, "ItineraryService.ToggleTodo")
+// MoveEntry moves an entry to a position in another day of this trip or of another trip the user can edit
+// The entry keeps its ID, todos, photos and place. Moving to another trip unlinks its expenses and
+// documents, they belong to the source trip. A nil position appends to the target day.
+func (s *ItineraryService) MoveEntry(ctx context.Context, entryID, userID, targetItineraryID string, position *int, expectedVersion *int64) (*models.ItineraryEntry, error) {
+	ctx, span := s.tracer.Start(ctx, "ItineraryService.MoveEntry")
 	defer span.End()
 	logger := utils.NewTraceLogger(ctx, span)
 
 	logger.Input(map[string]interface{}{
-		"entryID": entryID,
-		"todoID":  todoID,
+		"entryID":           entryID,
+		"targetItineraryID": targetItineraryID,
+		"position":          position,
 	})
 
 	entry, err := s.entryRepo.FindByID(ctx, entryID)
 	if err != nil {
 		err := errors.New("entry not found")
 		logger.Error(err)
-		return err
+		return nil, err
 	}
 
-	// Find todo by ID
-	todoFound := false
-	for i := range entry.Todos {
-		if entry.Todos[i].ID == todoID {
-			entry.Todos[i].Completed = !entry.Todos[i].Completed
-			todoFound = true
-			break
-		}
+	sourceTripID, err := s.ensureItineraryWritable(ctx, entry.ItineraryID.Hex())
+	if err != nil {
+		logger.Error(err)
+		return nil, err
 	}
 
-	if !todoFound {
-		err := errors.New("todo not found")
+	if err := checkVersion(entry.Version, expectedVersion); err != nil {
 		logger.Error(err)
-		return err
+		return nil, err
 	}
 
-	err = s.entryRepo.Update(ctx, entry)
+	target, err := s.targetItinerary(ctx, targetItineraryID, sourceTripID, userID)
 	if err != nil {
 		logger.Error(err)
-		return err
+		return nil, err
 	}
 
-	logger.Info("Todo toggled successfully")
-	return nil
-}
-
-// CreateTodo creates a new todo in an entry
-func (s *ItineraryService) CreateTodo(ctx context.Context, entryID string, title string, order int) (*models.Todo, error) {
-	ctx, span := s.tracer.Start(ctx, "ItineraryService.CreateTodo")
-	defer span.End()
-	logger := utils.NewTraceLogger(ctx, span)
-
-	logger.Input(map[string]interface{}{
-		"entryID": entryID,
-		"title":   title,
-		"order":   order,
-	})
-
-	entry, err := s.entryRepo.FindByID(ctx, entryID)
+	sourceEntries, err := s.entryRepo.FindByItineraryID(ctx, entry.ItineraryID.Hex())
 	if err != nil {
-		err := errors.New("entry not found")
 		logger.Error(err)
 		return nil, err
 	}
+	sourceEntries = withoutEntry(sourceEntries, entry.ID)
 
-	// Generate ID for new todo
-	newTodo := models.Todo{
-		ID:        primitive.NewObjectID().Hex(),
-		Title:     title,
-		Completed: false,
-		Order:     order,
+	targetEntries := sourceEntries
+	if target.ID != entry.ItineraryID {
+		if targetEntries, err = s.entryRepo.FindByItineraryID(ctx, targetItineraryID); err != nil {
+			logger.Error(err)
+			return nil, err
+		}
 	}
+	targetEntries = insertEntry(targetEntries, entry, position)
 
-	// Append to existing todos
-	entry.Todos = append(entry.Todos, newTodo)
+	// The move, both renumberings and the unlinking commit together, a concurrent edit of any
+	// of the entries rolls everything back
+	sourceItineraryID := entry.ItineraryID
+	err = inTransaction(ctx, func(ctx context.Context) error {
+		entry.ItineraryID = target.ID
+		for i, e := range targetEntries {
+			if e == entry {
+				entry.Order = i
+			}
+		}
+		if err := s.entryRepo.Update(ctx, entry); err != nil {
+			return err
+		}
 
-	err = s.entryRepo.Update(ctx, entry)
+		if target.ID != sourceItineraryID {
+			if err := s.renumberEntries(ctx, sourceEntries); err != nil {
+				return err
+			}
+		}
+		if err := s.renumberEntries(ctx, targetEntries); err != nil {
+			return err
+		}
+
+		if target.TripID != sourceTripID {
+			if err := s.expenseRepo.DetachFromEntries(ctx, []primitive.ObjectID{entry.ID}); err != nil {
+				return err
+			}
+			if err := s.documentRepo.DetachFromEntries(ctx, []primitive.ObjectID{entry.ID}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 	if err != nil {
 		logger.Error(err)
 		return nil, err
 	}
 
+	if target.TripID != sourceTripID {
+		if err := s.tripRepo.RefreshEntryTitles(ctx, sourceTripID); err != nil {
+			logger.Error(err)
+		}
+		if err := s.tripRepo.RefreshEntryTitles(ctx, target.TripID); err != nil {
+			logger.Error(err)
+		}
+
+		s.publishTripEvent(sourceTripID, sse.TripEventEntryDeleted, entryDeletedEvent{
+			ID:          entry.ID.Hex(),
+			ItineraryID: sourceItineraryID.Hex(),
+		})
+		s.publishTripEvent(target.TripID, sse.TripEventEntryCreated, entry)
+	} else {
+		s.publishTripEvent(target.TripID, sse.TripEventEntryUpdated, entry)
+	}
+
+	if target.ID != sourceItineraryID {
+		s.publishTripEvent(sourceTripID, sse.TripEventEntriesReordered, newEntriesReorderedEvent(sourceItineraryID, sourceEntries))
+	}
+	s.publishTripEvent(target.TripID, sse.TripEventEntriesReordered, newEntriesReorderedEvent(target.ID, targetEntries))
+
 	logger.Output(map[string]interface{}{
-		"todoID": newTodo.ID,
+		"itineraryID": entry.ItineraryID.Hex(),
+		"order":       entry.Order,
 	})
-	logger.Info("Todo created successfully")
-	return &newTodo, nil
+	return entry, nil
 }
 
-// UpdateTodo updates a todo's title
-func (s *ItineraryService) UpdateTodo(ctx context.Context, entryID string, todoID string, title string) error {
-	ctx, span := s.tracer.Start(ctx, "ItineraryService.UpdateTodo")
+// CopyEntry copies an entry with its todos, photos and place to a position in a day of this trip
+// or of another trip the user can edit. Expenses and documents stay with the original.
+func (s *ItineraryService) CopyEntry(ctx context.Context, entryID, userID, targetItineraryID string, position *int) (*models.ItineraryEntry, error) {
+	ctx, span := s.tracer.Start(ctx, "ItineraryService.CopyEntry")
 	defer span.End()
 	logger := utils.NewTraceLogger(ctx, span)
 
 	logger.Input(map[string]interface{}{
-		"entryID": entryID,
-		"todoID":  todoID,
-		"title":   title,
+		"entryID":           entryID,
+		"targetItineraryID": targetItineraryID,
+		"position":          position,
 	})
 
-	entry, err := s.entryRepo.FindByID(ctx, entryID)
+	original, err := s.entryRepo.FindByID(ctx, entryID)
 	if err != nil {
 		err := errors.New("entry not found")
 		logger.Error(err)
-		return err
+		return nil, err
 	}
 
-	// Find and update todo by ID
-	todoFound := false
-	for i := range entry.Todos {
-		if entry.Todos[i].ID == todoID {
-			entry.Todos[i].Title = title
-			todoFound = true
-			break
-		}
+	sourceItinerary, err := s.itineraryRepo.FindByID(ctx, original.ItineraryID.Hex())
+	if err != nil {
+		err := errors.New("itinerary not found")
+		logger.Error(err)
+		return nil, err
 	}
 
-	if !todoFound {
-		err := errors.New("todo not found")
+	target, err := s.targetItinerary(ctx, targetItineraryID, sourceItinerary.TripID, userID)
+	if err != nil {
 		logger.Error(err)
-		return err
+		return nil, err
 	}
 
-	err = s.entryRepo.Update(ctx, entry)
+	targetEntries, err := s.entryRepo.FindByItineraryID(ctx, targetItineraryID)
 	if err != nil {
 		logger.Error(err)
-		return err
+		return nil, err
 	}
 
-	logger.Info("Todo updated successfully")
-	return nil
-}
+	entry := &models.ItineraryEntry{
+		ItineraryID: target.ID,
+		Type:        original.Type,
+		Title:       original.Title,
+		Description: original.Description,
+		PlaceID:     original.PlaceID,
+		StartTime:   original.StartTime,
+		EndTime:     original.EndTime,
+		Duration:    original.Duration,
+		Budget:      original.Budget,
+		Photos:      append([]string(nil), original.Photos...),
+		Todos:       make([]models.Todo, 0, len(original.Todos)),
+	}
+	for _, todo := range original.Todos {
+		todo.ID = primitive.NewObjectID().Hex()
+		entry.Todos = append(entry.Todos, todo)
+	}
 
-// DeleteTodo deletes a todo by ID
-func (s *ItineraryService) DeleteTodo(ctx context.Context, entryID string, todoID string) error {
-	ctx, span := s.tracer.Start(ctx, "ItineraryService.DeleteTodo")
-	defer span.End()
-	logger := utils.NewTraceLogger(ctx, span)
+	targetEntries = insertEntry(targetEntries, entry, position)
+	for i, e := range targetEntries {
+		if e == entry {
+			entry.Order = i
+		}
+	}
 
-	logger.Input(map[string]interface{}{
-		"entryID": entryID,
-		"todoID":  todoID,
+	// The copy only exists once the day is renumbered around it
+	err = inTransaction(ctx, func(ctx context.Context) error {
+		if err := s.entryRepo.Create(ctx, entry); err != nil {
+			return err
+		}
+		return s.renumberEntries(ctx, targetEntries)
 	})
-
-	entry, err := s.entryRepo.FindByID(ctx, entryID)
 	if err != nil {
-		err := errors.New("entry not found")
 		logger.Error(err)
-		return err
-	}
-
-	// Find and remove todo by ID
-	todoIndex := -1
-	for i := range entry.Todos {
-		if entry.Todos[i].ID == todoID {
-			todoIndex = i
-			break
-		}
+		return nil, err
 	}
 
-	if todoIndex == -1 {
-		err := errors.New("todo not found")
+	if err := s.tripRepo.RefreshEntryTitles(ctx, target.TripID); err != nil {
 		logger.Error(err)
-		return err
 	}
 
-	// Remove todo from slice
-	entry.Todos = append(entry.Todos[:todoIndex], entry.Todos[todoIndex+1:]...)
+	s.publishTripEvent(target.TripID, sse.TripEventEntryCreated, entry)
+	s.publishTripEvent(target.TripID, sse.TripEventEntriesReordered, newEntriesReorderedEvent(target.ID, targetEntries))
 
-	err = s.entryRepo.Update(ctx, entry)
+	logger.Output(map[string]interface{}{
+		"entryID": entry.ID.Hex(),
+		"order":   entry.Order,
+	})
+	return entry, nil
+}
+
+// targetItinerary loads the day an entry is moved or copied to, another trip must be editable by the user
+func (s *ItineraryService) targetItinerary(ctx context.Context, itineraryID string, sourceTripID primitive.ObjectID, userID string) (*models.Itinerary, error) {
+	target, err := s.itineraryRepo.FindByID(ctx, itineraryID)
 	if err != nil {
-		logger.Error(err)
-		return err
+		return nil, errors.New("target itinerary not found")
 	}
 
-	logger.Info("Todo deleted successfully")
+	// Access to the source trip was checked by the route middleware
+	if target.TripID == sourceTripID {
+		return target, s.ensureTripWritable(ctx, target.TripID.Hex())
+	}
+
+	trip, err := s.tripRepo.FindByID(ctx, target.TripID.Hex())
+	if err != nil {
+		return nil, errors.New("target itinerary not found")
+	}
+	role := s.tripRepo.GetMemberRole(trip, userID)
+	if role != models.MemberRoleOwner && role != models.MemberRoleEditor {
+		return nil, errors.New("forbidden: you can't edit the target trip")
+	}
+	if trip.IsArchived() {
+		return nil, errors.New("trip is archived")
+	}
+	return target, nil
+}
+
+// renumberEntries saves the orders of entries whose position in the list changed
+func (s *ItineraryService) renumberEntries(ctx context.Context, entries []*models.ItineraryEntry) error {
+	for i, entry := range entries {
+		if entry.Order == i {
+			continue
+		}
+		entry.Order = i
+		if err := s.entryRepo.Update(ctx, entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// withoutEntry returns the entries except the one with the ID
+func withoutEntry(entries []*models.ItineraryEntry, id primitive.ObjectID) []*models.ItineraryEntry {
+	kept := make([]*models.ItineraryEntry, 0, len(entries))
+	for _, entry := range entries {
+		if entry.ID != id {
+			kept = append(kept, entry)
+		}
+	}
+	return kept
+}
+
+// insertEntry inserts an entry at position, a nil or out of range position appends
+func insertEntry(entries []*models.ItineraryEntry, entry *models.ItineraryEntry, position *int) []*models.ItineraryEntry {
+	index := len(entries)
+	if position != nil && *position >= 0 && *position < len(entries) {
+		index = *position
+	}
+
+	result := make([]*models.ItineraryEntry, 0, len(entries)+1)
+	result = append(result, entries[:index]...)
+	result = append(result, entry)
+	return append(result, entries[index:]...)
+}
+
+// publishTripEvent publishes a change unless events are deferred to the end of a batch
+func (s *ItineraryService) publishTripEvent(tripID primitive.ObjectID, eventType string, data interface{}) {
+	if s.deferEvents {
+		return
+	}
+	publishTripEvent(s.tripHub, tripID, eventType, data)
+}
+
+// publishDaysChanged publishes the whole itinerary unless events are deferred to the end of a batch
+func (s *ItineraryService) publishDaysChanged(ctx context.Context, trip *models.Trip, removedIDs ...string) {
+	if s.deferEvents {
+		return
+	}
+	publishDaysChanged(ctx, s.tripHub, s.itineraryRepo, trip, removedIDs...)
+}
+
+// refreshEntryTitles keeps the trip full-text search fields in sync after entry changes
+// Callers only log the error, search freshness must not fail itinerary edits
+func (s *ItineraryService) refreshEntryTitles(ctx context.Context, itineraryID primitive.ObjectID) error {
+	itinerary, err := s.itineraryRepo.FindByID(ctx, itineraryID.Hex())
+	if err != nil {
+		return err
+	}
+	return s.tripRepo.RefreshEntryTitles(ctx, itinerary.TripID)
+}
+
+// ensureTripWritable rejects changes to archived trips, their plans are read-only until unarchived
+func (s *ItineraryService) ensureTripWritable(ctx context.Context, tripID string) error {
+	trip, err := s.tripRepo.FindByID(ctx, tripID)
+	if err != nil {
+		return errors.New("trip not found")
+	}
+	if trip.IsArchived() {
+		return errors.New("trip is archived")
+	}
 	return nil
 }
 
+// ensureItineraryWritable rejects changes to days of archived trips and returns the day's trip ID
+func (s *ItineraryService) ensureItineraryWritable(ctx context.Context, itineraryID string) (primitive.ObjectID, error) {
+	itinerary, err := s.itineraryRepo.FindByID(ctx, itineraryID)
+	if err != nil {
+		return primitive.NilObjectID, errors.New("itinerary not found")
+	}
+	return itinerary.TripID, s.ensureTripWritable(ctx, itinerary.TripID.Hex())
+}
+
+// UpdateTodos replaces the todos of an entry and returns the updated entry
+func (s *ItineraryService) UpdateTodos(ctx context.Context, entryID string, todos []models.Todo, expectedVersion *int64) (*models.ItineraryEntry, error) {
+	ctx, span := s.tracer.Start(ctx, "ItineraryService.UpdateTodos")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	logger.Input(map[string]interface{}{
+		"entryID":    entryID,
+		"todosCount": len(todos),
+	})
+
+	entry, err := s.entryRepo.FindByID(ctx, entryID)
+	if err != nil {
+		err := errors.New("entry not found")
+		logger.Error(err)
+		return nil, err
+	}
+
+	tripID, err := s.ensureItineraryWritable(ctx, entry.ItineraryID.Hex())
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	if err := checkVersion(entry.Version, expectedVersion); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	// Generate IDs for new todos (ones without ID)
+	for i := range todos {
+		if todos[i].ID == "" {
+			todos[i].ID = primitive.NewObjectID().Hex()
+		}
+	}
+
+	entry.Todos = todos
+
+	err = s.entryRepo.Update(ctx, entry)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	s.publishTripEvent(tripID, sse.TripEventTodosUpdated, newTodosUpdatedEvent(entry))
+
+	logger.Info("Todos updated successfully")
+	return entry, nil
+}
+
+// ToggleTodo toggles a todo's completed status by ID and returns the updated entry
+func (s *ItineraryService) ToggleTodo(ctx context.Context, entryID string, todoID string) (*models.ItineraryEntry, error) {
+	ctx, span := s.tracer.Start(ctx, "ItineraryService.ToggleTodo")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	logger.Input(map[string]interface{}{
+		"entryID": entryID,
+		"todoID":  todoID,
+	})
+
+	entry, err := s.entryRepo.FindByID(ctx, entryID)
+	if err != nil {
+		err := errors.New("entry not found")
+		logger.Error(err)
+		return nil, err
+	}
+
+	tripID, err := s.ensureItineraryWritable(ctx, entry.ItineraryID.Hex())
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	// Find todo by ID
+	todoFound := false
+	for i := range entry.Todos {
+		if entry.Todos[i].ID == todoID {
+			entry.Todos[i].Completed = !entry.Todos[i].Completed
+			todoFound = true
+			break
+		}
+	}
+
+	if !todoFound {
+		err := errors.New("todo not found")
+		logger.Error(err)
+		return nil, err
+	}
+
+	err = s.entryRepo.Update(ctx, entry)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	s.publishTripEvent(tripID, sse.TripEventTodosUpdated, newTodosUpdatedEvent(entry))
+
+	logger.Info("Todo toggled successfully")
+	return entry, nil
+}
+
+// CreateTodo creates a new todo in an entry
+func (s *ItineraryService) CreateTodo(ctx context.Context, entryID string, title string, order int) (*models.Todo, error) {
+	ctx, span := s.tracer.Start(ctx, "ItineraryService.CreateTodo")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	logger.Input(map[string]interface{}{
+		"entryID": entryID,
+		"title":   title,
+		"order":   order,
+	})
+
+	entry, err := s.entryRepo.FindByID(ctx, entryID)
+	if err != nil {
+		err := errors.New("entry not found")
+		logger.Error(err)
+		return nil, err
+	}
+
+	tripID, err := s.ensureItineraryWritable(ctx, entry.ItineraryID.Hex())
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	// Generate ID for new todo
+	newTodo := models.Todo{
+		ID:        primitive.NewObjectID().Hex(),
+		Title:     title,
+		Completed: false,
+		Order:     order,
+	}
+
+	// Append to existing todos
+	entry.Todos = append(entry.Todos, newTodo)
+
+	err = s.entryRepo.Update(ctx, entry)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	s.publishTripEvent(tripID, sse.TripEventTodosUpdated, newTodosUpdatedEvent(entry))
+
+	logger.Output(map[string]interface{}{
+		"todoID": newTodo.ID,
+	})
+	logger.Info("Todo created successfully")
+	return &newTodo, nil
+}
+
+// UpdateTodo updates a todo's title and returns the updated entry
+func (s *ItineraryService) UpdateTodo(ctx context.Context, entryID string, todoID string, title string, expectedVersion *int64) (*models.ItineraryEntry, error) {
+	ctx, span := s.tracer.Start(ctx, "ItineraryService.UpdateTodo")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	logger.Input(map[string]interface{}{
+		"entryID": entryID,
+		"todoID":  todoID,
+		"title":   title,
+	})
+
+	entry, err := s.entryRepo.FindByID(ctx, entryID)
+	if err != nil {
+		err := errors.New("entry not found")
+		logger.Error(err)
+		return nil, err
+	}
+
+	tripID, err := s.ensureItineraryWritable(ctx, entry.ItineraryID.Hex())
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	if err := checkVersion(entry.Version, expectedVersion); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	// Find and update todo by ID
+	todoFound := false
+	for i := range entry.Todos {
+		if entry.Todos[i].ID == todoID {
+			entry.Todos[i].Title = title
+			todoFound = true
+			break
+		}
+	}
+
+	if !todoFound {
+		err := errors.New("todo not found")
+		logger.Error(err)
+		return nil, err
+	}
+
+	err = s.entryRepo.Update(ctx, entry)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	s.publishTripEvent(tripID, sse.TripEventTodosUpdated, newTodosUpdatedEvent(entry))
+
+	logger.Info("Todo updated successfully")
+	return entry, nil
+}
+
+// DeleteTodo deletes a todo by ID and returns the updated entry
+func (s *ItineraryService) DeleteTodo(ctx context.Context, entryID string, todoID string, expectedVersion *int64) (*models.ItineraryEntry, error) {
+	ctx, span := s.tracer.Start(ctx, "ItineraryService.DeleteTodo")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	logger.Input(map[string]interface{}{
+		"entryID": entryID,
+		"todoID":  todoID,
+	})
+
+	entry, err := s.entryRepo.FindByID(ctx, entryID)
+	if err != nil {
+		err := errors.New("entry not found")
+		logger.Error(err)
+		return nil, err
+	}
+
+	tripID, err := s.ensureItineraryWritable(ctx, entry.ItineraryID.Hex())
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	if err := checkVersion(entry.Version, expectedVersion); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	// Find and remove todo by ID
+	todoIndex := -1
+	for i := range entry.Todos {
+		if entry.Todos[i].ID == todoID {
+			todoIndex = i
+			break
+		}
+	}
+
+	if todoIndex == -1 {
+		err := errors.New("todo not found")
+		logger.Error(err)
+		return nil, err
+	}
+
+	// Remove todo from slice
+	entry.Todos = append(entry.Todos[:todoIndex], entry.Todos[todoIndex+1:]...)
+
+	err = s.entryRepo.Update(ctx, entry)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	s.publishTripEvent(tripID, sse.TripEventTodosUpdated, newTodosUpdatedEvent(entry))
+
+	logger.Info("Todo deleted successfully")
+	return entry, nil
+}
+
 // ReorderTodos reorders todos based on the provided list of IDs
-func (s *ItineraryService) ReorderTodos(ctx context.Context, entryID string, todoIDs []string) (*models.ItineraryEntry, error) {
+func (s *ItineraryService) ReorderTodos(ctx context.Context, entryID string, todoIDs []string, expectedVersion *int64) (*models.ItineraryEntry, error) {
 	ctx, span := s.tracer.Start(ctx, "ItineraryService.ReorderTodos")
 	defer span.End()
 	logger := utils.NewTraceLogger(ctx, span)
@@ -717,6 +1236,17 @@ func (s *ItineraryService) ReorderTodos(ctx context.Context, entryID string, tod
 		return nil, err
 	}
 
+	tripID, err := s.ensureItineraryWritable(ctx, entry.ItineraryID.Hex())
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	if err := checkVersion(entry.Version, expectedVersion); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
 	// Create a map of existing todos by ID for quick lookup
 	todoMap := make(map[string]models.Todo)
 	for _, todo := range entry.Todos {
@@ -747,12 +1277,16 @@ func (s *ItineraryService) ReorderTodos(ctx context.Context, entryID string, tod
 		return nil, err
 	}
 
+	s.publishTripEvent(tripID, sse.TripEventTodosUpdated, newTodosUpdatedEvent(entry))
+
 	logger.Info("Todos reordered successfully")
 	return entry, nil
 }
 
 // ReorderEntries reorders entries within an itinerary by updating their order field
-func (s *ItineraryService) ReorderEntries(ctx context.Context, itineraryID string, entryIDs []string) ([]*models.ItineraryEntry, error) {
+// The itinerary version guards the whole order, so a reorder based on a stale list fails instead of interleaving.
+// Returns the itinerary with its reordered entries.
+func (s *ItineraryService) ReorderEntries(ctx context.Context, itineraryID string, entryIDs []string, expectedVersion *int64) (*models.Itinerary, error) {
 	ctx, span := s.tracer.Start(ctx, "ItineraryService.ReorderEntries")
 	defer span.End()
 	logger := utils.NewTraceLogger(ctx, span)
@@ -762,6 +1296,24 @@ func (s *ItineraryService) ReorderEntries(ctx context.Context, itineraryID strin
 		"entryIDCount": len(entryIDs),
 	})
 
+	tripID, err := s.ensureItineraryWritable(ctx, itineraryID)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	itinerary, err := s.itineraryRepo.FindByID(ctx, itineraryID)
+	if err != nil {
+		err := errors.New("itinerary not found")
+		logger.Error(err)
+		return nil, err
+	}
+
+	if err := checkVersion(itinerary.Version, expectedVersion); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
 	// Get all entries for this itinerary
 	entries, err := s.entryRepo.FindByItineraryID(ctx, itineraryID)
 	if err != nil {
@@ -782,23 +1334,38 @@ func (s *ItineraryService) ReorderEntries(ctx context.Context, itineraryID strin
 		return nil, err
 	}
 
-	// Update order for each entry
-	for i, entryID := range entryIDs {
-		if entry, exists := entryMap[entryID]; exists {
+	// Claim the itinerary version first, a concurrent reorder loses here before touching any entry.
+	// The claim and the new orders commit together, a failed entry update leaves the day as it was.
+	err = inTransaction(ctx, func(ctx context.Context) error {
+		if err := s.itineraryRepo.Update(ctx, itinerary); err != nil {
+			return err
+		}
+
+		// Update order for each entry
+		for i, entryID := range entryIDs {
+			entry, exists := entryMap[entryID]
+			if !exists {
+				return errors.New("entry not found: " + entryID)
+			}
 			entry.Order = i
 			if err := s.entryRepo.Update(ctx, entry); err != nil {
-				logger.Error(err)
-				return nil, err
+				return err
 			}
-		} else {
-			err := errors.New("entry not found: " + entryID)
-			logger.Error(err)
-			return nil, err
 		}
+		return nil
+	})
+	if err != nil {
+		logger.Error(err)
+		return nil, err
 	}
 
 	logger.Info("Entries reordered successfully")
 
+	s.publishTripEvent(tripID, sse.TripEventEntriesReordered, entriesReorderedEvent{
+		ItineraryID: itineraryID,
+		EntryIDs:    entryIDs,
+	})
+
 	// Fetch entries with place data aggregated for response
 	entriesWithPlaces, err := s.entryRepo.FindByItineraryIDWithPlaces(ctx, itineraryID)
 	if err != nil {
@@ -806,7 +1373,428 @@ func (s *ItineraryService) ReorderEntries(ctx context.Context, itineraryID strin
 		return nil, err
 	}
 
-	return entriesWithPlaces, nil
+	itinerary.Entries = entriesWithPlaces
+	return itinerary, nil
+}
+
+// SuggestEntryOrder suggests an order of a day's entries that shortens travel between its places
+// The start and end entries, entries with a start time, and entries without a location keep their positions.
+func (s *ItineraryService) SuggestEntryOrder(ctx context.Context, itineraryID, startEntryID, endEntryID string) (*schemas.RouteSuggestionResponse, error) {
+	ctx, span := s.tracer.Start(ctx, "ItineraryService.SuggestEntryOrder")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	logger.Input(map[string]interface{}{
+		"itineraryID":  itineraryID,
+		"startEntryID": startEntryID,
+		"endEntryID":   endEntryID,
+	})
+
+	if startEntryID != "" && startEntryID == endEntryID {
+		err := errors.New("start and end entries must differ")
+		logger.Error(err)
+		return nil, err
+	}
+
+	entries, err := s.entryRepo.FindByItineraryIDWithPlaces(ctx, itineraryID)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	// Move the chosen start and end entries to the ends of the day
+	current := append([]*models.ItineraryEntry(nil), entries...)
+	ordered := make([]*models.ItineraryEntry, 0, len(entries))
+	var start, end *models.ItineraryEntry
+	for _, entry := range entries {
+		switch entry.ID.Hex() {
+		case startEntryID:
+			start = entry
+		case endEntryID:
+			end = entry
+		default:
+			ordered = append(ordered, entry)
+		}
+	}
+	if startEntryID != "" && start == nil {
+		err := errors.New("start entry not found in this day")
+		logger.Error(err)
+		return nil, err
+	}
+	if endEntryID != "" && end == nil {
+		err := errors.New("end entry not found in this day")
+		logger.Error(err)
+		return nil, err
+	}
+	if start != nil {
+		ordered = append([]*models.ItineraryEntry{start}, ordered...)
+	}
+	if end != nil {
+		ordered = append(ordered, end)
+	}
+
+	stops := make([]geo.Stop, len(ordered))
+	for i, entry := range ordered {
+		point := entryPoint(entry)
+		_, _, timed := entry.Schedule()
+		stops[i] = geo.Stop{
+			Point: point,
+			Fixed: entry == start || entry == end || timed || entry.Type != models.EntryTypePlace || point == nil,
+		}
+	}
+
+	suggested := make([]*models.ItineraryEntry, 0, len(ordered))
+	for _, index := range geo.OptimizeOrder(stops) {
+		suggested = append(suggested, ordered[index])
+	}
+
+	suggestion := &schemas.RouteSuggestionResponse{
+		ItineraryID:         itineraryID,
+		EntryIDs:            make([]string, 0, len(suggested)),
+		Entries:             suggested,
+		CurrentDistanceKm:   roundTo2(entriesPathKm(current)),
+		SuggestedDistanceKm: roundTo2(entriesPathKm(suggested)),
+	}
+	for i, entry := range suggested {
+		suggestion.EntryIDs = append(suggestion.EntryIDs, entry.ID.Hex())
+		if entry.ID != current[i].ID {
+			suggestion.Changed = true
+		}
+	}
+	suggestion.SavedKm = roundTo2(suggestion.CurrentDistanceKm - suggestion.SuggestedDistanceKm)
+
+	logger.Output(map[string]interface{}{
+		"changed":             suggestion.Changed,
+		"currentDistanceKm":   suggestion.CurrentDistanceKm,
+		"suggestedDistanceKm": suggestion.SuggestedDistanceKm,
+	})
+	return suggestion, nil
+}
+
+// ApplySuggestedEntryOrder reorders a day's entries to the suggested order
+func (s *ItineraryService) ApplySuggestedEntryOrder(ctx context.Context, itineraryID, startEntryID, endEntryID string, expectedVersion *int64) (*schemas.RouteSuggestionResponse, *models.Itinerary, error) {
+	ctx, span := s.tracer.Start(ctx, "ItineraryService.ApplySuggestedEntryOrder")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	logger.Input(map[string]interface{}{
+		"itineraryID":  itineraryID,
+		"startEntryID": startEntryID,
+		"endEntryID":   endEntryID,
+	})
+
+	suggestion, err := s.SuggestEntryOrder(ctx, itineraryID, startEntryID, endEntryID)
+	if err != nil {
+		logger.Error(err)
+		return nil, nil, err
+	}
+
+	// Nothing to move, only honour the precondition
+	if !suggestion.Changed {
+		if _, err := s.ensureItineraryWritable(ctx, itineraryID); err != nil {
+			logger.Error(err)
+			return nil, nil, err
+		}
+		itinerary, err := s.GetItinerary(ctx, itineraryID)
+		if err != nil {
+			logger.Error(err)
+			return nil, nil, err
+		}
+		if err := checkVersion(itinerary.Version, expectedVersion); err != nil {
+			logger.Error(err)
+			return nil, nil, err
+		}
+		return suggestion, itinerary, nil
+	}
+
+	itinerary, err := s.ReorderEntries(ctx, itineraryID, suggestion.EntryIDs, expectedVersion)
+	if err != nil {
+		logger.Error(err)
+		return nil, nil, err
+	}
+	suggestion.Entries = itinerary.Entries
+	suggestion.Applied = true
+
+	logger.Output(map[string]interface{}{
+		"savedKm": suggestion.SavedKm,
+	})
+	return suggestion, itinerary, nil
+}
+
+// entryPoint returns the location of an entry's place, nil when it has none
+func entryPoint(entry *models.ItineraryEntry) *geo.Point {
+	if entry.Place == nil {
+		return nil
+	}
+	lat, lng, ok := entry.Place.Location.LatLng()
+	if !ok {
+		return nil
+	}
+	return &geo.Point{Lat: lat, Lng: lng}
+}
+
+// entriesPathKm is the straight-line distance of visiting the entries' places in order
+func entriesPathKm(entries []*models.ItineraryEntry) float64 {
+	points := make([]geo.Point, 0, len(entries))
+	for _, entry := range entries {
+		if point := entryPoint(entry); point != nil {
+			points = append(points, *point)
+		}
+	}
+	return geo.PathKm(points)
+}
+
+// defaultFillDuration is how long a place without a duration is planned for when filling times
+const defaultFillDuration = 60
+
+// FillTimes plans start and end times for a day's entries in order from their durations
+// Entries with a start time keep it, notes and todos without a duration stay untimed,
+// travel from the previous place is added before each start.
+func (s *ItineraryService) FillTimes(ctx context.Context, itineraryID string, req schemas.FillTimesRequest) (*schemas.FillTimesResponse, error) {
+	ctx, span := s.tracer.Start(ctx, "ItineraryService.FillTimes")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	logger.Input(map[string]interface{}{
+		"itineraryID": itineraryID,
+		"dayStart":    req.DayStart,
+		"buffer":      req.BufferMinutes,
+		"travelMode":  req.TravelMode,
+	})
+
+	cursor, err := models.ParseClock(req.DayStart)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	itinerary, err := s.itineraryRepo.FindByIDWithEntries(ctx, itineraryID)
+	if err != nil {
+		err := errors.New("itinerary not found")
+		logger.Error(err)
+		return nil, err
+	}
+	if itinerary.Unscheduled {
+		err := errors.New("unscheduled entries have no day to fill")
+		logger.Error(err)
+		return nil, err
+	}
+
+	defaultDuration := req.DefaultDuration
+	if defaultDuration == 0 {
+		defaultDuration = defaultFillDuration
+	}
+
+	result := &schemas.FillTimesResponse{
+		ItineraryID: itineraryID,
+		Entries:     []schemas.FilledEntryTime{},
+	}
+	ends := []int{}
+	var prevPoint *geo.Point
+	for _, entry := range itinerary.Entries {
+		point := entryPoint(entry)
+		travel := 0
+		if point != nil && prevPoint != nil {
+			travel = modeMinutes(req.TravelMode, geo.DistanceKm(*prevPoint, *point), s.travel)
+		}
+
+		if start, end, ok := entry.Schedule(); ok {
+			result.Entries = append(result.Entries, schemas.FilledEntryTime{
+				EntryID:       entry.ID.Hex(),
+				Title:         entry.Title,
+				StartTime:     models.FormatClock(start),
+				EndTime:       models.FormatClock(end),
+				TravelMinutes: travel,
+				Fixed:         true,
+			})
+			ends = append(ends, end)
+			// A fixed entry earlier than the plan so far doesn't pull later entries back
+			if end+req.BufferMinutes > cursor {
+				cursor = end + req.BufferMinutes
+			}
+			if point != nil {
+				prevPoint = point
+			}
+			continue
+		}
+
+		duration := 0
+		if entry.Duration != nil && *entry.Duration > 0 {
+			duration = *entry.Duration
+		} else if entry.Type == models.EntryTypePlace {
+			duration = defaultDuration
+		}
+		if duration == 0 {
+			continue
+		}
+
+		start := cursor + travel
+		end := start + duration
+		result.Entries = append(result.Entries, schemas.FilledEntryTime{
+			EntryID:       entry.ID.Hex(),
+			Title:         entry.Title,
+			StartTime:     models.FormatClock(start),
+			EndTime:       models.FormatClock(end),
+			TravelMinutes: travel,
+		})
+		ends = append(ends, end)
+		cursor = end + req.BufferMinutes
+		if point != nil {
+			prevPoint = point
+		}
+	}
+
+	// A planned entry that runs past the start of the next fixed one overlaps it
+	for i := range result.Entries {
+		if result.Entries[i].Fixed {
+			continue
+		}
+		for j := i + 1; j < len(result.Entries); j++ {
+			if !result.Entries[j].Fixed {
+				continue
+			}
+			nextStart, _ := models.ParseClock(result.Entries[j].StartTime)
+			result.Entries[i].Overlaps = ends[i] > nextStart
+			break
+		}
+	}
+
+	logger.Output(map[string]interface{}{
+		"entries": len(result.Entries),
+	})
+	return result, nil
+}
+
+// ApplyFillTimes saves the times FillTimes plans, If-Match carries the itinerary version
+func (s *ItineraryService) ApplyFillTimes(ctx context.Context, itineraryID string, req schemas.FillTimesRequest, expectedVersion *int64) (*schemas.FillTimesResponse, *models.Itinerary, error) {
+	ctx, span := s.tracer.Start(ctx, "ItineraryService.ApplyFillTimes")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	logger.Input(map[string]interface{}{
+		"itineraryID": itineraryID,
+		"dayStart":    req.DayStart,
+	})
+
+	tripID, err := s.ensureItineraryWritable(ctx, itineraryID)
+	if err != nil {
+		logger.Error(err)
+		return nil, nil, err
+	}
+
+	itinerary, err := s.itineraryRepo.FindByID(ctx, itineraryID)
+	if err != nil {
+		err := errors.New("itinerary not found")
+		logger.Error(err)
+		return nil, nil, err
+	}
+
+	if err := checkVersion(itinerary.Version, expectedVersion); err != nil {
+		logger.Error(err)
+		return nil, nil, err
+	}
+
+	result, err := s.FillTimes(ctx, itineraryID, req)
+	if err != nil {
+		logger.Error(err)
+		return nil, nil, err
+	}
+
+	// Claim the itinerary version first, a concurrent fill or reorder loses here before touching any entry
+	if err := s.itineraryRepo.Update(ctx, itinerary); err != nil {
+		logger.Error(err)
+		return nil, nil, err
+	}
+
+	for _, filled := range result.Entries {
+		if filled.Fixed {
+			continue
+		}
+		entry, err := s.entryRepo.FindByID(ctx, filled.EntryID)
+		if err != nil {
+			logger.Error(err)
+			return nil, nil, err
+		}
+		startTime, endTime := filled.StartTime, filled.EndTime
+		entry.StartTime = &startTime
+		entry.EndTime = &endTime
+		if err := s.entryRepo.Update(ctx, entry); err != nil {
+			logger.Error(err)
+			return nil, nil, err
+		}
+		s.publishTripEvent(tripID, sse.TripEventEntryUpdated, entry)
+	}
+	result.Applied = true
+
+	logger.Output(map[string]interface{}{
+		"entries": len(result.Entries),
+	})
+	return result, itinerary, nil
+}
+
+// ValidateItinerary checks a day's schedule, access is checked by the route middleware
+func (s *ItineraryService) ValidateItinerary(ctx context.Context, itineraryID string) ([]models.ScheduleWarning, error) {
+	ctx, span := s.tracer.Start(ctx, "ItineraryService.ValidateItinerary")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	logger.Input(map[string]interface{}{"itineraryID": itineraryID})
+
+	itinerary, err := s.itineraryRepo.FindByIDWithEntries(ctx, itineraryID)
+	if err != nil {
+		err := errors.New("itinerary not found")
+		logger.Error(err)
+		return nil, err
+	}
+
+	warnings := scheduleWarnings(itinerary, s.schedule)
+
+	logger.Output(map[string]interface{}{"warnings": len(warnings)})
+	return warnings, nil
+}
+
+// ValidateTrip checks the schedule of every day of a trip
+func (s *ItineraryService) ValidateTrip(ctx context.Context, tripID string) ([]models.ScheduleWarning, error) {
+	ctx, span := s.tracer.Start(ctx, "ItineraryService.ValidateTrip")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	logger.Input(map[string]interface{}{"tripID": tripID})
+
+	itineraries, err := s.itineraryRepo.FindByTripIDWithEntries(ctx, tripID)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	warnings := []models.ScheduleWarning{}
+	for _, itinerary := range itineraries {
+		warnings = append(warnings, scheduleWarnings(itinerary, s.schedule)...)
+	}
+
+	logger.Output(map[string]interface{}{"warnings": len(warnings)})
+	return warnings, nil
+}
+
+// EntryScheduleWarnings returns the warnings about an entry and its day after it was saved
+// Warnings are advisory, a failed check is logged and yields none.
+func (s *ItineraryService) EntryScheduleWarnings(ctx context.Context, entry *models.ItineraryEntry) []models.ScheduleWarning {
+	ctx, span := s.tracer.Start(ctx, "ItineraryService.EntryScheduleWarnings")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	logger.Input(map[string]interface{}{"entryID": entry.ID.Hex()})
+
+	itinerary, err := s.itineraryRepo.FindByIDWithEntries(ctx, entry.ItineraryID.Hex())
+	if err != nil {
+		logger.Error(err)
+		return nil
+	}
+
+	warnings := entryWarnings(scheduleWarnings(itinerary, s.schedule), entry.ID.Hex())
+
+	logger.Output(map[string]interface{}{"warnings": len(warnings)})
+	return warnings
 }
 
 // CountEntriesByItinerary counts entries in an itinerary (day)
@@ -835,7 +1823,7 @@ func (s *ItineraryService) CountItinerariesByTrip(ctx context.Context, tripID st
 
 	logger.Input(map[string]interface{}{"tripID": tripID})
 
-	count, err := s.itineraryRepo.CountByTripID(ctx, tripID)
+	count, err := s.itineraryRepo.CountDaysByTripID(ctx, tripID)
 	if err != nil {
 		logger.Error(err)
 		return 0, err
@@ -858,7 +1846,7 @@ func (s *ItineraryService) InsertItineraryAfter(ctx context.Context, tripID, iti
 		"userID":      userID,
 	})
 
-	// 1. Verify trip exists and user owns it
+	// 1. Verify trip exists and user can edit it
 	trip, err := s.tripRepo.FindByID(ctx, tripID)
 	if err != nil {
 		err := errors.New("trip not found")
@@ -866,8 +1854,14 @@ func (s *ItineraryService) InsertItineraryAfter(ctx context.Context, tripID, iti
 		return nil, err
 	}
 
-	if trip.OwnerID.Hex() != userID {
-		err := errors.New("unauthorized: you don't own this trip")
+	if !s.tripRepo.HasRole(trip, userID, models.MemberRoleOwner, models.MemberRoleEditor) {
+		err := errors.New("unauthorized: you can't edit this trip")
+		logger.Error(err)
+		return nil, err
+	}
+
+	if trip.IsArchived() {
+		err := errors.New("trip is archived")
 		logger.Error(err)
 		return nil, err
 	}
@@ -961,6 +1955,8 @@ func (s *ItineraryService) InsertItineraryAfter(ctx context.Context, tripID, iti
 		logger.Info("Extended trip endDate by 1 day")
 	}
 
+	s.publishDaysChanged(ctx, trip)
+
 	logger.Output(map[string]interface{}{
 		"newItineraryID": newItinerary.ID.Hex(),
 		"dayNumber":      newItinerary.DayNumber,
@@ -0,0 +1,114 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"backend-go/internal/config"
+	"backend-go/internal/models"
+)
+
+// scheduledEntry is an entry with its validated start and end in minutes since midnight
+type scheduledEntry struct {
+	entry      *models.ItineraryEntry
+	start, end int
+}
+
+// scheduleWarnings checks one day for overlaps, visits outside opening hours, end times before
+// start times and more scheduled time than limits allow, entries must have Place populated
+func scheduleWarnings(itinerary *models.Itinerary, limits *config.ScheduleConfig) []models.ScheduleWarning {
+	warnings := []models.ScheduleWarning{}
+
+	// The unscheduled bucket has no date and isn't a day's plan
+	if itinerary.Unscheduled {
+		return warnings
+	}
+
+	itineraryID := itinerary.ID.Hex()
+	date, dateErr := time.Parse("2006-01-02", itinerary.Date)
+
+	scheduled := []scheduledEntry{}
+	for _, entry := range itinerary.Entries {
+		start, end, ok := entry.Schedule()
+		if !ok {
+			continue
+		}
+
+		// Schedule reads an earlier end as past midnight, here it's more likely a typo,
+		// so the entry is flagged and only its start is checked further
+		if entry.EndTime != nil && *entry.EndTime != "" {
+			if rawEnd, err := models.ParseClock(*entry.EndTime); err == nil && rawEnd < start {
+				warnings = append(warnings, models.ScheduleWarning{
+					Type:        models.ScheduleWarningEndBeforeStart,
+					ItineraryID: itineraryID,
+					EntryID:     entry.ID.Hex(),
+					Message:     fmt.Sprintf("%s ends at %s, before it starts at %s", entry.Title, *entry.EndTime, *entry.StartTime),
+				})
+				end = start
+			}
+		}
+
+		scheduled = append(scheduled, scheduledEntry{entry: entry, start: start, end: end})
+
+		if dateErr == nil && entry.Type == models.EntryTypePlace && entry.Place != nil && entry.Place.OpeningHours != nil {
+			if open, known := entry.Place.OpeningHours.IsOpenDuring(date.Weekday(), start, end); known && !open {
+				warnings = append(warnings, models.ScheduleWarning{
+					Type:        models.ScheduleWarningClosed,
+					ItineraryID: itineraryID,
+					EntryID:     entry.ID.Hex(),
+					Message:     fmt.Sprintf("%s is not open %s on %s", entry.Title, visitSpan(start, end), date.Weekday()),
+				})
+			}
+		}
+	}
+
+	intervals := [][2]int{}
+	for i, a := range scheduled {
+		if a.end <= a.start {
+			continue
+		}
+		intervals = append(intervals, [2]int{a.start, a.end})
+
+		for _, b := range scheduled[i+1:] {
+			if b.end > b.start && a.start < b.end && b.start < a.end {
+				warnings = append(warnings, models.ScheduleWarning{
+					Type:         models.ScheduleWarningOverlap,
+					ItineraryID:  itineraryID,
+					EntryID:      a.entry.ID.Hex(),
+					OtherEntryID: b.entry.ID.Hex(),
+					Message:      fmt.Sprintf("%s (%s) overlaps %s (%s)", a.entry.Title, visitSpan(a.start, a.end), b.entry.Title, visitSpan(b.start, b.end)),
+				})
+			}
+		}
+	}
+
+	covered, _, _ := mergeIntervals(intervals)
+	if limit := limits.MaxDayHours; limit > 0 && float64(covered) > limit*60 {
+		warnings = append(warnings, models.ScheduleWarning{
+			Type:        models.ScheduleWarningDayTooLong,
+			ItineraryID: itineraryID,
+			Message:     fmt.Sprintf("%.1f hours are scheduled, more than the %.1f hour limit", float64(covered)/60, limit),
+		})
+	}
+
+	return warnings
+}
+
+// entryWarnings keeps the warnings about one entry and those about its whole day
+func entryWarnings(warnings []models.ScheduleWarning, entryID string) []models.ScheduleWarning {
+	kept := []models.ScheduleWarning{}
+	for _, warning := range warnings {
+		if warning.EntryID == "" || warning.EntryID == entryID || warning.OtherEntryID == entryID {
+			kept = append(kept, warning)
+		}
+	}
+	return kept
+}
+
+// visitSpan formats a visit as "at 09:00" or "09:00-11:30"
+func visitSpan(start, end int) string {
+	if end <= start {
+		return "at " + models.FormatClock(start)
+	}
+	return models.FormatClock(start) + "-" + models.FormatClock(end)
+}
@@ -0,0 +1,106 @@
+package services
+
+import (
+	"math"
+
+	"backend-go/internal/config"
+	"backend-go/internal/models"
+	"backend-go/pkg/geo"
+)
+
+// attachTravelLegs fills Legs on each day, entries must be sorted by order with Place populated
+// profiles are the speeds travel legs are estimated with
+func attachTravelLegs(itineraries []*models.Itinerary, profiles *config.TravelConfig) {
+	for _, itinerary := range itineraries {
+		// The unscheduled bucket isn't visited in order
+		if itinerary.Unscheduled {
+			continue
+		}
+		itinerary.Legs = travelLegs(itinerary.Entries, profiles)
+	}
+}
+
+// travelLegs estimates the legs between consecutive located place entries, notes and todos in between are skipped
+func travelLegs(entries []*models.ItineraryEntry, profiles *config.TravelConfig) []*models.TravelLeg {
+	legs := []*models.TravelLeg{}
+
+	var prev *models.ItineraryEntry
+	var prevPoint *geo.Point
+	for _, entry := range entries {
+		if entry.Type != models.EntryTypePlace {
+			continue
+		}
+		point := entryPoint(entry)
+		if point == nil {
+			continue
+		}
+
+		if prev != nil {
+			legs = append(legs, estimateLeg(prev, entry, geo.DistanceKm(*prevPoint, *point), profiles))
+		}
+		prev, prevPoint = entry, point
+	}
+	return legs
+}
+
+// estimateLeg estimates each travel mode and flags the modes that don't fit between the two entries
+func estimateLeg(from, to *models.ItineraryEntry, distanceKm float64, profiles *config.TravelConfig) *models.TravelLeg {
+	leg := &models.TravelLeg{
+		FromEntryID:    from.ID.Hex(),
+		ToEntryID:      to.ID.Hex(),
+		DistanceKm:     roundTo2(distanceKm),
+		WalkMinutes:    modeMinutes(models.TravelModeWalk, distanceKm, profiles),
+		TransitMinutes: modeMinutes(models.TravelModeTransit, distanceKm, profiles),
+		DriveMinutes:   modeMinutes(models.TravelModeDrive, distanceKm, profiles),
+	}
+
+	_, fromEnd, fromOk := from.Schedule()
+	toStart, _, toOk := to.Schedule()
+	if !fromOk || !toOk {
+		return leg
+	}
+
+	gap := toStart - fromEnd
+	leg.GapMinutes = &gap
+
+	modes := []struct {
+		name    string
+		minutes int
+	}{
+		{models.TravelModeWalk, leg.WalkMinutes},
+		{models.TravelModeTransit, leg.TransitMinutes},
+		{models.TravelModeDrive, leg.DriveMinutes},
+	}
+	fastest := leg.WalkMinutes
+	for _, mode := range modes {
+		if mode.minutes > gap {
+			leg.TooShortFor = append(leg.TooShortFor, mode.name)
+		}
+		if mode.minutes < fastest {
+			fastest = mode.minutes
+		}
+	}
+	leg.TooShort = gap < fastest
+	return leg
+}
+
+// modeMinutes estimates the travel time of a mode over a straight-line distance
+func modeMinutes(mode string, distanceKm float64, profiles *config.TravelConfig) int {
+	routeKm := distanceKm * profiles.DetourFactor
+	switch mode {
+	case models.TravelModeWalk:
+		return travelMinutes(routeKm, profiles.WalkSpeedKmh, 0)
+	case models.TravelModeDrive:
+		return travelMinutes(routeKm, profiles.DriveSpeedKmh, profiles.DriveOverheadMinutes)
+	default:
+		return travelMinutes(routeKm, profiles.TransitSpeedKmh, profiles.TransitOverheadMinutes)
+	}
+}
+
+// travelMinutes is the time to cover km at speed plus a fixed overhead, rounded up to whole minutes
+func travelMinutes(km, speedKmh float64, overheadMinutes int) int {
+	if speedKmh <= 0 || km == 0 {
+		return 0
+	}
+	return int(math.Ceil(km/speedKmh*60)) + overheadMinutes
+}
@@ -0,0 +1,312 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"backend-go/internal/models"
+	"backend-go/internal/repository"
+	"backend-go/internal/schemas"
+	"backend-go/pkg/sse"
+	"backend-go/pkg/utils"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type TripDocumentService struct {
+	documentRepo  *repository.TripDocumentRepository
+	fileRepo      *repository.FileRepository
+	tripRepo      *repository.TripRepository
+	itineraryRepo *repository.ItineraryRepository
+	entryRepo     *repository.ItineraryEntryRepository
+	tripHub       *sse.TripHub
+	tracer        trace.Tracer
+}
+
+func NewTripDocumentService(tripHub *sse.TripHub) *TripDocumentService {
+	return &TripDocumentService{
+		documentRepo:  repository.NewTripDocumentRepository(),
+		fileRepo:      repository.NewFileRepository(),
+		tripRepo:      repository.NewTripRepository(),
+		itineraryRepo: repository.NewItineraryRepository(),
+		entryRepo:     repository.NewItineraryEntryRepository(),
+		tripHub:       tripHub,
+		tracer:        otel.Tracer("trip-document-service"),
+	}
+}
+
+// ListDocuments lists a trip's documents with their files, optionally filtered by entry and category
+// Access is checked by the route middleware
+func (s *TripDocumentService) ListDocuments(ctx context.Context, tripID string, query schemas.ListTripDocumentsQuery) ([]*models.TripDocument, error) {
+	ctx, span := s.tracer.Start(ctx, "TripDocumentService.ListDocuments")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	logger.Input(map[string]interface{}{
+		"tripID":   tripID,
+		"entryID":  query.EntryID,
+		"category": query.Category,
+	})
+
+	documents, err := s.documentRepo.FindByTripID(ctx, tripID, query.EntryID, query.Category)
+	if err != nil {
+		err := errors.New("invalid entry ID")
+		logger.Error(err)
+		return nil, err
+	}
+
+	if err := s.populateFiles(ctx, documents); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	logger.Output(map[string]interface{}{
+		"count": len(documents),
+	})
+	return documents, nil
+}
+
+// GetDocument gets a document with its file
+func (s *TripDocumentService) GetDocument(ctx context.Context, documentID string) (*models.TripDocument, error) {
+	ctx, span := s.tracer.Start(ctx, "TripDocumentService.GetDocument")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	logger.Input(map[string]interface{}{
+		"documentID": documentID,
+	})
+
+	document, err := s.documentRepo.FindByID(ctx, documentID)
+	if err != nil {
+		err := errors.New("document not found")
+		logger.Error(err)
+		return nil, err
+	}
+
+	if err := s.populateFiles(ctx, []*models.TripDocument{document}); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	logger.Output(map[string]interface{}{
+		"fileID": document.FileID.Hex(),
+	})
+	return document, nil
+}
+
+// AttachDocument attaches one of the caller's uploaded files to a trip or one of its entries
+func (s *TripDocumentService) AttachDocument(ctx context.Context, tripID, userID string, req schemas.AttachTripDocumentRequest) (*models.TripDocument, error) {
+	ctx, span := s.tracer.Start(ctx, "TripDocumentService.AttachDocument")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	logger.Input(map[string]interface{}{
+		"tripID":   tripID,
+		"userID":   userID,
+		"fileID":   req.FileID,
+		"entryID":  req.EntryID,
+		"category": req.Category,
+	})
+
+	if err := s.ensureTripWritable(ctx, tripID); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	file, err := s.fileRepo.FindByID(ctx, req.FileID)
+	if err != nil {
+		err := errors.New("file not found")
+		logger.Error(err)
+		return nil, err
+	}
+
+	// Only the uploader can share a file, otherwise any member could expose someone else's passport
+	if file.OwnerID.Hex() != userID {
+		err := errors.New("unauthorized: you don't own this file")
+		logger.Error(err)
+		return nil, err
+	}
+
+	document, err := s.documentRepo.NewDocument(tripID, req.FileID, userID)
+	if err != nil {
+		err := errors.New("invalid IDs")
+		logger.Error(err)
+		return nil, err
+	}
+
+	if req.EntryID != nil && *req.EntryID != "" {
+		if err := s.ensureEntryInTrip(ctx, *req.EntryID, document.TripID); err != nil {
+			logger.Error(err)
+			return nil, err
+		}
+		if err := s.documentRepo.SetEntryID(document, *req.EntryID); err != nil {
+			err := errors.New("invalid entry ID")
+			logger.Error(err)
+			return nil, err
+		}
+	}
+
+	document.Category = req.Category
+	document.Title = req.Title
+	if document.Title == "" {
+		document.Title = file.Metadata["original_filename"]
+	}
+
+	if err := s.documentRepo.Create(ctx, document); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+	document.File = file
+
+	publishTripEvent(s.tripHub, document.TripID, sse.TripEventDocumentAdded, document)
+
+	logger.Output(map[string]interface{}{
+		"documentID": document.ID.Hex(),
+	})
+	return document, nil
+}
+
+// UpdateDocument recategorizes, renames or moves a document between the trip and its entries
+func (s *TripDocumentService) UpdateDocument(ctx context.Context, documentID string, req schemas.UpdateTripDocumentRequest) (*models.TripDocument, error) {
+	ctx, span := s.tracer.Start(ctx, "TripDocumentService.UpdateDocument")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	logger.Input(map[string]interface{}{
+		"documentID": documentID,
+		"entryID":    req.EntryID,
+		"category":   req.Category,
+	})
+
+	document, err := s.documentRepo.FindByID(ctx, documentID)
+	if err != nil {
+		err := errors.New("document not found")
+		logger.Error(err)
+		return nil, err
+	}
+
+	if err := s.ensureTripWritable(ctx, document.TripID.Hex()); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	if req.EntryID != nil {
+		if *req.EntryID != "" {
+			if err := s.ensureEntryInTrip(ctx, *req.EntryID, document.TripID); err != nil {
+				logger.Error(err)
+				return nil, err
+			}
+		}
+		if err := s.documentRepo.SetEntryID(document, *req.EntryID); err != nil {
+			err := errors.New("invalid entry ID")
+			logger.Error(err)
+			return nil, err
+		}
+	}
+	if req.Category != nil {
+		document.Category = *req.Category
+	}
+	if req.Title != nil {
+		document.Title = *req.Title
+	}
+
+	if err := s.documentRepo.Update(ctx, document); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	if err := s.populateFiles(ctx, []*models.TripDocument{document}); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	publishTripEvent(s.tripHub, document.TripID, sse.TripEventDocumentUpdated, document)
+
+	logger.Output(map[string]interface{}{
+		"documentID": document.ID.Hex(),
+	})
+	return document, nil
+}
+
+// DetachDocument removes a document from the trip, the file stays in its uploader's files
+func (s *TripDocumentService) DetachDocument(ctx context.Context, documentID string) error {
+	ctx, span := s.tracer.Start(ctx, "TripDocumentService.DetachDocument")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	logger.Input(map[string]interface{}{
+		"documentID": documentID,
+	})
+
+	document, err := s.documentRepo.FindByID(ctx, documentID)
+	if err != nil {
+		err := errors.New("document not found")
+		logger.Error(err)
+		return err
+	}
+
+	if err := s.ensureTripWritable(ctx, document.TripID.Hex()); err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	if err := s.documentRepo.Delete(ctx, documentID); err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	publishTripEvent(s.tripHub, document.TripID, sse.TripEventDocumentRemoved, deletedEvent{ID: documentID})
+
+	logger.Info("Document detached successfully")
+	return nil
+}
+
+// populateFiles fills File on each document with one query
+func (s *TripDocumentService) populateFiles(ctx context.Context, documents []*models.TripDocument) error {
+	fileIDs := make([]primitive.ObjectID, 0, len(documents))
+	for _, document := range documents {
+		fileIDs = append(fileIDs, document.FileID)
+	}
+
+	files, err := s.fileRepo.FindByIDs(ctx, fileIDs)
+	if err != nil {
+		return err
+	}
+
+	filesByID := make(map[primitive.ObjectID]*models.File, len(files))
+	for _, file := range files {
+		filesByID[file.ID] = file
+	}
+	for _, document := range documents {
+		document.File = filesByID[document.FileID]
+	}
+	return nil
+}
+
+// ensureEntryInTrip rejects entries of other trips
+func (s *TripDocumentService) ensureEntryInTrip(ctx context.Context, entryID string, tripID primitive.ObjectID) error {
+	entry, err := s.entryRepo.FindByID(ctx, entryID)
+	if err != nil {
+		return errors.New("entry not found")
+	}
+
+	itinerary, err := s.itineraryRepo.FindByID(ctx, entry.ItineraryID.Hex())
+	if err != nil || itinerary.TripID != tripID {
+		return errors.New("entry not found")
+	}
+	return nil
+}
+
+// ensureTripWritable rejects changes to archived trips, their documents are read-only until unarchived
+func (s *TripDocumentService) ensureTripWritable(ctx context.Context, tripID string) error {
+	trip, err := s.tripRepo.FindByID(ctx, tripID)
+	if err != nil {
+		return errors.New("trip not found")
+	}
+	if trip.IsArchived() {
+		return errors.New("trip is archived")
+	}
+	return nil
+}
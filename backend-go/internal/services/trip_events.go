@@ -0,0 +1,111 @@
+package services
+
+import (
+	"context"
+	"log"
+
+	"backend-go/internal/models"
+	"backend-go/internal/repository"
+	"backend-go/pkg/sse"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// publishTripEvent notifies members subscribed to a trip on /sse/trips/:id, it never fails the change
+// that caused it. Services get the hub through their constructor, a nil hub disables publishing.
+func publishTripEvent(hub *sse.TripHub, tripID primitive.ObjectID, eventType string, data interface{}) {
+	if hub == nil {
+		return
+	}
+	hub.Publish(sse.TripEvent{
+		Type:   eventType,
+		TripID: tripID.Hex(),
+		Data:   data,
+	})
+}
+
+// publishMemberRemoved tells members someone left and ends the removed user's own subscriptions
+func publishMemberRemoved(hub *sse.TripHub, tripID primitive.ObjectID, memberID string, userID primitive.ObjectID) {
+	if hub == nil {
+		return
+	}
+	hub.Publish(sse.TripEvent{
+		Type:     sse.TripEventMemberRemoved,
+		TripID:   tripID.Hex(),
+		Data:     deletedEvent{ID: memberID, UserID: userID.Hex()},
+		CloseFor: userID.Hex(),
+	})
+}
+
+// publishDaysChanged sends every day with its entries after days were added, removed, renumbered or re-dated
+// Clients replace their itinerary state with it, removedIDs lists days that no longer exist
+func publishDaysChanged(ctx context.Context, hub *sse.TripHub, itineraryRepo *repository.ItineraryRepository, trip *models.Trip, removedIDs ...string) {
+	if hub == nil {
+		return
+	}
+
+	days, err := itineraryRepo.FindByTripIDWithEntries(ctx, trip.ID.Hex())
+	if err != nil {
+		log.Printf("SSE: failed to load days for trip %s: %v", trip.ID.Hex(), err)
+		return
+	}
+
+	publishTripEvent(hub, trip.ID, sse.TripEventDaysChanged, daysChangedEvent{
+		StartDate:  trip.StartDate.Format("2006-01-02"),
+		EndDate:    trip.EndDate.Format("2006-01-02"),
+		Days:       days,
+		RemovedIDs: removedIDs,
+	})
+}
+
+// daysChangedEvent carries the trip's whole itinerary
+type daysChangedEvent struct {
+	StartDate  string              `json:"startDate"`
+	EndDate    string              `json:"endDate"`
+	Days       []*models.Itinerary `json:"days"`
+	RemovedIDs []string            `json:"removedIds,omitempty"`
+}
+
+// entryDeletedEvent identifies a removed entry
+type entryDeletedEvent struct {
+	ID          string `json:"id"`
+	ItineraryID string `json:"itineraryId"`
+}
+
+// entriesReorderedEvent lists a day's entry IDs in their new order
+type entriesReorderedEvent struct {
+	ItineraryID string   `json:"itineraryId"`
+	EntryIDs    []string `json:"entryIds"`
+}
+
+func newEntriesReorderedEvent(itineraryID primitive.ObjectID, entries []*models.ItineraryEntry) entriesReorderedEvent {
+	event := entriesReorderedEvent{
+		ItineraryID: itineraryID.Hex(),
+		EntryIDs:    make([]string, 0, len(entries)),
+	}
+	for _, entry := range entries {
+		event.EntryIDs = append(event.EntryIDs, entry.ID.Hex())
+	}
+	return event
+}
+
+// todosUpdatedEvent carries an entry's full todo list
+type todosUpdatedEvent struct {
+	EntryID     string        `json:"entryId"`
+	ItineraryID string        `json:"itineraryId"`
+	Todos       []models.Todo `json:"todos"`
+}
+
+func newTodosUpdatedEvent(entry *models.ItineraryEntry) todosUpdatedEvent {
+	return todosUpdatedEvent{
+		EntryID:     entry.ID.Hex(),
+		ItineraryID: entry.ItineraryID.Hex(),
+		Todos:       entry.Todos,
+	}
+}
+
+// deletedEvent identifies a removed day, expense or member
+type deletedEvent struct {
+	ID     string `json:"id"`
+	UserID string `json:"userId,omitempty"` // Removed member's user
+}
@@ -0,0 +1,659 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+
+	"backend-go/internal/config"
+	"backend-go/internal/models"
+	"backend-go/internal/repository"
+	"backend-go/pkg/booklet"
+	"backend-go/pkg/ical"
+	"backend-go/pkg/mapexport"
+	"backend-go/pkg/utils"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	icalProdID = "-//Painaina//Trip Itinerary//EN"
+	icalDomain = "painaina"
+
+	// defaultEventDuration is used for entries that have a start time but neither end time nor duration
+	defaultEventDuration = 60
+
+	// calendarRefreshInterval hints subscribed calendar apps how often to refetch the feed
+	calendarRefreshInterval = time.Hour
+)
+
+// Map export formats
+const (
+	MapFormatGeoJSON = "geojson"
+	MapFormatGPX     = "gpx"
+	MapFormatKML     = "kml"
+)
+
+type TripExportService struct {
+	tripRepo       *repository.TripRepository
+	itineraryRepo  *repository.ItineraryRepository
+	expenseRepo    *repository.ExpenseRepository
+	userRepo       *repository.UserRepository
+	bookletOptions booklet.Options
+	coverMaxSize   int64
+	coverHosts     map[string]bool
+	httpClient     *http.Client
+	tracer         trace.Tracer
+}
+
+func NewTripExportService(cfg *config.BookletConfig) *TripExportService {
+	coverHosts := make(map[string]bool, len(cfg.CoverAllowedHosts))
+	for _, entry := range cfg.CoverAllowedHosts {
+		if host := coverHostname(entry); host != "" {
+			coverHosts[host] = true
+		}
+	}
+
+	return &TripExportService{
+		tripRepo:      repository.NewTripRepository(),
+		itineraryRepo: repository.NewItineraryRepository(),
+		expenseRepo:   repository.NewExpenseRepository(),
+		userRepo:      repository.NewUserRepository(),
+		bookletOptions: booklet.Options{
+			FontPath:     cfg.FontPath,
+			BoldFontPath: cfg.BoldFontPath,
+		},
+		coverMaxSize: cfg.CoverMaxSizeBytes,
+		coverHosts:   coverHosts,
+		httpClient:   newCoverClient(time.Duration(cfg.CoverTimeout) * time.Second),
+		tracer:       otel.Tracer("trip-export-service"),
+	}
+}
+
+// coverHostname reduces an allowed cover host entry to a lowercase hostname, entries may be
+// configured as full URLs (R2_DEV_SUBDOMAIN is https://xxx.r2.dev) or with a port or path
+func coverHostname(entry string) string {
+	entry = strings.TrimSpace(entry)
+	if entry == "" {
+		return ""
+	}
+	if !strings.Contains(entry, "://") {
+		entry = "//" + entry
+	}
+	parsed, err := url.Parse(entry)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(parsed.Hostname())
+}
+
+// newCoverClient downloads cover photos without following redirects and refuses to
+// connect to loopback, private or link-local addresses, whatever the host resolves to
+func newCoverClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			ip := net.ParseIP(host)
+			if ip == nil || !ip.IsGlobalUnicast() || ip.IsPrivate() {
+				return fmt.Errorf("cover photo address %s is not public", host)
+			}
+			return nil
+		},
+	}
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: &http.Transport{DialContext: dialer.DialContext},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return errors.New("cover photo redirects are not followed")
+		},
+	}
+}
+
+// ExportICS renders the trip itinerary as an iCalendar document
+// Access is checked by the route middleware
+func (s *TripExportService) ExportICS(ctx context.Context, tripID string) ([]byte, *models.Trip, error) {
+	ctx, span := s.tracer.Start(ctx, "TripExportService.ExportICS")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	logger.Input(map[string]interface{}{
+		"tripID": tripID,
+	})
+
+	trip, err := s.tripRepo.FindByID(ctx, tripID)
+	if err != nil {
+		err := errors.New("trip not found")
+		logger.Error(err)
+		return nil, nil, err
+	}
+
+	data, err := s.renderICS(ctx, trip)
+	if err != nil {
+		logger.Error(err)
+		return nil, nil, err
+	}
+
+	logger.Output(map[string]interface{}{
+		"bytes": len(data),
+	})
+	return data, trip, nil
+}
+
+// ExportMap renders the trip places as GeoJSON, GPX or KML, grouped per day in itinerary order
+// Access is checked by the route middleware
+func (s *TripExportService) ExportMap(ctx context.Context, tripID, format string) ([]byte, *models.Trip, error) {
+	ctx, span := s.tracer.Start(ctx, "TripExportService.ExportMap")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	logger.Input(map[string]interface{}{
+		"tripID": tripID,
+		"format": format,
+	})
+
+	trip, err := s.tripRepo.FindByID(ctx, tripID)
+	if err != nil {
+		err := errors.New("trip not found")
+		logger.Error(err)
+		return nil, nil, err
+	}
+
+	itineraries, err := s.itineraryRepo.FindByTripIDWithEntries(ctx, tripID)
+	if err != nil {
+		logger.Error(err)
+		return nil, nil, err
+	}
+
+	doc := buildMapDocument(trip, itineraries)
+
+	var data []byte
+	switch format {
+	case MapFormatGeoJSON:
+		data, err = doc.GeoJSON()
+	case MapFormatGPX:
+		data, err = doc.GPX()
+	case MapFormatKML:
+		data, err = doc.KML()
+	default:
+		err = errors.New("unsupported export format")
+	}
+	if err != nil {
+		logger.Error(err)
+		return nil, nil, err
+	}
+
+	logger.Output(map[string]interface{}{
+		"days":  len(doc.Days),
+		"bytes": len(data),
+	})
+	return data, trip, nil
+}
+
+// ExportBooklet renders a printable PDF with cover and day-by-day itinerary, members and budget
+// summary are only included for trip members. Access is checked by the route middleware
+func (s *TripExportService) ExportBooklet(ctx context.Context, tripID, viewerID string) ([]byte, *models.Trip, error) {
+	ctx, span := s.tracer.Start(ctx, "TripExportService.ExportBooklet")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	logger.Input(map[string]interface{}{
+		"tripID":   tripID,
+		"viewerID": viewerID,
+	})
+
+	trip, err := s.tripRepo.FindByID(ctx, tripID)
+	if err != nil {
+		err := errors.New("trip not found")
+		logger.Error(err)
+		return nil, nil, err
+	}
+
+	itineraries, err := s.itineraryRepo.FindByTripIDWithEntries(ctx, tripID)
+	if err != nil {
+		logger.Error(err)
+		return nil, nil, err
+	}
+
+	doc := &booklet.Booklet{
+		Title:       trip.Title,
+		Subtitle:    fmt.Sprintf("%s - %s", trip.StartDate.Format("2 Jan 2006"), trip.EndDate.Format("2 Jan 2006")),
+		Destination: strings.Trim(trip.Destinations.Name+", "+trip.Destinations.Country, ", "),
+		Days:        bookletDays(trip, itineraries),
+	}
+	if trip.Description != nil {
+		doc.Description = *trip.Description
+	}
+
+	// Who travels and what they spend stays with the members, public trips can be printed by anyone
+	if viewerID != "" && (s.tripRepo.IsOwner(trip, viewerID) || s.tripRepo.IsMemberExists(trip, viewerID)) {
+		totals, err := s.expenseRepo.GetTotalByCategory(ctx, tripID)
+		if err != nil {
+			logger.Error(err)
+			return nil, nil, err
+		}
+
+		doc.Members = s.bookletMembers(ctx, trip)
+		doc.Budget = booklet.Budget{
+			Planned:    trip.BudgetTotal,
+			Categories: totals,
+		}
+		if trip.BudgetCurrency != nil {
+			doc.Budget.Currency = *trip.BudgetCurrency
+		}
+	}
+
+	// The cover is best-effort, the booklet is still useful without it
+	if trip.CoverPhoto != nil && *trip.CoverPhoto != "" {
+		cover, err := s.fetchCover(ctx, *trip.CoverPhoto)
+		if err != nil {
+			logger.Warn("Failed to fetch cover photo: " + err.Error())
+		}
+		doc.Cover = cover
+	}
+
+	var buf bytes.Buffer
+	if err := booklet.Render(&buf, doc, s.bookletOptions); err != nil {
+		logger.Error(err)
+		return nil, nil, err
+	}
+
+	logger.Output(map[string]interface{}{
+		"days":  len(doc.Days),
+		"bytes": buf.Len(),
+	})
+	return buf.Bytes(), trip, nil
+}
+
+// ExportICSByToken renders the subscribable calendar feed identified by its token
+func (s *TripExportService) ExportICSByToken(ctx context.Context, token string) ([]byte, error) {
+	ctx, span := s.tracer.Start(ctx, "TripExportService.ExportICSByToken")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	trip, err := s.tripRepo.FindByCalendarToken(ctx, token)
+	if err != nil {
+		err := errors.New("calendar feed not found")
+		logger.Error(err)
+		return nil, err
+	}
+
+	data, err := s.renderICS(ctx, trip)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	logger.Output(map[string]interface{}{
+		"tripID": trip.ID.Hex(),
+		"bytes":  len(data),
+	})
+	return data, nil
+}
+
+// GetCalendarFeedToken returns the trip calendar feed token, creating it on first use
+// Only trip members can subscribe since the feed is readable by anyone holding the token
+func (s *TripExportService) GetCalendarFeedToken(ctx context.Context, tripID, userID string) (string, error) {
+	ctx, span := s.tracer.Start(ctx, "TripExportService.GetCalendarFeedToken")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	logger.Input(map[string]interface{}{
+		"tripID": tripID,
+		"userID": userID,
+	})
+
+	trip, err := s.tripRepo.FindByID(ctx, tripID)
+	if err != nil {
+		err := errors.New("trip not found")
+		logger.Error(err)
+		return "", err
+	}
+
+	if s.tripRepo.GetMemberRole(trip, userID) == "" {
+		err := errors.New("unauthorized: only trip members can subscribe to the calendar")
+		logger.Error(err)
+		return "", err
+	}
+
+	if trip.CalendarToken != nil {
+		logger.Output("existing token")
+		return *trip.CalendarToken, nil
+	}
+
+	token, err := generateInviteToken()
+	if err != nil {
+		logger.Error(err)
+		return "", err
+	}
+
+	token, err = s.tripRepo.ClaimCalendarToken(ctx, trip.ID, token)
+	if err != nil {
+		logger.Error(err)
+		return "", err
+	}
+
+	logger.Output("token created")
+	return token, nil
+}
+
+// RevokeCalendarFeed invalidates the trip calendar feed, existing subscriptions stop updating
+func (s *TripExportService) RevokeCalendarFeed(ctx context.Context, tripID, userID string) error {
+	ctx, span := s.tracer.Start(ctx, "TripExportService.RevokeCalendarFeed")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	logger.Input(map[string]interface{}{
+		"tripID": tripID,
+		"userID": userID,
+	})
+
+	trip, err := s.tripRepo.FindByID(ctx, tripID)
+	if err != nil {
+		err := errors.New("trip not found")
+		logger.Error(err)
+		return err
+	}
+
+	if !s.tripRepo.IsOwner(trip, userID) {
+		err := errors.New("unauthorized: only owner can revoke the calendar feed")
+		logger.Error(err)
+		return err
+	}
+
+	if trip.CalendarToken == nil {
+		logger.Info("No calendar feed to revoke")
+		return nil
+	}
+
+	if _, err := s.tripRepo.ReplaceCalendarToken(ctx, trip.ID, nil); err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	logger.Info("Calendar feed revoked")
+	return nil
+}
+
+// renderICS builds an all-day event for each itinerary day and a timed event for each scheduled entry
+func (s *TripExportService) renderICS(ctx context.Context, trip *models.Trip) ([]byte, error) {
+	itineraries, err := s.itineraryRepo.FindByTripIDWithEntries(ctx, trip.ID.Hex())
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	calendar := &ical.Calendar{
+		ProdID:          icalProdID,
+		Name:            trip.Title,
+		RefreshInterval: calendarRefreshInterval,
+	}
+
+	for _, itinerary := range itineraries {
+		day, ok := itineraryDate(trip, itinerary)
+		if !ok {
+			continue
+		}
+
+		summary := itinerary.Title
+		if summary == "" {
+			summary = fmt.Sprintf("Day %d", itinerary.DayNumber)
+		}
+		calendar.Events = append(calendar.Events, ical.Event{
+			UID:         fmt.Sprintf("%s@%s", itinerary.ID.Hex(), icalDomain),
+			Summary:     fmt.Sprintf("%s - %s", trip.Title, summary),
+			Description: daySummary(itinerary),
+			Start:       day,
+			AllDay:      true,
+			Stamp:       now,
+		})
+
+		for _, entry := range itinerary.Entries {
+			start, end, ok := entry.Schedule()
+			if !ok {
+				continue
+			}
+			if end <= start {
+				end = start + defaultEventDuration
+			}
+
+			event := ical.Event{
+				UID:     fmt.Sprintf("%s@%s", entry.ID.Hex(), icalDomain),
+				Summary: entry.Title,
+				Start:   day.Add(time.Duration(start) * time.Minute),
+				End:     day.Add(time.Duration(end) * time.Minute),
+				Stamp:   now,
+			}
+			if entry.Description != nil {
+				event.Description = *entry.Description
+			}
+			if entry.Place != nil {
+				event.Location = entry.Place.Address
+				if entry.Place.Name != "" && !strings.Contains(entry.Place.Address, entry.Place.Name) {
+					event.Location = strings.TrimSuffix(entry.Place.Name+", "+entry.Place.Address, ", ")
+				}
+				if lat, lng, ok := entry.Place.Location.LatLng(); ok {
+					event.Geo = &ical.Geo{Lat: lat, Lng: lng}
+				}
+				if entry.Place.Website != nil {
+					event.URL = *entry.Place.Website
+				}
+			}
+			calendar.Events = append(calendar.Events, event)
+		}
+	}
+
+	return calendar.Marshal(), nil
+}
+
+// buildMapDocument collects place entries with a known location, skipping days without any
+func buildMapDocument(trip *models.Trip, itineraries []*models.Itinerary) *mapexport.Document {
+	doc := &mapexport.Document{Name: trip.Title}
+	if trip.Description != nil {
+		doc.Description = *trip.Description
+	}
+
+	for _, itinerary := range itineraries {
+		day := mapexport.Day{
+			Number: itinerary.DayNumber,
+			Title:  itinerary.Title,
+			Date:   itinerary.Date,
+		}
+
+		for _, entry := range itinerary.Entries {
+			if entry.Type != models.EntryTypePlace || entry.Place == nil {
+				continue
+			}
+			lat, lng, ok := entry.Place.Location.LatLng()
+			if !ok {
+				continue
+			}
+
+			waypoint := mapexport.Waypoint{
+				ID:      entry.ID.Hex(),
+				Name:    entry.Title,
+				Address: entry.Place.Address,
+				Lat:     lat,
+				Lng:     lng,
+			}
+			if waypoint.Name == "" {
+				waypoint.Name = entry.Place.Name
+			}
+			if entry.Description != nil {
+				waypoint.Description = *entry.Description
+			}
+			if entry.StartTime != nil {
+				waypoint.StartTime = *entry.StartTime
+			}
+			day.Waypoints = append(day.Waypoints, waypoint)
+		}
+
+		if len(day.Waypoints) > 0 {
+			doc.Days = append(doc.Days, day)
+		}
+	}
+
+	return doc
+}
+
+// bookletDays converts itinerary days and entries into printable sections
+func bookletDays(trip *models.Trip, itineraries []*models.Itinerary) []booklet.Day {
+	days := make([]booklet.Day, 0, len(itineraries))
+
+	for _, itinerary := range itineraries {
+		day := booklet.Day{
+			Heading: itinerary.Title,
+			Entries: make([]booklet.Entry, 0, len(itinerary.Entries)),
+		}
+		if day.Heading == "" {
+			day.Heading = fmt.Sprintf("Day %d", itinerary.DayNumber)
+		}
+		if date, ok := itineraryDate(trip, itinerary); ok {
+			day.Date = date.Format("Mon, 2 Jan 2006")
+		}
+
+		for _, entry := range itinerary.Entries {
+			item := booklet.Entry{Title: entry.Title}
+
+			if start, end, ok := entry.Schedule(); ok {
+				item.Time = models.FormatClock(start)
+				if end > start {
+					item.Time += " - " + models.FormatClock(end)
+				}
+			}
+			if entry.Place != nil {
+				item.Address = entry.Place.Address
+				if item.Title == "" {
+					item.Title = entry.Place.Name
+				}
+			}
+
+			notes := make([]string, 0, len(entry.Todos)+1)
+			if entry.Description != nil && *entry.Description != "" {
+				notes = append(notes, *entry.Description)
+			}
+			for _, todo := range entry.Todos {
+				mark := "[ ]"
+				if todo.Completed {
+					mark = "[x]"
+				}
+				notes = append(notes, mark+" "+todo.Title)
+			}
+			item.Notes = strings.Join(notes, "\n")
+
+			day.Entries = append(day.Entries, item)
+		}
+
+		days = append(days, day)
+	}
+
+	return days
+}
+
+// bookletMembers lists the owner first followed by the other members
+func (s *TripExportService) bookletMembers(ctx context.Context, trip *models.Trip) []booklet.Member {
+	members := make([]booklet.Member, 0, len(trip.TripMembers)+1)
+
+	if owner, err := s.userRepo.FindByID(ctx, trip.OwnerID.Hex()); err == nil {
+		members = append(members, booklet.Member{Name: owner.Name, Role: models.MemberRoleOwner})
+	}
+
+	for _, member := range trip.TripMembers {
+		if member.UserID == trip.OwnerID {
+			continue
+		}
+		user, err := s.userRepo.FindByID(ctx, member.UserID.Hex())
+		if err != nil {
+			continue
+		}
+		members = append(members, booklet.Member{Name: user.Name, Role: member.Role})
+	}
+
+	return members
+}
+
+// fetchCover downloads the cover photo, only formats supported by the PDF renderer are kept
+// The URL is owner supplied, so only HTTPS URLs on the allowed hosts are fetched.
+func (s *TripExportService) fetchCover(ctx context.Context, rawURL string) (*booklet.Image, error) {
+	coverURL, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	if coverURL.Scheme != "https" || !s.coverHosts[strings.ToLower(coverURL.Hostname())] {
+		return nil, errors.New("cover photo host is not allowed")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, coverURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, s.coverMaxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > s.coverMaxSize {
+		return nil, errors.New("cover photo is too large")
+	}
+
+	switch http.DetectContentType(data) {
+	case "image/jpeg":
+		return &booklet.Image{Data: data, Type: "jpg"}, nil
+	case "image/png":
+		return &booklet.Image{Data: data, Type: "png"}, nil
+	case "image/gif":
+		return &booklet.Image{Data: data, Type: "gif"}, nil
+	default:
+		return nil, errors.New("unsupported cover photo format")
+	}
+}
+
+// itineraryDate resolves the calendar date of a day, falling back to the trip start date + day number
+func itineraryDate(trip *models.Trip, itinerary *models.Itinerary) (time.Time, bool) {
+	if itinerary.Date != "" {
+		if date, err := time.Parse("2006-01-02", itinerary.Date); err == nil {
+			return date, true
+		}
+	}
+	if trip.StartDate.IsZero() || itinerary.DayNumber < 1 {
+		return time.Time{}, false
+	}
+	start := trip.StartDate
+	return time.Date(start.Year(), start.Month(), start.Day()+itinerary.DayNumber-1, 0, 0, 0, 0, time.UTC), true
+}
+
+// daySummary lists the entries of a day, used as the all-day event description
+func daySummary(itinerary *models.Itinerary) string {
+	lines := make([]string, 0, len(itinerary.Entries))
+	for _, entry := range itinerary.Entries {
+		line := entry.Title
+		if entry.StartTime != nil && *entry.StartTime != "" {
+			line = *entry.StartTime + " " + line
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,64 @@
+package services
+
+import (
+	"testing"
+
+	"backend-go/internal/models"
+)
+
+func TestBuildMapDocumentSkipsEntriesWithoutCoordinates(t *testing.T) {
+	trip := &models.Trip{Title: "Coast"}
+	itineraries := []*models.Itinerary{
+		{
+			DayNumber: 1,
+			Entries: []*models.ItineraryEntry{
+				{Type: models.EntryTypePlace, Place: &models.Place{
+					Name:     "Louvre",
+					Location: models.GeoPoint{Coordinates: []float64{2.3376, 48.8606}},
+				}},
+				{Type: models.EntryTypePlace, Title: "No location", Place: &models.Place{Name: "Somewhere"}},
+				{Type: models.EntryTypePlace, Title: "No place"},
+				{Type: models.EntryTypeNote, Title: "Note"},
+			},
+		},
+		{
+			DayNumber: 2,
+			Entries: []*models.ItineraryEntry{
+				{Type: models.EntryTypePlace, Title: "No location", Place: &models.Place{Name: "Nowhere"}},
+			},
+		},
+	}
+
+	doc := buildMapDocument(trip, itineraries)
+
+	if len(doc.Days) != 1 {
+		t.Fatalf("got %d days, want only the day with a located place", len(doc.Days))
+	}
+	waypoints := doc.Days[0].Waypoints
+	if len(waypoints) != 1 {
+		t.Fatalf("got %d waypoints, want 1", len(waypoints))
+	}
+	if wp := waypoints[0]; wp.Name != "Louvre" || wp.Lat != 48.8606 || wp.Lng != 2.3376 {
+		t.Errorf("waypoint = %+v", wp)
+	}
+}
+
+func TestCoverHostname(t *testing.T) {
+	tests := []struct {
+		entry string
+		want  string
+	}{
+		{"images.unsplash.com", "images.unsplash.com"},
+		{"https://pub-123.r2.dev", "pub-123.r2.dev"},
+		{"https://Pub-123.R2.dev/covers/", "pub-123.r2.dev"},
+		{"cdn.example.com:443", "cdn.example.com"},
+		{" plus.unsplash.com ", "plus.unsplash.com"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := coverHostname(tt.entry); got != tt.want {
+			t.Errorf("coverHostname(%q) = %q, want %q", tt.entry, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,701 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"backend-go/internal/config"
+	"backend-go/internal/models"
+	"backend-go/internal/repository"
+	"backend-go/internal/schemas"
+	"backend-go/pkg/ical"
+	"backend-go/pkg/sse"
+	"backend-go/pkg/utils"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Import formats
+const (
+	ImportFormatICS  = "ics"
+	ImportFormatCSV  = "csv"
+	ImportFormatJSON = "json"
+)
+
+const (
+	maxImportRows = 1000
+	maxImportDays = 365
+)
+
+type TripImportService struct {
+	itineraryService *ItineraryService
+	tripRepo         *repository.TripRepository
+	itineraryRepo    *repository.ItineraryRepository
+	placeRepo        *repository.PlaceRepository
+	tripHub          *sse.TripHub
+	tracer           trace.Tracer
+}
+
+func NewTripImportService(tripHub *sse.TripHub, travel *config.TravelConfig, schedule *config.ScheduleConfig) *TripImportService {
+	return &TripImportService{
+		itineraryService: NewItineraryService(tripHub, travel, schedule),
+		tripRepo:         repository.NewTripRepository(),
+		itineraryRepo:    repository.NewItineraryRepository(),
+		placeRepo:        repository.NewPlaceRepository(),
+		tripHub:          tripHub,
+		tracer:           otel.Tracer("trip-import-service"),
+	}
+}
+
+// importRow is a parsed entry waiting to be placed on a day
+// A row is positioned either by dayNumber (CSV, JSON) or by date (ICS, CSV with dates)
+type importRow struct {
+	row        int
+	dayNumber  int
+	date       time.Time
+	entryType  models.EntryType
+	title      string
+	notes      string
+	placeName  string
+	place      *schemas.PlaceData
+	startTime  *string
+	endTime    *string
+	todos      []models.Todo
+	skipReason string
+	parseError error
+}
+
+// ImportItinerary parses an ICS, CSV or JSON file and appends its entries to the trip itinerary
+// Missing days are created and trip dates are extended to cover imported rows.
+// Zoned ICS times are converted to location, the zone the trip is planned in (nil uses the
+// calendar's X-WR-TIMEZONE, then each event's own zone).
+// Each row is reported as imported, skipped (empty, all-day or duplicate) or failed.
+func (s *TripImportService) ImportItinerary(ctx context.Context, tripID, userID, format string, location *time.Location, data []byte) (*schemas.ImportReport, error) {
+	ctx, span := s.tracer.Start(ctx, "TripImportService.ImportItinerary")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	logger.Input(map[string]interface{}{
+		"tripID": tripID,
+		"userID": userID,
+		"format": format,
+		"bytes":  len(data),
+	})
+
+	trip, err := s.tripRepo.FindByID(ctx, tripID)
+	if err != nil {
+		err := errors.New("trip not found")
+		logger.Error(err)
+		return nil, err
+	}
+
+	if !s.tripRepo.HasRole(trip, userID, models.MemberRoleOwner, models.MemberRoleEditor) {
+		err := errors.New("unauthorized: you can't edit this trip")
+		logger.Error(err)
+		return nil, err
+	}
+
+	if trip.IsArchived() {
+		err := errors.New("trip is archived")
+		logger.Error(err)
+		return nil, err
+	}
+
+	var rows []*importRow
+	switch format {
+	case ImportFormatICS:
+		rows, err = parseICSRows(data, location)
+	case ImportFormatCSV:
+		rows, err = parseCSVRows(data)
+	case ImportFormatJSON:
+		rows, err = parseJSONRows(data)
+	default:
+		err = errors.New("unsupported import format")
+	}
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	if len(rows) > maxImportRows {
+		err := fmt.Errorf("invalid import file: too many rows (max %d)", maxImportRows)
+		logger.Error(err)
+		return nil, err
+	}
+
+	itineraries, err := s.itineraryRepo.FindByTripIDWithEntries(ctx, tripID)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	// Move the trip start back when rows are dated before it, existing days shift accordingly
+	datesChanged := false
+	tripStart := dateOnly(trip.StartDate)
+	if tripStart.IsZero() {
+		tripStart = earliestRowDate(rows)
+		trip.StartDate = tripStart
+		datesChanged = true
+	}
+	resolveRowDays(rows, tripStart)
+
+	if shift := 1 - minRowDay(rows); shift > 0 {
+		if err := s.shiftDays(ctx, itineraries, shift); err != nil {
+			logger.Error(err)
+			return nil, err
+		}
+		for _, row := range rows {
+			if !row.date.IsZero() || row.dayNumber >= 1 {
+				row.dayNumber += shift
+			}
+		}
+		tripStart = tripStart.AddDate(0, 0, -shift)
+		trip.StartDate = tripStart
+		datesChanged = true
+	}
+
+	days := make(map[int]*models.Itinerary, len(itineraries))
+	for _, itinerary := range itineraries {
+		days[itinerary.DayNumber] = itinerary
+	}
+
+	report := &schemas.ImportReport{
+		Format: format,
+		Rows:   make([]schemas.ImportRowResult, 0, len(rows)),
+	}
+	maxDay := 0
+
+	for _, row := range rows {
+		result := schemas.ImportRowResult{Row: row.row, Title: row.title, DayNumber: row.dayNumber}
+
+		switch {
+		case row.parseError != nil:
+			result.Status = schemas.ImportStatusFailed
+			result.Message = row.parseError.Error()
+		case row.skipReason != "":
+			result.Status = schemas.ImportStatusSkipped
+			result.Message = row.skipReason
+		case row.dayNumber < 1 || row.dayNumber > maxImportDays:
+			result.Status = schemas.ImportStatusFailed
+			result.Message = fmt.Sprintf("day %d is out of range (1-%d)", row.dayNumber, maxImportDays)
+		default:
+			itinerary, created, err := s.ensureDay(ctx, tripID, tripStart, days, row.dayNumber)
+			if err != nil {
+				result.Status = schemas.ImportStatusFailed
+				result.Message = err.Error()
+				break
+			}
+			if created {
+				report.CreatedDays++
+			}
+
+			if hasDuplicateEntry(itinerary, row) {
+				result.Status = schemas.ImportStatusSkipped
+				result.Message = "an entry with the same title and time already exists on this day"
+				break
+			}
+
+			entry, err := s.createEntry(ctx, itinerary, row)
+			if err != nil {
+				result.Status = schemas.ImportStatusFailed
+				result.Message = err.Error()
+				break
+			}
+
+			itinerary.Entries = append(itinerary.Entries, entry)
+			entryID := entry.ID.Hex()
+			result.EntryID = &entryID
+			result.Status = schemas.ImportStatusImported
+			if row.dayNumber > maxDay {
+				maxDay = row.dayNumber
+			}
+		}
+
+		switch result.Status {
+		case schemas.ImportStatusImported:
+			report.Imported++
+		case schemas.ImportStatusSkipped:
+			report.Skipped++
+		default:
+			report.Failed++
+		}
+		report.Rows = append(report.Rows, result)
+	}
+
+	// Extend the trip end date to the last imported day
+	if lastDay := tripStart.AddDate(0, 0, maxDay-1); maxDay > 0 && lastDay.After(dateOnly(trip.EndDate)) {
+		trip.EndDate = lastDay
+		datesChanged = true
+	}
+	if datesChanged {
+		if err := s.tripRepo.Update(ctx, trip); err != nil {
+			logger.Error(err)
+			return nil, err
+		}
+		// Days were shifted in place, the per-entry events don't cover that
+		publishDaysChanged(ctx, s.tripHub, s.itineraryRepo, trip)
+	}
+
+	report.StartDate = trip.StartDate.Format("2006-01-02")
+	report.EndDate = trip.EndDate.Format("2006-01-02")
+
+	logger.Output(map[string]interface{}{
+		"imported":    report.Imported,
+		"skipped":     report.Skipped,
+		"failed":      report.Failed,
+		"createdDays": report.CreatedDays,
+	})
+	return report, nil
+}
+
+// ensureDay returns the itinerary for a day number, creating it through ItineraryService when missing
+func (s *TripImportService) ensureDay(ctx context.Context, tripID string, tripStart time.Time, days map[int]*models.Itinerary, dayNumber int) (*models.Itinerary, bool, error) {
+	if itinerary, ok := days[dayNumber]; ok {
+		return itinerary, false, nil
+	}
+
+	date := tripStart.AddDate(0, 0, dayNumber-1).Format("2006-01-02")
+	itinerary, err := s.itineraryService.CreateItinerary(ctx, tripID, dayNumber, date, "Day "+strconv.Itoa(dayNumber), dayNumber)
+	if err != nil {
+		return nil, false, err
+	}
+
+	days[dayNumber] = itinerary
+	return itinerary, true, nil
+}
+
+// createEntry creates the row entry at the end of the day
+// Place names are matched against cached places, unmatched names are kept in the description
+func (s *TripImportService) createEntry(ctx context.Context, itinerary *models.Itinerary, row *importRow) (*models.ItineraryEntry, error) {
+	entryType := row.entryType
+	description := row.notes
+	order := len(itinerary.Entries)
+
+	var placeID *string
+	var placeData interface{}
+	if row.place != nil && row.place.PlaceID != "" {
+		placeData = row.place
+		entryType = models.EntryTypePlace
+	} else if row.placeName != "" {
+		if place := s.matchPlace(ctx, row.placeName); place != nil {
+			id := place.ID.Hex()
+			placeID = &id
+			entryType = models.EntryTypePlace
+		} else {
+			description = strings.TrimSpace(row.placeName + "\n" + description)
+			if entryType == models.EntryTypePlace {
+				entryType = models.EntryTypeNote
+			}
+		}
+	} else if entryType == models.EntryTypePlace {
+		entryType = models.EntryTypeNote
+	}
+	if entryType == "" {
+		entryType = models.EntryTypeNote
+	}
+
+	return s.itineraryService.CreateEntry(
+		ctx,
+		itinerary.ID.Hex(),
+		entryType,
+		row.title,
+		description,
+		placeID,
+		placeData,
+		row.startTime,
+		row.endTime,
+		&order,
+		row.todos,
+	)
+}
+
+// matchPlace finds a cached place with exactly this name (case-insensitive)
+func (s *TripImportService) matchPlace(ctx context.Context, name string) *models.Place {
+	places, err := s.placeRepo.SearchByName(ctx, "^"+regexp.QuoteMeta(name)+"$", 0, 1)
+	if err != nil || len(places) == 0 {
+		return nil
+	}
+	return places[0]
+}
+
+// shiftDays renumbers existing days so that imported rows dated before the trip start fit in front
+func (s *TripImportService) shiftDays(ctx context.Context, itineraries []*models.Itinerary, shift int) error {
+	for _, itinerary := range renumberDays(itineraries, shift) {
+		if err := s.itineraryRepo.Update(ctx, itinerary); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// renumberDays moves scheduled days shift positions later and returns them
+// Their dates stay put: the trip start moves back by the same shift, so day N+shift falls on
+// the calendar date day N had.
+func renumberDays(itineraries []*models.Itinerary, shift int) []*models.Itinerary {
+	renumbered := make([]*models.Itinerary, 0, len(itineraries))
+	for _, itinerary := range itineraries {
+		if itinerary.Unscheduled {
+			continue
+		}
+		itinerary.DayNumber += shift
+		itinerary.Order += shift
+		renumbered = append(renumbered, itinerary)
+	}
+	return renumbered
+}
+
+// hasDuplicateEntry reports whether the day already has an entry with the same title and start time
+// so importing the same file twice doesn't duplicate the plan
+func hasDuplicateEntry(itinerary *models.Itinerary, row *importRow) bool {
+	for _, entry := range itinerary.Entries {
+		if !strings.EqualFold(entry.Title, row.title) {
+			continue
+		}
+		if clockValue(entry.StartTime) == clockValue(row.startTime) {
+			return true
+		}
+	}
+	return false
+}
+
+func clockValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+// resolveRowDays converts row dates into day numbers relative to the trip start
+func resolveRowDays(rows []*importRow, tripStart time.Time) {
+	for _, row := range rows {
+		if row.date.IsZero() || row.parseError != nil {
+			continue
+		}
+		row.dayNumber = int(dateOnly(row.date).Sub(tripStart).Hours()/24) + 1
+	}
+}
+
+func minRowDay(rows []*importRow) int {
+	minDay := 1
+	for _, row := range rows {
+		if row.parseError != nil || row.skipReason != "" || row.date.IsZero() {
+			continue
+		}
+		if row.dayNumber < minDay && row.dayNumber > 1-maxImportDays {
+			minDay = row.dayNumber
+		}
+	}
+	return minDay
+}
+
+func earliestRowDate(rows []*importRow) time.Time {
+	var earliest time.Time
+	for _, row := range rows {
+		if row.date.IsZero() {
+			continue
+		}
+		if earliest.IsZero() || row.date.Before(earliest) {
+			earliest = dateOnly(row.date)
+		}
+	}
+	if earliest.IsZero() {
+		return dateOnly(time.Now())
+	}
+	return earliest
+}
+
+func dateOnly(t time.Time) time.Time {
+	if t.IsZero() {
+		return t
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// ==================== ICS ====================
+
+// parseICSRows turns each VEVENT into a row, all-day events describe days rather than entries and are skipped
+// Zoned times are converted to location, or the calendar default zone, floating times are kept as is
+func parseICSRows(data []byte, location *time.Location) ([]*importRow, error) {
+	calendar, err := ical.Parse(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("invalid import file: %w", err)
+	}
+
+	if location == nil && calendar.TimeZone != "" {
+		location, _ = time.LoadLocation(calendar.TimeZone)
+	}
+
+	rows := make([]*importRow, 0, len(calendar.Events))
+	for i, event := range calendar.Events {
+		if location != nil && !event.Floating && !event.AllDay {
+			event.Start = event.Start.In(location)
+			event.End = event.End.In(location)
+		}
+
+		row := &importRow{
+			row:       i + 1,
+			entryType: models.EntryTypeNote,
+			title:     strings.TrimSpace(event.Summary),
+			notes:     strings.TrimSpace(event.Description),
+			placeName: strings.TrimSpace(event.Location),
+			date:      event.Start,
+		}
+
+		switch {
+		case event.ParseError != nil:
+			row.parseError = event.ParseError
+		case event.Start.IsZero():
+			row.parseError = errors.New("event has no start date")
+		case event.AllDay:
+			row.skipReason = "all-day events are not imported"
+		case row.title == "":
+			row.skipReason = "missing title"
+		default:
+			start := models.FormatClock(event.Start.Hour()*60 + event.Start.Minute())
+			row.startTime = &start
+			if event.End.After(event.Start) {
+				end := models.FormatClock(event.End.Hour()*60 + event.End.Minute())
+				row.endTime = &end
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// ==================== CSV ====================
+
+// csvColumns maps normalized header names to columns: day, time, title, place name, notes
+var csvColumns = map[string]string{
+	"day":       "day",
+	"date":      "day",
+	"time":      "time",
+	"title":     "title",
+	"name":      "title",
+	"placename": "place",
+	"place":     "place",
+	"location":  "place",
+	"notes":     "notes",
+	"note":      "notes",
+}
+
+var csvHeaderNormalizer = strings.NewReplacer(" ", "", "_", "", "-", "")
+
+// parseCSVRows reads rows with columns day, time, title, place name, notes
+// The header row is optional, day is a day number or a YYYY-MM-DD date, time is "HH:MM" or "HH:MM-HH:MM"
+func parseCSVRows(data []byte) ([]*importRow, error) {
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("invalid import file: %w", err)
+	}
+
+	columns := map[string]int{"day": 0, "time": 1, "title": 2, "place": 3, "notes": 4}
+	first := 0
+	if len(records) > 0 && isCSVHeader(records[0]) {
+		columns = map[string]int{}
+		for i, name := range records[0] {
+			if column, ok := csvColumns[csvHeaderNormalizer.Replace(strings.ToLower(strings.TrimSpace(name)))]; ok {
+				if _, exists := columns[column]; !exists {
+					columns[column] = i
+				}
+			}
+		}
+		first = 1
+	}
+
+	field := func(record []string, column string) string {
+		index, ok := columns[column]
+		if !ok || index >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[index])
+	}
+
+	rows := make([]*importRow, 0, len(records))
+	for i := first; i < len(records); i++ {
+		record := records[i]
+		row := &importRow{
+			row:       i + 1,
+			entryType: models.EntryTypeNote,
+			title:     field(record, "title"),
+			placeName: field(record, "place"),
+			notes:     field(record, "notes"),
+		}
+		if row.title == "" {
+			row.title = row.placeName
+		}
+
+		day := field(record, "day")
+		switch {
+		case strings.Join(record, "") == "":
+			row.skipReason = "empty row"
+		case row.title == "":
+			row.skipReason = "missing title"
+		case day == "":
+			row.parseError = errors.New("missing day")
+		default:
+			if number, err := strconv.Atoi(day); err == nil {
+				row.dayNumber = number
+			} else if date, err := time.Parse("2006-01-02", day); err == nil {
+				row.date = date
+			} else {
+				row.parseError = fmt.Errorf("invalid day %q, expected a day number or YYYY-MM-DD", day)
+			}
+		}
+
+		if row.parseError == nil && row.skipReason == "" {
+			row.startTime, row.endTime, row.parseError = parseTimeRange(field(record, "time"))
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func isCSVHeader(record []string) bool {
+	for _, name := range record {
+		if csvColumns[csvHeaderNormalizer.Replace(strings.ToLower(strings.TrimSpace(name)))] == "title" {
+			return true
+		}
+	}
+	return false
+}
+
+// parseTimeRange parses "", "HH:MM" or "HH:MM-HH:MM" into normalized start and end times
+func parseTimeRange(value string) (*string, *string, error) {
+	if value == "" {
+		return nil, nil, nil
+	}
+
+	parts := strings.SplitN(value, "-", 2)
+	start, err := models.ParseClock(strings.TrimSpace(parts[0]))
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid time %q, expected HH:MM or HH:MM-HH:MM", value)
+	}
+	startTime := models.FormatClock(start)
+
+	if len(parts) == 1 {
+		return &startTime, nil, nil
+	}
+	end, err := models.ParseClock(strings.TrimSpace(parts[1]))
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid time %q, expected HH:MM or HH:MM-HH:MM", value)
+	}
+	endTime := models.FormatClock(end)
+	return &startTime, &endTime, nil
+}
+
+// ==================== JSON ====================
+
+// parseJSONRows reads our own trip export, either the bare trip or the {"status", "data"} API envelope
+// Days keep their day number so a plan can be imported into a trip with different dates.
+func parseJSONRows(data []byte) ([]*importRow, error) {
+	var envelope struct {
+		Data *schemas.TripDetailResponse `json:"data"`
+	}
+	trip := &schemas.TripDetailResponse{}
+	if err := json.Unmarshal(data, &envelope); err == nil && envelope.Data != nil {
+		trip = envelope.Data
+	} else if err := json.Unmarshal(data, trip); err != nil {
+		return nil, fmt.Errorf("invalid import file: %w", err)
+	}
+
+	itineraries := trip.Itineraries
+	sort.SliceStable(itineraries, func(i, j int) bool {
+		return itineraries[i].DayNumber < itineraries[j].DayNumber
+	})
+
+	rows := []*importRow{}
+	for _, itinerary := range itineraries {
+		entries := itinerary.Entries
+		sort.SliceStable(entries, func(i, j int) bool {
+			return entries[i].Order < entries[j].Order
+		})
+
+		for _, entry := range entries {
+			row := &importRow{
+				row:       len(rows) + 1,
+				dayNumber: itinerary.DayNumber,
+				entryType: models.EntryType(entry.Type),
+				title:     strings.TrimSpace(entry.Title),
+				startTime: entry.StartTime,
+				endTime:   entry.EndTime,
+				place:     placeDataFromResponse(entry.Place),
+			}
+			if entry.Description != nil {
+				row.notes = *entry.Description
+			}
+			if row.place == nil && entry.Place != nil && entry.Place.Name != nil {
+				row.placeName = *entry.Place.Name
+			}
+			for _, todo := range entry.Todos {
+				row.todos = append(row.todos, models.Todo{Title: todo.Title, Completed: todo.Completed, Order: todo.Order})
+			}
+
+			switch row.entryType {
+			case models.EntryTypePlace, models.EntryTypeNote, models.EntryTypeTodos:
+			default:
+				row.parseError = fmt.Errorf("invalid entry type %q", entry.Type)
+			}
+			if row.title == "" {
+				row.skipReason = "missing title"
+			}
+			if row.parseError == nil && row.skipReason == "" {
+				row.parseError = validateClock(row.startTime, row.endTime)
+			}
+			rows = append(rows, row)
+		}
+	}
+	return rows, nil
+}
+
+// placeDataFromResponse rebuilds the place payload accepted by ItineraryService.CreateEntry
+func placeDataFromResponse(place *schemas.PlaceResponse) *schemas.PlaceData {
+	if place == nil || place.GooglePlaceID == nil || *place.GooglePlaceID == "" {
+		return nil
+	}
+
+	data := &schemas.PlaceData{
+		PlaceID: *place.GooglePlaceID,
+		Types:   place.Categories,
+	}
+	if place.Name != nil {
+		data.Name = *place.Name
+	}
+
+	// Location is exported as the stored GeoPoint
+	if raw, err := json.Marshal(place.Location); err == nil {
+		var point models.GeoPoint
+		if json.Unmarshal(raw, &point) == nil {
+			if lat, lng, ok := point.LatLng(); ok {
+				data.Location = schemas.Coordinates{Lat: lat, Lng: lng}
+			}
+		}
+	}
+	return data
+}
+
+func validateClock(values ...*string) error {
+	for _, value := range values {
+		if value == nil || *value == "" {
+			continue
+		}
+		if _, err := models.ParseClock(*value); err != nil {
+			return fmt.Errorf("invalid time %q, expected HH:MM", *value)
+		}
+	}
+	return nil
+}
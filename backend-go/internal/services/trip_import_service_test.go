@@ -0,0 +1,169 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"backend-go/internal/models"
+)
+
+// Rows dated before the trip start move the start back and renumber existing days,
+// the days must keep the calendar dates they had
+func TestRenumberDaysKeepsDates(t *testing.T) {
+	tripStart := time.Date(2026, 6, 10, 0, 0, 0, 0, time.UTC)
+	itineraries := []*models.Itinerary{
+		{DayNumber: 1, Order: 1, Date: "2026-06-10"},
+		{DayNumber: 2, Order: 2}, // no stored date, derived from the trip start
+		{Unscheduled: true},
+	}
+	rows := []*importRow{
+		{row: 1, date: time.Date(2026, 6, 8, 9, 0, 0, 0, time.UTC)},
+		{row: 2, dayNumber: 2},
+	}
+
+	resolveRowDays(rows, tripStart)
+	shift := 1 - minRowDay(rows)
+	if shift != 2 {
+		t.Fatalf("shift = %d, want 2", shift)
+	}
+
+	renumbered := renumberDays(itineraries, shift)
+	newStart := tripStart.AddDate(0, 0, -shift)
+
+	if len(renumbered) != 2 {
+		t.Fatalf("renumbered %d days, want the 2 scheduled ones", len(renumbered))
+	}
+	if itineraries[2].DayNumber != 0 || itineraries[2].Order != 0 {
+		t.Errorf("unscheduled bucket was renumbered: %+v", itineraries[2])
+	}
+
+	dated := itineraries[0]
+	if dated.DayNumber != 3 || dated.Order != 3 {
+		t.Errorf("dated day = day %d order %d, want 3 and 3", dated.DayNumber, dated.Order)
+	}
+	if dated.Date != "2026-06-10" {
+		t.Errorf("dated day date = %s, want it unchanged", dated.Date)
+	}
+	if got := newStart.AddDate(0, 0, dated.DayNumber-1).Format("2006-01-02"); got != dated.Date {
+		t.Errorf("day %d falls on %s after the start moved, stored date is %s", dated.DayNumber, got, dated.Date)
+	}
+
+	undated := itineraries[1]
+	if undated.DayNumber != 4 || undated.Date != "" {
+		t.Errorf("undated day = day %d date %q, want day 4 without a date", undated.DayNumber, undated.Date)
+	}
+	if got := newStart.AddDate(0, 0, undated.DayNumber-1); !got.Equal(tripStart.AddDate(0, 0, 1)) {
+		t.Errorf("undated day now falls on %s, want %s", got, tripStart.AddDate(0, 0, 1))
+	}
+}
+
+func TestParseCSVRows(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		check func(t *testing.T, rows []*importRow)
+	}{
+		{
+			name:  "header without optional columns",
+			input: "Title,Date\nLouvre,2026-06-10\n",
+			check: func(t *testing.T, rows []*importRow) {
+				row := rows[0]
+				if row.parseError != nil || row.title != "Louvre" || !row.date.Equal(time.Date(2026, 6, 10, 0, 0, 0, 0, time.UTC)) {
+					t.Errorf("row = %+v", row)
+				}
+				if row.startTime != nil || row.placeName != "" || row.notes != "" {
+					t.Errorf("missing columns were filled: %+v", row)
+				}
+			},
+		},
+		{
+			name:  "header without day column",
+			input: "title,notes\nLouvre,Book ahead\n",
+			check: func(t *testing.T, rows []*importRow) {
+				if err := rows[0].parseError; err == nil || err.Error() != "missing day" {
+					t.Errorf("parseError = %v, want missing day", err)
+				}
+			},
+		},
+		{
+			name:  "short record without header",
+			input: "2,09:00\n3,10:00,Museum\n",
+			check: func(t *testing.T, rows []*importRow) {
+				if rows[0].skipReason != "missing title" {
+					t.Errorf("row 1 skipReason = %q, want missing title", rows[0].skipReason)
+				}
+				if row := rows[1]; row.parseError != nil || row.dayNumber != 3 || row.title != "Museum" || *row.startTime != "10:00" {
+					t.Errorf("row 2 = %+v", row)
+				}
+			},
+		},
+		{
+			name:  "bad dates and times",
+			input: "day,time,title\n2026-13-40,,Nowhere\nmonday,,Somewhere\n1,25:00,Late\n1,09:00-noon,Lunch\n",
+			check: func(t *testing.T, rows []*importRow) {
+				for _, row := range rows {
+					if row.parseError == nil {
+						t.Errorf("row %d (%s) was accepted", row.row, row.title)
+					}
+				}
+				if err := rows[0].parseError; err != nil && !strings.Contains(err.Error(), `invalid day "2026-13-40"`) {
+					t.Errorf("row 2 parseError = %v", err)
+				}
+			},
+		},
+		{
+			name:  "empty row",
+			input: "1,,Walk\n,,,\n",
+			check: func(t *testing.T, rows []*importRow) {
+				if rows[1].skipReason != "empty row" {
+					t.Errorf("skipReason = %q, want empty row", rows[1].skipReason)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := parseCSVRows([]byte(tt.input))
+			if err != nil {
+				t.Fatal(err)
+			}
+			tt.check(t, rows)
+		})
+	}
+}
+
+func TestParseICSRowsConvertsToTripZone(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Skip("tz database not available:", err)
+	}
+
+	data := []byte(strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"BEGIN:VEVENT",
+		"SUMMARY:Flight",
+		"DTSTART:20260609T230000Z",
+		"DTEND:20260610T010000Z",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"SUMMARY:Dinner",
+		"DTSTART:20260610T190000",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n"))
+
+	rows, err := parseICSRows(data, tokyo)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	flight := rows[0]
+	if flight.date.Day() != 10 || *flight.startTime != "08:00" || *flight.endTime != "10:00" {
+		t.Errorf("flight = %s %v-%v, want 2026-06-10 08:00-10:00", flight.date, *flight.startTime, *flight.endTime)
+	}
+	if dinner := rows[1]; *dinner.startTime != "19:00" {
+		t.Errorf("floating dinner moved to %s", *dinner.startTime)
+	}
+}
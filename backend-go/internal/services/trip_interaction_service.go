@@ -0,0 +1,404 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"backend-go/internal/models"
+	"backend-go/internal/repository"
+	"backend-go/internal/schemas"
+	"backend-go/pkg/utils"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TripInteractionService handles reactions, bookmarks, views and shares on trips
+// Interaction records are the source of truth, trip counters are cached copies kept in step with $inc-style updates
+type TripInteractionService struct {
+	tripRepo        *repository.TripRepository
+	interactionRepo *repository.InteractionRepository
+	tracer          trace.Tracer
+}
+
+func NewTripInteractionService() *TripInteractionService {
+	return &TripInteractionService{
+		tripRepo:        repository.NewTripRepository(),
+		interactionRepo: repository.NewInteractionRepository(),
+		tracer:          otel.Tracer("trip-interaction-service"),
+	}
+}
+
+// React sets the user's reaction on a trip, replacing any other reaction they had
+func (s *TripInteractionService) React(ctx context.Context, tripID, userID string, reaction models.InteractionAction) (*schemas.TripInteractionResponse, error) {
+	ctx, span := s.tracer.Start(ctx, "TripInteractionService.React")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	logger.Input(map[string]interface{}{
+		"tripID":   tripID,
+		"userID":   userID,
+		"reaction": reaction,
+	})
+
+	trip, err := s.findViewableTrip(ctx, tripID, userID)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	interaction, err := s.interactionRepo.NewInteraction(userID, tripID, models.InteractionTargetTrip, reaction)
+	if err != nil {
+		err := errors.New("invalid IDs")
+		logger.Error(err)
+		return nil, err
+	}
+	if !interaction.IsReaction() {
+		err := errors.New("invalid reaction")
+		logger.Error(err)
+		return nil, err
+	}
+
+	// A user has one reaction per trip, switching from like to love must not count twice
+	removed, err := s.interactionRepo.DeleteReactions(ctx, userID, tripID, models.InteractionTargetTrip, reaction)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	inserted, err := s.interactionRepo.UpsertUnique(ctx, interaction)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	delta := -int(removed)
+	if inserted {
+		delta++
+	}
+
+	response, err := s.applyCounters(ctx, trip, userID, map[string]int{repository.TripCounterReactions: delta})
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	logger.Output(response)
+	return response, nil
+}
+
+// Unreact removes the user's reaction from a trip, also allowed on trips the user can no longer view
+func (s *TripInteractionService) Unreact(ctx context.Context, tripID, userID string) (*schemas.TripInteractionResponse, error) {
+	ctx, span := s.tracer.Start(ctx, "TripInteractionService.Unreact")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	logger.Input(map[string]interface{}{
+		"tripID": tripID,
+		"userID": userID,
+	})
+
+	trip, err := s.tripRepo.FindByID(ctx, tripID)
+	if err != nil {
+		err := errors.New("trip not found")
+		logger.Error(err)
+		return nil, err
+	}
+
+	removed, err := s.interactionRepo.DeleteReactions(ctx, userID, tripID, models.InteractionTargetTrip, "")
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	response, err := s.applyCounters(ctx, trip, userID, map[string]int{repository.TripCounterReactions: -int(removed)})
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	logger.Output(response)
+	return response, nil
+}
+
+// Bookmark saves a trip to the user's bookmarks, bookmarking twice is a no-op
+func (s *TripInteractionService) Bookmark(ctx context.Context, tripID, userID string) (*schemas.TripInteractionResponse, error) {
+	ctx, span := s.tracer.Start(ctx, "TripInteractionService.Bookmark")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	logger.Input(map[string]interface{}{
+		"tripID": tripID,
+		"userID": userID,
+	})
+
+	trip, err := s.findViewableTrip(ctx, tripID, userID)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	interaction, err := s.interactionRepo.NewInteraction(userID, tripID, models.InteractionTargetTrip, models.ActionBookmark)
+	if err != nil {
+		err := errors.New("invalid IDs")
+		logger.Error(err)
+		return nil, err
+	}
+
+	inserted, err := s.interactionRepo.UpsertUnique(ctx, interaction)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	delta := 0
+	if inserted {
+		delta = 1
+	}
+
+	response, err := s.applyCounters(ctx, trip, userID, map[string]int{repository.TripCounterBookmarks: delta})
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	logger.Output(response)
+	return response, nil
+}
+
+// Unbookmark removes a trip from the user's bookmarks, also allowed on trips the user can no longer view
+func (s *TripInteractionService) Unbookmark(ctx context.Context, tripID, userID string) (*schemas.TripInteractionResponse, error) {
+	ctx, span := s.tracer.Start(ctx, "TripInteractionService.Unbookmark")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	logger.Input(map[string]interface{}{
+		"tripID": tripID,
+		"userID": userID,
+	})
+
+	trip, err := s.tripRepo.FindByID(ctx, tripID)
+	if err != nil {
+		err := errors.New("trip not found")
+		logger.Error(err)
+		return nil, err
+	}
+
+	removed, err := s.interactionRepo.DeleteByUserAndTarget(ctx, userID, tripID, models.InteractionTargetTrip, models.ActionBookmark)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	delta := 0
+	if removed {
+		delta = -1
+	}
+
+	response, err := s.applyCounters(ctx, trip, userID, map[string]int{repository.TripCounterBookmarks: delta})
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	logger.Output(response)
+	return response, nil
+}
+
+// RecordView counts a trip view, userID is empty for anonymous viewers
+func (s *TripInteractionService) RecordView(ctx context.Context, tripID, userID string) (*schemas.TripInteractionResponse, error) {
+	ctx, span := s.tracer.Start(ctx, "TripInteractionService.RecordView")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	logger.Input(map[string]interface{}{
+		"tripID": tripID,
+		"userID": userID,
+	})
+
+	response, err := s.recordAction(ctx, tripID, userID, models.ActionView, repository.TripCounterViews)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	logger.Output(response)
+	return response, nil
+}
+
+// RecordShare counts a trip share, userID is empty for anonymous viewers
+func (s *TripInteractionService) RecordShare(ctx context.Context, tripID, userID string) (*schemas.TripInteractionResponse, error) {
+	ctx, span := s.tracer.Start(ctx, "TripInteractionService.RecordShare")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	logger.Input(map[string]interface{}{
+		"tripID": tripID,
+		"userID": userID,
+	})
+
+	response, err := s.recordAction(ctx, tripID, userID, models.ActionShare, repository.TripCounterShares)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	logger.Output(response)
+	return response, nil
+}
+
+// ListBookmarkedTrips lists the user's bookmarked trips, most recently bookmarked first
+// Bookmarked trips that became private or were deleted are left out
+func (s *TripInteractionService) ListBookmarkedTrips(ctx context.Context, userID string, query *schemas.ListBookmarkedTripsQuery) ([]*models.Trip, error) {
+	ctx, span := s.tracer.Start(ctx, "TripInteractionService.ListBookmarkedTrips")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	logger.Input(map[string]interface{}{
+		"userID": userID,
+		"limit":  query.Limit,
+		"offset": query.Offset,
+	})
+
+	limit := int64(query.Limit)
+	if limit == 0 {
+		limit = 20
+	}
+
+	ids, err := s.interactionRepo.FindTargetIDsByUser(ctx, userID, models.InteractionTargetTrip, models.ActionBookmark, int64(query.Offset), limit)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+	if len(ids) == 0 {
+		logger.Output(map[string]interface{}{"count": 0})
+		return []*models.Trip{}, nil
+	}
+
+	trips, err := s.tripRepo.Find(ctx, &repository.TripFilter{
+		IDs:      ids,
+		ViewerID: &userID,
+		Limit:    int64(len(ids)),
+	})
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	// Keep bookmark order rather than trip creation order
+	byID := make(map[primitive.ObjectID]*models.Trip, len(trips))
+	for _, trip := range trips {
+		byID[trip.ID] = trip
+	}
+	ordered := make([]*models.Trip, 0, len(trips))
+	for _, id := range ids {
+		if trip, ok := byID[id]; ok {
+			ordered = append(ordered, trip)
+		}
+	}
+
+	logger.Output(map[string]interface{}{
+		"count": len(ordered),
+	})
+	return ordered, nil
+}
+
+// GetViewerState returns the user's reaction and bookmark on a trip
+func (s *TripInteractionService) GetViewerState(ctx context.Context, tripID, userID string) (*schemas.TripViewerState, error) {
+	ctx, span := s.tracer.Start(ctx, "TripInteractionService.GetViewerState")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	logger.Input(map[string]interface{}{
+		"tripID": tripID,
+		"userID": userID,
+	})
+
+	actions, err := s.interactionRepo.FindUniqueActions(ctx, userID, tripID, models.InteractionTargetTrip)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	state := &schemas.TripViewerState{}
+	for _, action := range actions {
+		switch action {
+		case models.ActionBookmark:
+			state.Bookmarked = true
+		case models.ActionLike, models.ActionLove, models.ActionAngry:
+			reaction := string(action)
+			state.Reaction = &reaction
+		}
+	}
+
+	logger.Output(state)
+	return state, nil
+}
+
+// recordAction stores a non-unique interaction and bumps its counter
+func (s *TripInteractionService) recordAction(ctx context.Context, tripID, userID string, action models.InteractionAction, counter string) (*schemas.TripInteractionResponse, error) {
+	trip, err := s.findViewableTrip(ctx, tripID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Anonymous actions are stored under the nil user ID so counters can be recomputed from interactions
+	actorID := userID
+	if actorID == "" {
+		actorID = primitive.NilObjectID.Hex()
+	}
+
+	interaction, err := s.interactionRepo.NewInteraction(actorID, tripID, models.InteractionTargetTrip, action)
+	if err != nil {
+		return nil, errors.New("invalid IDs")
+	}
+	if err := s.interactionRepo.Create(ctx, interaction); err != nil {
+		return nil, err
+	}
+
+	return s.applyCounters(ctx, trip, userID, map[string]int{counter: 1})
+}
+
+// findViewableTrip loads a trip the user may open, hidden trips are reported as not found
+func (s *TripInteractionService) findViewableTrip(ctx context.Context, tripID, userID string) (*models.Trip, error) {
+	trip, err := s.tripRepo.FindByID(ctx, tripID)
+	if err != nil || !s.tripRepo.CanView(trip, userID) {
+		return nil, errors.New("trip not found")
+	}
+	return trip, nil
+}
+
+// applyCounters updates the cached counters (skipped when nothing changed) and builds the response
+func (s *TripInteractionService) applyCounters(ctx context.Context, trip *models.Trip, userID string, deltas map[string]int) (*schemas.TripInteractionResponse, error) {
+	for field, delta := range deltas {
+		if delta == 0 {
+			delete(deltas, field)
+		}
+	}
+
+	if len(deltas) > 0 {
+		updated, err := s.tripRepo.IncrementCounters(ctx, trip.ID, deltas)
+		if err != nil {
+			return nil, err
+		}
+		trip = updated
+	}
+
+	response := &schemas.TripInteractionResponse{
+		ViewCount:      trip.ViewCount,
+		ReactionsCount: trip.ReactionsCount,
+		BookmarkCount:  trip.BookmarkCount,
+		ShareCount:     trip.ShareCount,
+	}
+
+	if userID != "" {
+		state, err := s.GetViewerState(ctx, trip.ID.Hex(), userID)
+		if err != nil {
+			return nil, err
+		}
+		response.ViewerState = state
+	}
+
+	return response, nil
+}
@@ -0,0 +1,508 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"backend-go/internal/models"
+	"backend-go/internal/repository"
+	"backend-go/internal/schemas"
+	"backend-go/pkg/sse"
+	"backend-go/pkg/utils"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// defaultInviteLinkTTL is used when the owner doesn't set an expiry for a shareable link
+const defaultInviteLinkTTL = 72 * time.Hour
+
+type TripInvitationService struct {
+	invitationRepo *repository.TripInvitationRepository
+	tripRepo       *repository.TripRepository
+	userRepo       *repository.UserRepository
+	tripHub        *sse.TripHub
+	tracer         trace.Tracer
+}
+
+func NewTripInvitationService(tripHub *sse.TripHub) *TripInvitationService {
+	return &TripInvitationService{
+		invitationRepo: repository.NewTripInvitationRepository(),
+		tripRepo:       repository.NewTripRepository(),
+		userRepo:       repository.NewUserRepository(),
+		tripHub:        tripHub,
+		tracer:         otel.Tracer("trip-invitation-service"),
+	}
+}
+
+// InviteUser creates a pending invitation for a user, the user becomes a member only after accepting
+func (s *TripInvitationService) InviteUser(ctx context.Context, tripID, userID string, req *schemas.CreateTripInvitationRequest) (*models.TripInvitation, error) {
+	ctx, span := s.tracer.Start(ctx, "TripInvitationService.InviteUser")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	logger.Input(map[string]interface{}{
+		"tripID":    tripID,
+		"userID":    userID,
+		"inviteeID": req.UserID,
+		"role":      req.Role,
+	})
+
+	trip, err := s.tripRepo.FindByID(ctx, tripID)
+	if err != nil {
+		err := errors.New("trip not found")
+		logger.Error(err)
+		return nil, err
+	}
+
+	// Only owner can invite members
+	if !s.tripRepo.IsOwner(trip, userID) {
+		err := errors.New("unauthorized: only owner can invite members")
+		logger.Error(err)
+		return nil, err
+	}
+
+	if _, err := s.userRepo.FindByID(ctx, req.UserID); err != nil {
+		err := errors.New("user not found")
+		logger.Error(err)
+		return nil, err
+	}
+
+	if s.tripRepo.IsMemberExists(trip, req.UserID) {
+		err := errors.New("user is already a member")
+		logger.Error(err)
+		return nil, err
+	}
+
+	exists, err := s.invitationRepo.ExistsPendingForInvitee(ctx, tripID, req.UserID)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+	if exists {
+		err := errors.New("user already has a pending invitation")
+		logger.Error(err)
+		return nil, err
+	}
+
+	invitation, err := s.invitationRepo.NewInvitation(tripID, userID)
+	if err != nil {
+		err := errors.New("invalid IDs")
+		logger.Error(err)
+		return nil, err
+	}
+	if err := s.invitationRepo.SetInviteeID(invitation, req.UserID); err != nil {
+		err := errors.New("invalid user ID")
+		logger.Error(err)
+		return nil, err
+	}
+	invitation.Type = models.InvitationTypeUser
+	invitation.Role = req.Role
+
+	if err := s.invitationRepo.Create(ctx, invitation); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	logger.Output(map[string]interface{}{
+		"invitationID": invitation.ID.Hex(),
+	})
+	return invitation, nil
+}
+
+// CreateInviteLink creates a shareable expiring token that lets anyone join with a preset role
+func (s *TripInvitationService) CreateInviteLink(ctx context.Context, tripID, userID string, req *schemas.CreateInviteLinkRequest) (*models.TripInvitation, error) {
+	ctx, span := s.tracer.Start(ctx, "TripInvitationService.CreateInviteLink")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	logger.Input(map[string]interface{}{
+		"tripID":         tripID,
+		"userID":         userID,
+		"role":           req.Role,
+		"expiresInHours": req.ExpiresInHours,
+	})
+
+	trip, err := s.tripRepo.FindByID(ctx, tripID)
+	if err != nil {
+		err := errors.New("trip not found")
+		logger.Error(err)
+		return nil, err
+	}
+
+	if !s.tripRepo.IsOwner(trip, userID) {
+		err := errors.New("unauthorized: only owner can invite members")
+		logger.Error(err)
+		return nil, err
+	}
+
+	token, err := generateInviteToken()
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	ttl := defaultInviteLinkTTL
+	if req.ExpiresInHours > 0 {
+		ttl = time.Duration(req.ExpiresInHours) * time.Hour
+	}
+	expiresAt := time.Now().Add(ttl)
+
+	invitation, err := s.invitationRepo.NewInvitation(tripID, userID)
+	if err != nil {
+		err := errors.New("invalid IDs")
+		logger.Error(err)
+		return nil, err
+	}
+	invitation.Type = models.InvitationTypeLink
+	invitation.Role = req.Role
+	invitation.Token = token
+	invitation.ExpiresAt = &expiresAt
+	invitation.MaxUses = req.MaxUses
+
+	if err := s.invitationRepo.Create(ctx, invitation); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	logger.Output(map[string]interface{}{
+		"invitationID": invitation.ID.Hex(),
+		"expiresAt":    expiresAt,
+	})
+	return invitation, nil
+}
+
+// GetTripInvitations gets pending invitations and active links for a trip (owner only)
+func (s *TripInvitationService) GetTripInvitations(ctx context.Context, tripID, userID string) ([]*models.TripInvitation, error) {
+	ctx, span := s.tracer.Start(ctx, "TripInvitationService.GetTripInvitations")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	logger.Input(map[string]interface{}{
+		"tripID": tripID,
+		"userID": userID,
+	})
+
+	trip, err := s.tripRepo.FindByID(ctx, tripID)
+	if err != nil {
+		err := errors.New("trip not found")
+		logger.Error(err)
+		return nil, err
+	}
+
+	if !s.tripRepo.IsOwner(trip, userID) {
+		err := errors.New("unauthorized: only owner can view invitations")
+		logger.Error(err)
+		return nil, err
+	}
+
+	invitations, err := s.invitationRepo.FindPendingByTripID(ctx, tripID)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	logger.Output(map[string]interface{}{
+		"count": len(invitations),
+	})
+	return invitations, nil
+}
+
+// RevokeInvitation revokes a pending invitation or link (owner only)
+func (s *TripInvitationService) RevokeInvitation(ctx context.Context, tripID, invitationID, userID string) error {
+	ctx, span := s.tracer.Start(ctx, "TripInvitationService.RevokeInvitation")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	logger.Input(map[string]interface{}{
+		"tripID":       tripID,
+		"invitationID": invitationID,
+		"userID":       userID,
+	})
+
+	trip, err := s.tripRepo.FindByID(ctx, tripID)
+	if err != nil {
+		err := errors.New("trip not found")
+		logger.Error(err)
+		return err
+	}
+
+	if !s.tripRepo.IsOwner(trip, userID) {
+		err := errors.New("unauthorized: only owner can revoke invitations")
+		logger.Error(err)
+		return err
+	}
+
+	invitation, err := s.invitationRepo.FindByID(ctx, invitationID)
+	if err != nil || invitation.TripID != trip.ID {
+		err := errors.New("invitation not found")
+		logger.Error(err)
+		return err
+	}
+
+	if invitation.Status != models.InvitationStatusPending {
+		err := errors.New("invitation is no longer pending")
+		logger.Error(err)
+		return err
+	}
+
+	now := time.Now()
+	invitation.Status = models.InvitationStatusRevoked
+	invitation.RespondedAt = &now
+	if err := s.invitationRepo.Update(ctx, invitation); err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	logger.Info("Invitation revoked successfully")
+	return nil
+}
+
+// GetMyInvitations gets pending invitations addressed to the user, with trip and inviter info
+func (s *TripInvitationService) GetMyInvitations(ctx context.Context, userID string) ([]*models.TripInvitation, error) {
+	ctx, span := s.tracer.Start(ctx, "TripInvitationService.GetMyInvitations")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	logger.Input(map[string]interface{}{
+		"userID": userID,
+	})
+
+	invitations, err := s.invitationRepo.FindPendingByInvitee(ctx, userID)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	// Skip invitations whose trip has been deleted meanwhile
+	result := make([]*models.TripInvitation, 0, len(invitations))
+	for _, invitation := range invitations {
+		trip, err := s.tripRepo.FindByID(ctx, invitation.TripID.Hex())
+		if err != nil {
+			continue
+		}
+		s.populate(ctx, invitation, trip)
+		result = append(result, invitation)
+	}
+
+	logger.Output(map[string]interface{}{
+		"count": len(result),
+	})
+	return result, nil
+}
+
+// AcceptInvitation accepts a user invitation and adds the invitee to the trip
+func (s *TripInvitationService) AcceptInvitation(ctx context.Context, invitationID, userID string) (*models.TripInvitation, error) {
+	ctx, span := s.tracer.Start(ctx, "TripInvitationService.AcceptInvitation")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	logger.Input(map[string]interface{}{
+		"invitationID": invitationID,
+		"userID":       userID,
+	})
+
+	invitation, trip, err := s.findRespondable(ctx, invitationID, userID)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	// Someone already a member (joined by link meanwhile) just has the invitation marked accepted
+	if !s.tripRepo.IsMemberExists(trip, userID) {
+		if _, err := s.addMember(ctx, trip, userID, invitation.Role); err != nil {
+			logger.Error(err)
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	invitation.Status = models.InvitationStatusAccepted
+	invitation.RespondedAt = &now
+	invitation.UseCount++
+	if err := s.invitationRepo.Update(ctx, invitation); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	s.populate(ctx, invitation, trip)
+
+	logger.Output(map[string]interface{}{
+		"tripID": trip.ID.Hex(),
+		"role":   invitation.Role,
+	})
+	return invitation, nil
+}
+
+// DeclineInvitation declines a user invitation
+func (s *TripInvitationService) DeclineInvitation(ctx context.Context, invitationID, userID string) (*models.TripInvitation, error) {
+	ctx, span := s.tracer.Start(ctx, "TripInvitationService.DeclineInvitation")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	logger.Input(map[string]interface{}{
+		"invitationID": invitationID,
+		"userID":       userID,
+	})
+
+	invitation, trip, err := s.findRespondable(ctx, invitationID, userID)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	now := time.Now()
+	invitation.Status = models.InvitationStatusDeclined
+	invitation.RespondedAt = &now
+	if err := s.invitationRepo.Update(ctx, invitation); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	s.populate(ctx, invitation, trip)
+
+	logger.Output(map[string]interface{}{
+		"tripID": trip.ID.Hex(),
+	})
+	return invitation, nil
+}
+
+// JoinByToken adds the user to the trip using a shareable invite link
+func (s *TripInvitationService) JoinByToken(ctx context.Context, token, userID string) (*models.TripInvitation, error) {
+	ctx, span := s.tracer.Start(ctx, "TripInvitationService.JoinByToken")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	logger.Input(map[string]interface{}{
+		"userID": userID,
+	})
+
+	invitation, err := s.invitationRepo.FindByToken(ctx, token)
+	if err != nil {
+		err := errors.New("invitation not found")
+		logger.Error(err)
+		return nil, err
+	}
+
+	if !invitation.IsUsable() {
+		err := errors.New("invitation is no longer valid")
+		logger.Error(err)
+		return nil, err
+	}
+
+	trip, err := s.tripRepo.FindByID(ctx, invitation.TripID.Hex())
+	if err != nil {
+		err := errors.New("trip not found")
+		logger.Error(err)
+		return nil, err
+	}
+
+	if s.tripRepo.IsMemberExists(trip, userID) || s.tripRepo.IsOwner(trip, userID) {
+		err := errors.New("user is already a member")
+		logger.Error(err)
+		return nil, err
+	}
+
+	// Claim a use atomically so concurrent joins can't exceed max uses
+	claimed, err := s.invitationRepo.ClaimUse(ctx, invitation)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+	if !claimed {
+		err := errors.New("invitation is no longer valid")
+		logger.Error(err)
+		return nil, err
+	}
+
+	// The claimed use goes back when the user doesn't end up joining
+	added, err := s.addMember(ctx, trip, userID, invitation.Role)
+	if err == nil && !added {
+		err = errors.New("user is already a member")
+	}
+	if err != nil {
+		logger.Error(err)
+		if releaseErr := s.invitationRepo.ReleaseUse(ctx, invitation); releaseErr != nil {
+			logger.Error(releaseErr)
+		}
+		return nil, err
+	}
+
+	invitation.UseCount++
+	s.populate(ctx, invitation, trip)
+
+	logger.Output(map[string]interface{}{
+		"tripID": trip.ID.Hex(),
+		"role":   invitation.Role,
+	})
+	return invitation, nil
+}
+
+// findRespondable loads a pending user invitation addressed to userID together with its trip
+func (s *TripInvitationService) findRespondable(ctx context.Context, invitationID, userID string) (*models.TripInvitation, *models.Trip, error) {
+	invitation, err := s.invitationRepo.FindByID(ctx, invitationID)
+	if err != nil || !s.invitationRepo.IsInvitee(invitation, userID) {
+		return nil, nil, errors.New("invitation not found")
+	}
+
+	if !invitation.IsUsable() {
+		return nil, nil, errors.New("invitation is no longer valid")
+	}
+
+	trip, err := s.tripRepo.FindByID(ctx, invitation.TripID.Hex())
+	if err != nil {
+		return nil, nil, errors.New("trip not found")
+	}
+
+	return invitation, trip, nil
+}
+
+// addMember appends userID to the trip members with the given role
+// Returns false when the user was already a member.
+func (s *TripInvitationService) addMember(ctx context.Context, trip *models.Trip, userID, role string) (bool, error) {
+	member, err := s.tripRepo.NewTripMember(userID, role)
+	if err != nil {
+		return false, errors.New("invalid user ID")
+	}
+
+	added, err := s.tripRepo.AddMember(ctx, trip.ID, member)
+	if err != nil || !added {
+		return false, err
+	}
+
+	trip.TripMembers = append(trip.TripMembers, *member)
+	trip.Version++
+	publishTripEvent(s.tripHub, trip.ID, sse.TripEventMemberAdded, member)
+	return true, nil
+}
+
+// populate fills trip summary and inviter info for API responses
+func (s *TripInvitationService) populate(ctx context.Context, invitation *models.TripInvitation, trip *models.Trip) {
+	invitation.Trip = &models.InvitationTripInfo{
+		ID:         trip.ID,
+		Title:      trip.Title,
+		CoverPhoto: trip.CoverPhoto,
+		StartDate:  trip.StartDate,
+		EndDate:    trip.EndDate,
+	}
+
+	if inviter, err := s.userRepo.FindByID(ctx, invitation.InvitedBy.Hex()); err == nil && inviter != nil {
+		invitation.Inviter = &models.TripUserInfo{
+			ID:       inviter.ID,
+			Name:     inviter.Name,
+			PhotoURL: inviter.PhotoURL,
+		}
+	}
+}
+
+// generateInviteToken generates a random URL-safe token for invite links
+func generateInviteToken() (string, error) {
+	bytes := make([]byte, 24)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(bytes), nil
+}
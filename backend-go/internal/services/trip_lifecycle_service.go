@@ -0,0 +1,287 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"backend-go/internal/config"
+	"backend-go/internal/models"
+	"backend-go/internal/repository"
+	"backend-go/internal/schemas"
+	"backend-go/pkg/utils"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TripLifecycleService handles archiving, scheduled publishing and the background sweeper
+type TripLifecycleService struct {
+	tripRepo *repository.TripRepository
+	userRepo *repository.UserRepository
+	cfg      *config.LifecycleConfig
+	tracer   trace.Tracer
+}
+
+func NewTripLifecycleService(cfg *config.LifecycleConfig) *TripLifecycleService {
+	return &TripLifecycleService{
+		tripRepo: repository.NewTripRepository(),
+		userRepo: repository.NewUserRepository(),
+		cfg:      cfg,
+		tracer:   otel.Tracer("trip-lifecycle-service"),
+	}
+}
+
+// ArchiveTrip archives a trip, making its itinerary and expenses read-only
+func (s *TripLifecycleService) ArchiveTrip(ctx context.Context, tripID, userID string) (*models.Trip, error) {
+	ctx, span := s.tracer.Start(ctx, "TripLifecycleService.ArchiveTrip")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	logger.Input(map[string]interface{}{
+		"tripID": tripID,
+		"userID": userID,
+	})
+
+	trip, err := s.findOwnedTrip(ctx, tripID, userID)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	if trip.IsArchived() {
+		err := errors.New("trip is already archived")
+		logger.Error(err)
+		return nil, err
+	}
+
+	trip.Archive(time.Now())
+	if err := s.tripRepo.Update(ctx, trip); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	logger.Output(map[string]interface{}{
+		"status": trip.Status,
+	})
+	return trip, nil
+}
+
+// UnarchiveTrip restores an archived trip to its previous status
+// The sweeper won't archive it again, the owner can still archive it by hand
+func (s *TripLifecycleService) UnarchiveTrip(ctx context.Context, tripID, userID string) (*models.Trip, error) {
+	ctx, span := s.tracer.Start(ctx, "TripLifecycleService.UnarchiveTrip")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	logger.Input(map[string]interface{}{
+		"tripID": tripID,
+		"userID": userID,
+	})
+
+	trip, err := s.findOwnedTrip(ctx, tripID, userID)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	if !trip.IsArchived() {
+		err := errors.New("trip is not archived")
+		logger.Error(err)
+		return nil, err
+	}
+
+	trip.Unarchive(time.Now())
+	if err := s.tripRepo.Update(ctx, trip); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	logger.Output(map[string]interface{}{
+		"status": trip.Status,
+	})
+	return trip, nil
+}
+
+// PublishTrip publishes a draft now, or schedules it when publishAt is in the future
+func (s *TripLifecycleService) PublishTrip(ctx context.Context, tripID, userID string, req *schemas.PublishTripRequest) (*models.Trip, error) {
+	ctx, span := s.tracer.Start(ctx, "TripLifecycleService.PublishTrip")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	logger.Input(map[string]interface{}{
+		"tripID":    tripID,
+		"userID":    userID,
+		"publishAt": req.PublishAt,
+	})
+
+	trip, err := s.findOwnedTrip(ctx, tripID, userID)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	if trip.IsArchived() {
+		err := errors.New("trip is archived")
+		logger.Error(err)
+		return nil, err
+	}
+
+	if trip.Status == models.TripStatusPublished {
+		err := errors.New("trip is already published")
+		logger.Error(err)
+		return nil, err
+	}
+
+	if req.PublishAt != nil && req.PublishAt.After(time.Now()) {
+		publishAt := req.PublishAt.UTC()
+		trip.PublishAt = &publishAt
+	} else {
+		trip.Status = models.TripStatusPublished
+		trip.PublishAt = nil
+	}
+
+	if err := s.tripRepo.Update(ctx, trip); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	logger.Output(map[string]interface{}{
+		"status":    trip.Status,
+		"publishAt": trip.PublishAt,
+	})
+	return trip, nil
+}
+
+// CancelScheduledPublish keeps a scheduled draft as a draft
+func (s *TripLifecycleService) CancelScheduledPublish(ctx context.Context, tripID, userID string) (*models.Trip, error) {
+	ctx, span := s.tracer.Start(ctx, "TripLifecycleService.CancelScheduledPublish")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	logger.Input(map[string]interface{}{
+		"tripID": tripID,
+		"userID": userID,
+	})
+
+	trip, err := s.findOwnedTrip(ctx, tripID, userID)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	if trip.PublishAt == nil {
+		err := errors.New("trip has no scheduled publish")
+		logger.Error(err)
+		return nil, err
+	}
+
+	trip.PublishAt = nil
+	if err := s.tripRepo.Update(ctx, trip); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	logger.Output(map[string]interface{}{
+		"status": trip.Status,
+	})
+	return trip, nil
+}
+
+// Sweep publishes due scheduled drafts and archives trips that ended long enough ago
+// Owners with their own auto-archive setting are swept separately from everyone on the server default.
+func (s *TripLifecycleService) Sweep(ctx context.Context, now time.Time) (published, archived int64, err error) {
+	ctx, span := s.tracer.Start(ctx, "TripLifecycleService.Sweep")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	logger.Input(map[string]interface{}{
+		"now": now,
+	})
+
+	published, err = s.tripRepo.PublishScheduled(ctx, now)
+	if err != nil {
+		logger.Error(err)
+		return 0, 0, err
+	}
+
+	overrides, err := s.userRepo.FindAutoArchiveOverrides(ctx)
+	if err != nil {
+		logger.Error(err)
+		return published, 0, err
+	}
+
+	// Owners sharing the same delay are swept together
+	byDays := map[int][]primitive.ObjectID{}
+	overridden := make([]primitive.ObjectID, 0, len(overrides))
+	for ownerID, days := range overrides {
+		overridden = append(overridden, ownerID)
+		if days > 0 {
+			byDays[days] = append(byDays[days], ownerID)
+		}
+	}
+
+	for days, ownerIDs := range byDays {
+		count, err := s.tripRepo.ArchiveEndedBefore(ctx, now.AddDate(0, 0, -days), now, ownerIDs, false)
+		if err != nil {
+			logger.Error(err)
+			return published, archived, err
+		}
+		archived += count
+	}
+
+	if s.cfg.AutoArchiveAfterDays > 0 {
+		count, err := s.tripRepo.ArchiveEndedBefore(ctx, now.AddDate(0, 0, -s.cfg.AutoArchiveAfterDays), now, overridden, true)
+		if err != nil {
+			logger.Error(err)
+			return published, archived, err
+		}
+		archived += count
+	}
+
+	logger.Output(map[string]interface{}{
+		"published": published,
+		"archived":  archived,
+	})
+	return published, archived, nil
+}
+
+// RunSweeper sweeps on the configured interval until ctx is cancelled
+func (s *TripLifecycleService) RunSweeper(ctx context.Context) {
+	if s.cfg.SweepIntervalMinutes <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(time.Duration(s.cfg.SweepIntervalMinutes) * time.Minute)
+	defer ticker.Stop()
+
+	for {
+		published, archived, err := s.Sweep(ctx, time.Now())
+		if err != nil {
+			log.Printf("⚠️  Trip lifecycle sweep failed: %v", err)
+		} else if published > 0 || archived > 0 {
+			log.Printf("✓ Trip lifecycle sweep: %d published, %d archived", published, archived)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// findOwnedTrip loads a trip and verifies the user owns it
+func (s *TripLifecycleService) findOwnedTrip(ctx context.Context, tripID, userID string) (*models.Trip, error) {
+	trip, err := s.tripRepo.FindByID(ctx, tripID)
+	if err != nil {
+		return nil, errors.New("trip not found")
+	}
+
+	if !s.tripRepo.IsOwner(trip, userID) {
+		return nil, errors.New("unauthorized: you don't own this trip")
+	}
+	return trip, nil
+}
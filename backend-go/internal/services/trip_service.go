@@ -3,6 +3,8 @@ package services
 import (
 	"context"
 	"errors"
+	"math"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -10,26 +12,35 @@ import (
 	"backend-go/internal/models"
 	"backend-go/internal/repository"
 	"backend-go/internal/schemas"
+	"backend-go/pkg/sse"
 	"backend-go/pkg/utils"
 
+	"github.com/kamva/mgm/v3"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/trace"
 )
 
 type TripService struct {
-	tripRepo      *repository.TripRepository
-	userRepo      *repository.UserRepository
-	itineraryRepo *repository.ItineraryRepository
-	tracer        trace.Tracer
+	tripRepo           *repository.TripRepository
+	userRepo           *repository.UserRepository
+	itineraryRepo      *repository.ItineraryRepository
+	entryRepo          *repository.ItineraryEntryRepository
+	interactionService *TripInteractionService
+	tripHub            *sse.TripHub
+	tracer             trace.Tracer
 }
 
-func NewTripService() *TripService {
+func NewTripService(tripHub *sse.TripHub) *TripService {
 	return &TripService{
-		tripRepo:      repository.NewTripRepository(),
-		userRepo:      repository.NewUserRepository(),
-		itineraryRepo: repository.NewItineraryRepository(),
-		tracer:        otel.Tracer("trip-service"),
+		tripRepo:           repository.NewTripRepository(),
+		userRepo:           repository.NewUserRepository(),
+		itineraryRepo:      repository.NewItineraryRepository(),
+		entryRepo:          repository.NewItineraryEntryRepository(),
+		interactionService: NewTripInteractionService(),
+		tripHub:            tripHub,
+		tracer:             otel.Tracer("trip-service"),
 	}
 }
 
@@ -106,6 +117,15 @@ func (s *TripService) CreateTrip(ctx context.Context, userID string, req *schema
 		trip.Type = *req.Type
 	}
 
+	// Guides are meant to be shared, personal trips stay private unless requested otherwise
+	trip.Visibility = models.TripVisibilityPrivate
+	if trip.Type == models.TripTypeGuide {
+		trip.Visibility = models.TripVisibilityPublic
+	}
+	if req.Visibility != nil {
+		trip.Visibility = *req.Visibility
+	}
+
 	// Add owner as first member with owner role
 	trip.TripMembers = []models.TripMember{
 		{
@@ -174,6 +194,125 @@ func (s *TripService) createDefaultItineraries(ctx context.Context, trip *models
 	return nil
 }
 
+// Shrink modes for entries on days removed when a trip gets shorter
+const (
+	ShrinkModeRefuse      = "refuse"      // Fail if any removed day has entries
+	ShrinkModeUnscheduled = "unscheduled" // Move those entries to the trip's unscheduled bucket
+)
+
+// resizeItineraries re-dates the trip's days to run from startDate to endDate
+// Days are kept in order, extra days are appended empty and trailing days are removed.
+// Removed days with entries either fail the update or hand their entries to the unscheduled bucket.
+func (s *TripService) resizeItineraries(ctx context.Context, trip *models.Trip, startDate, endDate time.Time, shrinkMode string) error {
+	itineraries, err := s.itineraryRepo.FindByTripID(ctx, trip.ID.Hex())
+	if err != nil {
+		return err
+	}
+
+	var bucket *models.Itinerary
+	days := make([]*models.Itinerary, 0, len(itineraries))
+	for _, itinerary := range itineraries {
+		if itinerary.Unscheduled {
+			bucket = itinerary
+			continue
+		}
+		days = append(days, itinerary)
+	}
+	sort.SliceStable(days, func(i, j int) bool {
+		return days[i].DayNumber < days[j].DayNumber
+	})
+
+	dayCount := int(math.Round(endDate.Sub(startDate).Hours()/24)) + 1
+
+	// Check removed days before touching anything
+	var removed []*models.Itinerary
+	var orphaned []*models.ItineraryEntry
+	if dayCount < len(days) {
+		removed = days[dayCount:]
+		days = days[:dayCount]
+
+		for _, day := range removed {
+			entries, err := s.entryRepo.FindByItineraryID(ctx, day.ID.Hex())
+			if err != nil {
+				return err
+			}
+			orphaned = append(orphaned, entries...)
+		}
+
+		if len(orphaned) > 0 && shrinkMode != ShrinkModeUnscheduled {
+			return errors.New("cannot shorten trip: removed days have entries")
+		}
+	}
+
+	if len(orphaned) > 0 {
+		if bucket == nil {
+			bucket = &models.Itinerary{
+				TripID:      trip.ID,
+				Title:       "Unscheduled",
+				Unscheduled: true,
+			}
+			if err := s.itineraryRepo.Create(ctx, bucket); err != nil {
+				return err
+			}
+		}
+
+		order, err := s.entryRepo.CountByItineraryID(ctx, bucket.ID.Hex())
+		if err != nil {
+			return err
+		}
+		for _, entry := range orphaned {
+			order++
+			entry.ItineraryID = bucket.ID
+			entry.Order = int(order)
+			if err := s.entryRepo.Update(ctx, entry); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, day := range removed {
+		if err := s.itineraryRepo.Delete(ctx, day.ID.Hex()); err != nil {
+			return err
+		}
+	}
+
+	for i, day := range days {
+		dayNumber := i + 1
+		date := startDate.AddDate(0, 0, i).Format("2006-01-02")
+		title := day.Title
+		// Renumber default titles, custom titles are kept
+		if title == "Day "+strconv.Itoa(day.DayNumber) {
+			title = "Day " + strconv.Itoa(dayNumber)
+		}
+		if day.DayNumber == dayNumber && day.Order == dayNumber && day.Date == date && day.Title == title {
+			continue
+		}
+
+		day.DayNumber = dayNumber
+		day.Order = dayNumber
+		day.Date = date
+		day.Title = title
+		if err := s.itineraryRepo.Update(ctx, day); err != nil {
+			return err
+		}
+	}
+
+	for dayNumber := len(days) + 1; dayNumber <= dayCount; dayNumber++ {
+		itinerary := &models.Itinerary{
+			TripID:    trip.ID,
+			Date:      startDate.AddDate(0, 0, dayNumber-1).Format("2006-01-02"),
+			DayNumber: dayNumber,
+			Title:     "Day " + strconv.Itoa(dayNumber),
+			Order:     dayNumber,
+		}
+		if err := s.itineraryRepo.Create(ctx, itinerary); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (s *TripService) GetTrip(ctx context.Context, tripID string) (*models.Trip, error) {
 	ctx, span := s.tracer.Start(ctx, "TripService.GetTrip")
 	defer span.End()
@@ -194,26 +333,144 @@ func (s *TripService) GetTrip(ctx context.Context, tripID string) (*models.Trip,
 }
 
 // GetTripWithFullData gets trip with all related data (itineraries, entries, expenses, users) in 1 query
-func (s *TripService) GetTripWithFullData(ctx context.Context, tripID string) (*schemas.TripDetailResponse, error) {
+// viewerID may be empty for anonymous requests, private and draft trips are hidden from non-members
+func (s *TripService) GetTripWithFullData(ctx context.Context, tripID, viewerID string) (*schemas.TripDetailResponse, error) {
 	ctx, span := s.tracer.Start(ctx, "TripService.GetTripWithFullData")
 	defer span.End()
 	logger := utils.NewTraceLogger(ctx, span)
 
 	logger.Input(map[string]interface{}{
-		"tripID": tripID,
+		"tripID":   tripID,
+		"viewerID": viewerID,
 	})
 
+	trip, err := s.tripRepo.FindByID(ctx, tripID)
+	if err != nil {
+		err := errors.New("trip not found")
+		logger.Error(err)
+		return nil, err
+	}
+
+	// Respond as not found so private trips don't leak their existence
+	if !s.tripRepo.CanView(trip, viewerID) {
+		err := errors.New("trip not found")
+		logger.Error(err)
+		return nil, err
+	}
+
 	data, err := s.tripRepo.FindByIDWithFullData(ctx, tripID)
 	if err != nil {
 		logger.Error(err)
 		return nil, err
 	}
 
+	if viewerID != "" {
+		state, err := s.interactionService.GetViewerState(ctx, tripID, viewerID)
+		if err != nil {
+			logger.Error(err)
+			return nil, err
+		}
+		data.ViewerState = state
+	}
+
 	logger.Output(data)
 	return data, nil
 }
 
-func (s *TripService) ListTrips(ctx context.Context, query *schemas.ListTripsQuery) ([]*models.Trip, error) {
+// SearchTrips runs a full-text search over trips and guides the viewer may list
+func (s *TripService) SearchTrips(ctx context.Context, query *schemas.SearchTripsQuery, viewerID string) (*repository.TripSearchResult, error) {
+	ctx, span := s.tracer.Start(ctx, "TripService.SearchTrips")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	logger.Input(map[string]interface{}{
+		"q":        query.Q,
+		"type":     query.Type,
+		"level":    query.Level,
+		"country":  query.Country,
+		"tags":     query.Tags,
+		"limit":    query.Limit,
+		"offset":   query.Offset,
+		"viewerID": viewerID,
+	})
+
+	filter := &repository.TripSearchFilter{
+		Query:    query.Q,
+		ViewerID: &viewerID,
+		Limit:    int64(query.Limit),
+		Offset:   int64(query.Offset),
+	}
+
+	if query.Type != "" {
+		filter.Type = &query.Type
+	}
+
+	if query.Level != "" {
+		filter.Level = &query.Level
+	}
+
+	if query.Country != "" {
+		filter.Country = &query.Country
+	}
+
+	if query.Tags != "" {
+		for _, tag := range strings.Split(query.Tags, ",") {
+			if trimmed := strings.TrimSpace(tag); trimmed != "" {
+				filter.Tags = append(filter.Tags, trimmed)
+			}
+		}
+	}
+
+	result, err := s.tripRepo.Search(ctx, filter)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	logger.Output(map[string]interface{}{
+		"count": len(result.Trips),
+		"total": result.Total,
+	})
+	return result, nil
+}
+
+// BackfillEntryTitles denormalizes entry titles on trips created before full-text search existed
+func (s *TripService) BackfillEntryTitles(ctx context.Context) (int, error) {
+	ctx, span := s.tracer.Start(ctx, "TripService.BackfillEntryTitles")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	const batchSize = 200
+	updated := 0
+
+	for {
+		ids, err := s.tripRepo.FindIDsMissingEntryTitles(ctx, batchSize)
+		if err != nil {
+			logger.Error(err)
+			return updated, err
+		}
+
+		for _, id := range ids {
+			if err := s.tripRepo.RefreshEntryTitles(ctx, id); err != nil {
+				logger.Error(err)
+				return updated, err
+			}
+			updated++
+		}
+
+		if len(ids) < batchSize {
+			break
+		}
+	}
+
+	logger.Output(map[string]interface{}{
+		"updated": updated,
+	})
+	return updated, nil
+}
+
+// ListTrips lists trips visible to viewerID (empty for anonymous): public trips plus the viewer's own
+func (s *TripService) ListTrips(ctx context.Context, query *schemas.ListTripsQuery, viewerID string) ([]*models.Trip, error) {
 	ctx, span := s.tracer.Start(ctx, "TripService.ListTrips")
 	defer span.End()
 	logger := utils.NewTraceLogger(ctx, span)
@@ -226,12 +483,119 @@ func (s *TripService) ListTrips(ctx context.Context, query *schemas.ListTripsQue
 		"type":     query.Type,
 		"status":   query.Status,
 		"tags":     query.Tags,
+		"viewerID": viewerID,
+	})
+
+	filter := tripFilterFromQuery(query, viewerID)
+
+	// Use dynamic Find method
+	trips, err := s.tripRepo.Find(ctx, filter)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	logger.Output(map[string]interface{}{
+		"count": len(trips),
+	})
+	return trips, nil
+}
+
+// defaultNearbyRadiusKm is used when a radius search doesn't set radiusKm
+const defaultNearbyRadiusKm = 50
+
+// FindNearbyTrips lists trips whose destination is within a radius of a point, nearest first
+func (s *TripService) FindNearbyTrips(ctx context.Context, query *schemas.NearbyTripsQuery, viewerID string) ([]*models.Trip, error) {
+	ctx, span := s.tracer.Start(ctx, "TripService.FindNearbyTrips")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	logger.Input(map[string]interface{}{
+		"lat":      *query.Lat,
+		"lng":      *query.Lng,
+		"radiusKm": query.RadiusKm,
+		"viewerID": viewerID,
+	})
+
+	radius := query.RadiusKm
+	if radius == 0 {
+		radius = defaultNearbyRadiusKm
+	}
+
+	trips, err := s.tripRepo.FindByGeo(ctx, &repository.TripGeoFilter{
+		TripFilter:    *tripFilterFromQuery(&query.ListTripsQuery, viewerID),
+		Lat:           *query.Lat,
+		Lng:           *query.Lng,
+		MaxDistanceKm: &radius,
+	})
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	logger.Output(map[string]interface{}{
+		"count": len(trips),
+	})
+	return trips, nil
+}
+
+// FindTripsWithinBox lists trips whose destination is inside a map viewport, nearest to its center first
+func (s *TripService) FindTripsWithinBox(ctx context.Context, query *schemas.WithinTripsQuery, viewerID string) ([]*models.Trip, error) {
+	ctx, span := s.tracer.Start(ctx, "TripService.FindTripsWithinBox")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	box := &repository.GeoBox{
+		SWLat: *query.SWLat,
+		SWLng: *query.SWLng,
+		NELat: *query.NELat,
+		NELng: *query.NELng,
+	}
+
+	logger.Input(map[string]interface{}{
+		"box":      box,
+		"viewerID": viewerID,
+	})
+
+	if box.SWLat > box.NELat || box.SWLng == box.NELng {
+		err := errors.New("invalid bounding box")
+		logger.Error(err)
+		return nil, err
+	}
+
+	// Center of the box, accounting for viewports that cross the antimeridian
+	east := box.NELng
+	if east <= box.SWLng {
+		east += 360
+	}
+	centerLng := (box.SWLng + east) / 2
+	if centerLng > 180 {
+		centerLng -= 360
+	}
+
+	trips, err := s.tripRepo.FindByGeo(ctx, &repository.TripGeoFilter{
+		TripFilter: *tripFilterFromQuery(&query.ListTripsQuery, viewerID),
+		Lat:        (box.SWLat + box.NELat) / 2,
+		Lng:        centerLng,
+		Box:        box,
+	})
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	logger.Output(map[string]interface{}{
+		"count": len(trips),
 	})
+	return trips, nil
+}
 
-	// Build filter from query parameters
+// tripFilterFromQuery builds the listing filter shared by ListTrips and the geo queries
+func tripFilterFromQuery(query *schemas.ListTripsQuery, viewerID string) *repository.TripFilter {
 	filter := &repository.TripFilter{
-		Limit:  int64(query.Limit),
-		Offset: int64(query.Offset),
+		ViewerID: &viewerID,
+		Limit:    int64(query.Limit),
+		Offset:   int64(query.Offset),
 	}
 
 	if query.Type != "" {
@@ -262,20 +626,11 @@ func (s *TripService) ListTrips(ctx context.Context, query *schemas.ListTripsQue
 		filter.Tags = tags
 	}
 
-	// Use dynamic Find method
-	trips, err := s.tripRepo.Find(ctx, filter)
-	if err != nil {
-		logger.Error(err)
-		return nil, err
-	}
-
-	logger.Output(map[string]interface{}{
-		"count": len(trips),
-	})
-	return trips, nil
+	return filter
 }
 
-func (s *TripService) UpdateTrip(ctx context.Context, tripID, userID string, req *schemas.UpdateTripRequest) (*models.Trip, error) {
+// UpdateTrip applies a partial update, expectedVersion is the client's If-Match version
+func (s *TripService) UpdateTrip(ctx context.Context, tripID, userID string, req *schemas.UpdateTripRequest, expectedVersion *int64) (*models.Trip, error) {
 	ctx, span := s.tracer.Start(ctx, "TripService.UpdateTrip")
 	defer span.End()
 	logger := utils.NewTraceLogger(ctx, span)
@@ -300,6 +655,19 @@ func (s *TripService) UpdateTrip(ctx context.Context, tripID, userID string, req
 		return nil, err
 	}
 
+	// Archived trips are read-only until unarchived
+	if trip.IsArchived() {
+		err := errors.New("trip is archived")
+		logger.Error(err)
+		return nil, err
+	}
+
+	// Checked before resizing so a stale edit doesn't touch the itinerary
+	if err := checkVersion(trip.Version, expectedVersion); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
 	// Parse and validate date range if provided
 	startDate := trip.StartDate
 	endDate := trip.EndDate
@@ -329,6 +697,13 @@ func (s *TripService) UpdateTrip(ctx context.Context, tripID, userID string, req
 		return nil, err
 	}
 
+	// Itinerary days are re-dated to match, adding or removing days as needed
+	resized := trip.Type == models.TripTypeTrip && (!startDate.Equal(trip.StartDate) || !endDate.Equal(trip.EndDate))
+	shrinkMode := ShrinkModeRefuse
+	if req.ShrinkMode != nil {
+		shrinkMode = *req.ShrinkMode
+	}
+
 	// Update fields
 	if req.Title != nil {
 		trip.Title = *req.Title
@@ -365,22 +740,46 @@ func (s *TripService) UpdateTrip(ctx context.Context, tripID, userID string, req
 	}
 	if req.Status != nil {
 		trip.Status = *req.Status
+		// Publishing by hand supersedes any schedule
+		if trip.Status == models.TripStatusPublished {
+			trip.PublishAt = nil
+		}
+	}
+	if req.Visibility != nil {
+		trip.Visibility = *req.Visibility
 	}
 	if req.Level != nil {
 		trip.Level = req.Level
 	}
 
-	// Update in database
-	if err := s.tripRepo.Update(ctx, trip); err != nil {
+	// Resize and save together, a version conflict on the trip must not leave the days resized
+	err = mgm.TransactionWithCtx(ctx, func(session mongo.Session, sc mongo.SessionContext) error {
+		if resized {
+			if err := s.resizeItineraries(sc, trip, startDate, endDate, shrinkMode); err != nil {
+				_ = session.AbortTransaction(sc)
+				return err
+			}
+		}
+		if err := s.tripRepo.Update(sc, trip); err != nil {
+			_ = session.AbortTransaction(sc)
+			return err
+		}
+		return session.CommitTransaction(sc)
+	})
+	if err != nil {
 		logger.Error(err)
 		return nil, err
 	}
 
+	if resized {
+		publishDaysChanged(ctx, s.tripHub, s.itineraryRepo, trip)
+	}
+
 	logger.Output(trip)
 	return trip, nil
 }
 
-func (s *TripService) DeleteTrip(ctx context.Context, tripID, userID string) error {
+func (s *TripService) DeleteTrip(ctx context.Context, tripID, userID string, expectedVersion *int64) error {
 	ctx, span := s.tracer.Start(ctx, "TripService.DeleteTrip")
 	defer span.End()
 	logger := utils.NewTraceLogger(ctx, span)
@@ -405,8 +804,13 @@ func (s *TripService) DeleteTrip(ctx context.Context, tripID, userID string) err
 		return err
 	}
 
-	// Soft delete
-	err = s.tripRepo.Delete(ctx, tripID)
+	if err := checkVersion(trip.Version, expectedVersion); err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	// Soft delete, the trip stays in the owner's trash until the purge job removes it
+	err = s.tripRepo.SoftDelete(ctx, tripID)
 	if err != nil {
 		logger.Error(err)
 		return err
@@ -416,46 +820,201 @@ func (s *TripService) DeleteTrip(ctx context.Context, tripID, userID string) err
 	return nil
 }
 
-// Trip Members
-func (s *TripService) GetTripMembers(ctx context.Context, tripID string) ([]models.TripMember, error) {
-	trip, err := s.tripRepo.FindByID(ctx, tripID)
+// ForkTrip deep-copies a trip (typically a published guide) into a new draft trip owned by userID.
+// Days are re-dated from the requested start date, entries keep their place references and
+// todos are reset to not completed.
+func (s *TripService) ForkTrip(ctx context.Context, sourceTripID, userID string, req *schemas.ForkTripRequest) (*models.Trip, error) {
+	ctx, span := s.tracer.Start(ctx, "TripService.ForkTrip")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	logger.Input(map[string]interface{}{
+		"sourceTripID": sourceTripID,
+		"userID":       userID,
+		"startDate":    req.StartDate,
+	})
+
+	startDate, err := time.Parse("2006-01-02", req.StartDate)
 	if err != nil {
-		return nil, errors.New("trip not found")
+		err := errors.New("invalid start date format, expected YYYY-MM-DD")
+		logger.Error(err)
+		return nil, err
 	}
-	return trip.TripMembers, nil
-}
 
-func (s *TripService) AddTripMember(ctx context.Context, tripID, userID string, req *schemas.AddTripMemberRequest) (*models.TripMember, error) {
-	trip, err := s.tripRepo.FindByID(ctx, tripID)
+	source, err := s.tripRepo.FindByID(ctx, sourceTripID)
 	if err != nil {
-		return nil, errors.New("trip not found")
+		err := errors.New("trip not found")
+		logger.Error(err)
+		return nil, err
 	}
 
-	// Only owner can add members
-	if !s.tripRepo.IsOwner(trip, userID) {
-		return nil, errors.New("unauthorized: only owner can add members")
+	// Only published trips can be forked by anyone, drafts only by their members
+	if source.Status != models.TripStatusPublished && !s.tripRepo.IsOwner(source, userID) && !s.tripRepo.IsMemberExists(source, userID) {
+		err := errors.New("trip not found")
+		logger.Error(err)
+		return nil, err
 	}
 
-	// Check if user is already a member
-	if s.tripRepo.IsMemberExists(trip, req.UserID) {
-		return nil, errors.New("user is already a member")
+	// Load days with entries before creating anything so a bad source fails early
+	itineraries, err := s.itineraryRepo.FindByTripIDWithEntries(ctx, sourceTripID)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
 	}
 
-	// Create new member using repository helper
-	member, err := s.tripRepo.NewTripMember(req.UserID, req.Role)
+	trip, err := s.tripRepo.NewTrip(userID)
 	if err != nil {
-		return nil, errors.New("invalid user ID")
+		err := errors.New("invalid user ID")
+		logger.Error(err)
+		return nil, err
 	}
 
-	trip.TripMembers = append(trip.TripMembers, *member)
-	if err := s.tripRepo.Update(ctx, trip); err != nil {
+	// New trip spans as many days as the source itinerary
+	lastDay := 1
+	for _, itinerary := range itineraries {
+		if itinerary.DayNumber > lastDay {
+			lastDay = itinerary.DayNumber
+		}
+	}
+
+	trip.Title = source.Title
+	if req.Title != nil {
+		trip.Title = *req.Title
+	}
+	trip.Description = source.Description
+	trip.StartDate = startDate
+	trip.EndDate = startDate.AddDate(0, 0, lastDay-1)
+	trip.Destinations = source.Destinations
+	trip.BudgetTotal = source.BudgetTotal
+	trip.BudgetCurrency = source.BudgetCurrency
+	trip.CoverPhoto = source.CoverPhoto
+	trip.Tags = source.Tags
+	trip.Level = source.Level
+	trip.Status = models.TripStatusDraft
+	trip.Type = models.TripTypeTrip
+	trip.ForkedFromID = &source.ID
+	trip.TripMembers = []models.TripMember{
+		{
+			ID:       primitive.NewObjectID(),
+			UserID:   trip.OwnerID,
+			Role:     models.MemberRoleOwner,
+			JoinedAt: time.Now(),
+		},
+	}
+
+	if err := s.tripRepo.Create(ctx, trip); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	// A source without days still gets the default days, anything half-copied is discarded
+	entryCount := 0
+	if len(itineraries) == 0 {
+		err = s.createDefaultItineraries(ctx, trip)
+	} else {
+		entryCount, err = s.copyItineraries(ctx, trip, itineraries, startDate)
+	}
+	if err != nil {
+		logger.Error(err)
+		if discardErr := s.discardTrip(ctx, trip.ID); discardErr != nil {
+			logger.Error(discardErr)
+		}
 		return nil, err
 	}
 
-	return member, nil
+	// Search index is secondary, a failure here shouldn't fail the fork
+	if err := s.tripRepo.RefreshEntryTitles(ctx, trip.ID); err != nil {
+		logger.Error(err)
+	}
+
+	logger.Output(map[string]interface{}{
+		"tripID":      trip.ID.Hex(),
+		"itineraries": len(itineraries),
+		"entries":     entryCount,
+	})
+	return trip, nil
 }
 
-func (s *TripService) UpdateTripMember(ctx context.Context, tripID, memberID, userID string, req *schemas.UpdateTripMemberRequest) (*models.TripMember, error) {
+// copyItineraries copies the source days and their entries into trip, re-dated from startDate
+func (s *TripService) copyItineraries(ctx context.Context, trip *models.Trip, itineraries []*models.Itinerary, startDate time.Time) (int, error) {
+	entryCount := 0
+	for _, day := range itineraries {
+		itinerary := &models.Itinerary{
+			TripID:    trip.ID,
+			DayNumber: day.DayNumber,
+			Date:      startDate.AddDate(0, 0, day.DayNumber-1).Format("2006-01-02"),
+			Title:     day.Title,
+			Order:     day.Order,
+		}
+		if day.Unscheduled {
+			itinerary.Date = ""
+			itinerary.Unscheduled = true
+		}
+		if err := s.itineraryRepo.Create(ctx, itinerary); err != nil {
+			return entryCount, err
+		}
+
+		for _, sourceEntry := range day.Entries {
+			entry := &models.ItineraryEntry{
+				ItineraryID: itinerary.ID,
+				Type:        sourceEntry.Type,
+				Title:       sourceEntry.Title,
+				Description: sourceEntry.Description,
+				PlaceID:     sourceEntry.PlaceID,
+				StartTime:   sourceEntry.StartTime,
+				EndTime:     sourceEntry.EndTime,
+				Duration:    sourceEntry.Duration,
+				Budget:      sourceEntry.Budget,
+				Photos:      sourceEntry.Photos,
+				Order:       sourceEntry.Order,
+			}
+
+			// Todos start fresh for the new owner
+			for _, todo := range sourceEntry.Todos {
+				entry.Todos = append(entry.Todos, models.Todo{
+					ID:        primitive.NewObjectID().Hex(),
+					Title:     todo.Title,
+					Completed: false,
+					Order:     todo.Order,
+				})
+			}
+
+			if err := s.entryRepo.Create(ctx, entry); err != nil {
+				return entryCount, err
+			}
+			entryCount++
+		}
+	}
+	return entryCount, nil
+}
+
+// discardTrip hard deletes a trip that failed to be set up, along with the days and entries created so far
+func (s *TripService) discardTrip(ctx context.Context, tripID primitive.ObjectID) error {
+	itineraries, err := s.itineraryRepo.FindByTripID(ctx, tripID.Hex())
+	if err != nil {
+		return err
+	}
+	for _, itinerary := range itineraries {
+		if err := s.entryRepo.DeleteByItineraryID(ctx, itinerary.ID.Hex()); err != nil {
+			return err
+		}
+	}
+	if err := s.itineraryRepo.DeleteByTripID(ctx, tripID.Hex()); err != nil {
+		return err
+	}
+	return s.tripRepo.Delete(ctx, tripID.Hex())
+}
+
+// Trip Members
+func (s *TripService) GetTripMembers(ctx context.Context, tripID string) ([]models.TripMember, error) {
+	trip, err := s.tripRepo.FindByID(ctx, tripID)
+	if err != nil {
+		return nil, errors.New("trip not found")
+	}
+	return trip.TripMembers, nil
+}
+
+func (s *TripService) UpdateTripMember(ctx context.Context, tripID, memberID, userID string, req *schemas.UpdateTripMemberRequest, expectedVersion *int64) (*models.TripMember, error) {
 	trip, err := s.tripRepo.FindByID(ctx, tripID)
 	if err != nil {
 		return nil, errors.New("trip not found")
@@ -466,6 +1025,11 @@ func (s *TripService) UpdateTripMember(ctx context.Context, tripID, memberID, us
 		return nil, errors.New("unauthorized: only owner can update members")
 	}
 
+	// Members are embedded in the trip, so the trip version guards them
+	if err := checkVersion(trip.Version, expectedVersion); err != nil {
+		return nil, err
+	}
+
 	// Find member using repository helper
 	_, index, err := s.tripRepo.FindMemberByID(trip, memberID)
 	if err != nil {
@@ -479,10 +1043,12 @@ func (s *TripService) UpdateTripMember(ctx context.Context, tripID, memberID, us
 		return nil, err
 	}
 
+	publishTripEvent(s.tripHub, trip.ID, sse.TripEventMemberUpdated, trip.TripMembers[index])
+
 	return &trip.TripMembers[index], nil
 }
 
-func (s *TripService) DeleteTripMember(ctx context.Context, tripID, memberID, userID string) error {
+func (s *TripService) DeleteTripMember(ctx context.Context, tripID, memberID, userID string, expectedVersion *int64) error {
 	trip, err := s.tripRepo.FindByID(ctx, tripID)
 	if err != nil {
 		return errors.New("trip not found")
@@ -493,14 +1059,36 @@ func (s *TripService) DeleteTripMember(ctx context.Context, tripID, memberID, us
 		return errors.New("unauthorized: only owner can remove members")
 	}
 
+	if err := checkVersion(trip.Version, expectedVersion); err != nil {
+		return err
+	}
+
 	// Find member using repository helper
-	_, index, err := s.tripRepo.FindMemberByID(trip, memberID)
+	member, index, err := s.tripRepo.FindMemberByID(trip, memberID)
 	if err != nil {
 		return err
 	}
+	removedUserID := member.UserID
+
+	// The calendar feed is readable by anyone holding its token, so a removed member's
+	// subscription must stop updating. Remaining members resubscribe with the new token.
+	if trip.CalendarToken != nil {
+		token, err := generateInviteToken()
+		if err != nil {
+			return err
+		}
+		if _, err := s.tripRepo.ReplaceCalendarToken(ctx, trip.ID, &token); err != nil {
+			return err
+		}
+	}
 
 	// Remove member from slice
 	trip.TripMembers = append(trip.TripMembers[:index], trip.TripMembers[index+1:]...)
 
-	return s.tripRepo.Update(ctx, trip)
+	if err := s.tripRepo.Update(ctx, trip); err != nil {
+		return err
+	}
+
+	publishMemberRemoved(s.tripHub, trip.ID, memberID, removedUserID)
+	return nil
 }
@@ -0,0 +1,205 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+
+	"backend-go/internal/models"
+	"backend-go/internal/repository"
+	"backend-go/internal/schemas"
+	"backend-go/pkg/geo"
+	"backend-go/pkg/utils"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// The planning window a day's free time is measured against, entries outside it widen the window
+	planningDayStart = 8 * 60
+	planningDayEnd   = 22 * 60
+)
+
+type TripStatsService struct {
+	tripRepo      *repository.TripRepository
+	itineraryRepo *repository.ItineraryRepository
+	expenseRepo   *repository.ExpenseRepository
+	tracer        trace.Tracer
+}
+
+func NewTripStatsService() *TripStatsService {
+	return &TripStatsService{
+		tripRepo:      repository.NewTripRepository(),
+		itineraryRepo: repository.NewItineraryRepository(),
+		expenseRepo:   repository.NewExpenseRepository(),
+		tracer:        otel.Tracer("trip-stats-service"),
+	}
+}
+
+// GetTripStats summarizes days, places, distance, time and budget against actual spend
+// Access is checked by the route middleware
+func (s *TripStatsService) GetTripStats(ctx context.Context, tripID string) (*schemas.TripStatsResponse, error) {
+	ctx, span := s.tracer.Start(ctx, "TripStatsService.GetTripStats")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	logger.Input(map[string]interface{}{
+		"tripID": tripID,
+	})
+
+	trip, err := s.tripRepo.FindByID(ctx, tripID)
+	if err != nil {
+		err := errors.New("trip not found")
+		logger.Error(err)
+		return nil, err
+	}
+
+	itineraries, err := s.itineraryRepo.FindByTripIDWithEntries(ctx, tripID)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	spent, err := s.expenseRepo.GetTotalByTrip(ctx, tripID)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	byCategory, err := s.expenseRepo.GetTotalByCategory(ctx, tripID)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	stats := &schemas.TripStatsResponse{
+		TripID: trip.ID.Hex(),
+		Days:   []schemas.TripDayStats{},
+		Budget: schemas.TripBudgetStats{
+			Currency:   trip.BudgetCurrency,
+			Planned:    trip.BudgetTotal,
+			Spent:      roundTo2(spent),
+			ByCategory: byCategory,
+		},
+	}
+	if trip.BudgetTotal != nil {
+		remaining := roundTo2(*trip.BudgetTotal - spent)
+		stats.Budget.Remaining = &remaining
+	}
+
+	places := map[string]bool{}
+	var scheduledMinutes, freeMinutes int
+	for _, itinerary := range itineraries {
+		stats.EntryCount += len(itinerary.Entries)
+		for _, entry := range itinerary.Entries {
+			if entry.PlaceID != nil {
+				places[entry.PlaceID.Hex()] = true
+			}
+		}
+
+		// The unscheduled bucket isn't a day, its entries only count towards the totals
+		if itinerary.Unscheduled {
+			continue
+		}
+
+		day, scheduled, free := dayStats(itinerary)
+		stats.Days = append(stats.Days, day)
+		stats.DistanceKm += day.DistanceKm
+		scheduledMinutes += scheduled
+		freeMinutes += free
+	}
+
+	stats.DayCount = len(stats.Days)
+	stats.PlaceCount = len(places)
+	stats.DistanceKm = roundTo2(stats.DistanceKm)
+	stats.ScheduledHours = minutesToHours(scheduledMinutes)
+	stats.FreeHours = minutesToHours(freeMinutes)
+
+	logger.Output(map[string]interface{}{
+		"days":       stats.DayCount,
+		"entries":    stats.EntryCount,
+		"places":     stats.PlaceCount,
+		"distanceKm": stats.DistanceKm,
+	})
+	return stats, nil
+}
+
+// dayStats computes one day's figures, entries are already sorted by order
+// Also returns the scheduled and free time in minutes so totals aren't summed from rounded hours.
+func dayStats(itinerary *models.Itinerary) (schemas.TripDayStats, int, int) {
+	day := schemas.TripDayStats{
+		ItineraryID: itinerary.ID.Hex(),
+		DayNumber:   itinerary.DayNumber,
+		Date:        itinerary.Date,
+		EntryCount:  len(itinerary.Entries),
+	}
+
+	places := map[string]bool{}
+	points := []geo.Point{}
+	intervals := [][2]int{}
+	for _, entry := range itinerary.Entries {
+		if entry.PlaceID != nil {
+			places[entry.PlaceID.Hex()] = true
+		}
+		if entry.Place != nil {
+			if lat, lng, ok := entry.Place.Location.LatLng(); ok {
+				points = append(points, geo.Point{Lat: lat, Lng: lng})
+			}
+		}
+		if start, end, ok := entry.Schedule(); ok && end > start {
+			intervals = append(intervals, [2]int{start, end})
+		}
+	}
+
+	scheduled, windowStart, windowEnd := mergeIntervals(intervals)
+	if windowStart > planningDayStart {
+		windowStart = planningDayStart
+	}
+	if windowEnd < planningDayEnd {
+		windowEnd = planningDayEnd
+	}
+	free := windowEnd - windowStart - scheduled
+
+	day.PlaceCount = len(places)
+	day.DistanceKm = roundTo2(geo.PathKm(points))
+	day.ScheduledHours = minutesToHours(scheduled)
+	day.FreeHours = minutesToHours(free)
+	return day, scheduled, free
+}
+
+// mergeIntervals returns the minutes covered by the intervals, overlaps counted once,
+// together with the earliest start and latest end (the planning window when empty)
+func mergeIntervals(intervals [][2]int) (covered, first, last int) {
+	if len(intervals) == 0 {
+		return 0, planningDayStart, planningDayEnd
+	}
+
+	sort.Slice(intervals, func(i, j int) bool {
+		return intervals[i][0] < intervals[j][0]
+	})
+
+	first = intervals[0][0]
+	curStart, curEnd := intervals[0][0], intervals[0][1]
+	for _, interval := range intervals[1:] {
+		if interval[0] <= curEnd {
+			if interval[1] > curEnd {
+				curEnd = interval[1]
+			}
+			continue
+		}
+		covered += curEnd - curStart
+		curStart, curEnd = interval[0], interval[1]
+	}
+	covered += curEnd - curStart
+	return covered, first, curEnd
+}
+
+func minutesToHours(minutes int) float64 {
+	return roundTo2(float64(minutes) / 60)
+}
+
+func roundTo2(value float64) float64 {
+	return math.Round(value*100) / 100
+}
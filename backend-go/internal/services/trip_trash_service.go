@@ -0,0 +1,206 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"backend-go/internal/config"
+	"backend-go/internal/models"
+	"backend-go/internal/repository"
+	"backend-go/internal/schemas"
+	"backend-go/pkg/utils"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const purgeBatchSize = 100
+
+// TripTrashService lists and restores soft deleted trips and purges them once retention has passed
+type TripTrashService struct {
+	tripRepo        *repository.TripRepository
+	itineraryRepo   *repository.ItineraryRepository
+	entryRepo       *repository.ItineraryEntryRepository
+	expenseRepo     *repository.ExpenseRepository
+	documentRepo    *repository.TripDocumentRepository
+	commentRepo     *repository.CommentRepository
+	interactionRepo *repository.InteractionRepository
+	cfg             *config.LifecycleConfig
+	tracer          trace.Tracer
+}
+
+func NewTripTrashService(cfg *config.LifecycleConfig) *TripTrashService {
+	return &TripTrashService{
+		tripRepo:        repository.NewTripRepository(),
+		itineraryRepo:   repository.NewItineraryRepository(),
+		entryRepo:       repository.NewItineraryEntryRepository(),
+		expenseRepo:     repository.NewExpenseRepository(),
+		documentRepo:    repository.NewTripDocumentRepository(),
+		commentRepo:     repository.NewCommentRepository(),
+		interactionRepo: repository.NewInteractionRepository(),
+		cfg:             cfg,
+		tracer:          otel.Tracer("trip-trash-service"),
+	}
+}
+
+// RetentionDays is how long deleted trips stay restorable
+func (s *TripTrashService) RetentionDays() int {
+	return s.cfg.TrashRetentionDays
+}
+
+// ListTrash lists the user's deleted trips, most recently deleted first
+func (s *TripTrashService) ListTrash(ctx context.Context, userID string, query *schemas.ListTrashQuery) ([]*models.Trip, error) {
+	ctx, span := s.tracer.Start(ctx, "TripTrashService.ListTrash")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	logger.Input(map[string]interface{}{
+		"userID": userID,
+		"limit":  query.Limit,
+		"offset": query.Offset,
+	})
+
+	limit := int64(query.Limit)
+	if limit == 0 {
+		limit = 20
+	}
+
+	trips, err := s.tripRepo.FindDeletedByOwner(ctx, userID, int64(query.Offset), limit)
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	logger.Output(map[string]interface{}{
+		"count": len(trips),
+	})
+	return trips, nil
+}
+
+// RestoreTrip takes a deleted trip out of the trash
+func (s *TripTrashService) RestoreTrip(ctx context.Context, tripID, userID string) (*models.Trip, error) {
+	ctx, span := s.tracer.Start(ctx, "TripTrashService.RestoreTrip")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	logger.Input(map[string]interface{}{
+		"tripID": tripID,
+		"userID": userID,
+	})
+
+	trip, err := s.tripRepo.FindDeletedByID(ctx, tripID)
+	if err != nil {
+		err := errors.New("trip not found")
+		logger.Error(err)
+		return nil, err
+	}
+
+	if !s.tripRepo.IsOwner(trip, userID) {
+		err := errors.New("unauthorized: you don't own this trip")
+		logger.Error(err)
+		return nil, err
+	}
+
+	trip.Restore()
+	if err := s.tripRepo.Update(ctx, trip); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	logger.Output(map[string]interface{}{
+		"title": trip.Title,
+	})
+	return trip, nil
+}
+
+// PurgeExpired hard deletes trips that have been in the trash longer than the retention period
+// Trips are purged one at a time and the trip document goes last, so a failed purge is retried on the next run.
+func (s *TripTrashService) PurgeExpired(ctx context.Context, now time.Time) (int, error) {
+	ctx, span := s.tracer.Start(ctx, "TripTrashService.PurgeExpired")
+	defer span.End()
+	logger := utils.NewTraceLogger(ctx, span)
+
+	logger.Input(map[string]interface{}{
+		"now":           now,
+		"retentionDays": s.cfg.TrashRetentionDays,
+	})
+
+	if s.cfg.TrashRetentionDays <= 0 {
+		logger.Warn("Trash retention is disabled, nothing purged")
+		return 0, nil
+	}
+
+	cutoff := now.AddDate(0, 0, -s.cfg.TrashRetentionDays)
+	purged := 0
+	for {
+		if err := ctx.Err(); err != nil {
+			logger.Error(err)
+			return purged, err
+		}
+
+		ids, err := s.tripRepo.FindIDsDeletedBefore(ctx, cutoff, purgeBatchSize)
+		if err != nil {
+			logger.Error(err)
+			return purged, err
+		}
+		if len(ids) == 0 {
+			break
+		}
+
+		for _, id := range ids {
+			if err := s.purgeTrip(ctx, id); err != nil {
+				logger.Error(err)
+				return purged, err
+			}
+			purged++
+		}
+	}
+
+	logger.Output(map[string]interface{}{
+		"purged": purged,
+	})
+	return purged, nil
+}
+
+// purgeTrip removes a trip and everything that hangs off it
+func (s *TripTrashService) purgeTrip(ctx context.Context, tripID primitive.ObjectID) error {
+	itineraries, err := s.itineraryRepo.FindByTripID(ctx, tripID.Hex())
+	if err != nil {
+		return err
+	}
+	for _, itinerary := range itineraries {
+		if err := s.entryRepo.DeleteByItineraryID(ctx, itinerary.ID.Hex()); err != nil {
+			return err
+		}
+	}
+
+	if err := s.itineraryRepo.DeleteByTripID(ctx, tripID.Hex()); err != nil {
+		return err
+	}
+
+	if err := s.expenseRepo.DeleteByTripID(ctx, tripID.Hex()); err != nil {
+		return err
+	}
+
+	// Only the links go, the files stay with their uploaders
+	if err := s.documentRepo.DeleteByTripID(ctx, tripID.Hex()); err != nil {
+		return err
+	}
+
+	commentIDs, err := s.commentRepo.DeleteByTargetID(ctx, tripID.Hex(), models.CommentTargetTrip)
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.interactionRepo.DeleteByTargets(ctx, models.InteractionTargetComment, commentIDs); err != nil {
+		return err
+	}
+
+	if _, err := s.interactionRepo.DeleteByTargets(ctx, models.InteractionTargetTrip, []primitive.ObjectID{tripID}); err != nil {
+		return err
+	}
+
+	return s.tripRepo.Delete(ctx, tripID.Hex())
+}
@@ -0,0 +1,37 @@
+package services
+
+import (
+	"context"
+
+	"backend-go/internal/repository"
+
+	"github.com/kamva/mgm/v3"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// ErrVersionConflict means the client's If-Match version is stale or a concurrent write won
+var ErrVersionConflict = repository.ErrVersionConflict
+
+// checkVersion fails with ErrVersionConflict when the version the client read is stale
+// A nil expected version skips the check, the versioned repository update still catches races.
+func checkVersion(current int64, expected *int64) error {
+	if expected != nil && *expected != current {
+		return ErrVersionConflict
+	}
+	return nil
+}
+
+// inTransaction runs fn in a transaction so a version conflict halfway leaves nothing written
+// When ctx already carries a session (an itinerary batch), fn joins that transaction instead.
+func inTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if mongo.SessionFromContext(ctx) != nil {
+		return fn(ctx)
+	}
+	return mgm.TransactionWithCtx(ctx, func(session mongo.Session, sc mongo.SessionContext) error {
+		if err := fn(sc); err != nil {
+			_ = session.AbortTransaction(sc)
+			return err
+		}
+		return session.CommitTransaction(sc)
+	})
+}
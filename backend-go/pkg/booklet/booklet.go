@@ -0,0 +1,332 @@
+package booklet
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/go-pdf/fpdf"
+)
+
+// Booklet is the printable content of a trip
+type Booklet struct {
+	Title       string
+	Subtitle    string // e.g. date range
+	Destination string
+	Description string
+	Cover       *Image
+	Days        []Day
+	Members     []Member
+	Budget      Budget
+}
+
+// Image is an in-memory picture, Type is "jpg", "png" or "gif"
+type Image struct {
+	Data []byte
+	Type string
+}
+
+type Day struct {
+	Heading string
+	Date    string
+	Entries []Entry
+}
+
+type Entry struct {
+	Time    string // e.g. "09:00 - 10:30"
+	Title   string
+	Address string
+	Notes   string
+}
+
+type Member struct {
+	Name string
+	Role string
+}
+
+type Budget struct {
+	Currency   string
+	Planned    *float64
+	Categories map[string]float64
+}
+
+// Options controls fonts of the rendered document
+// Core PDF fonts only cover Latin-1, a UTF-8 TrueType font is required for other scripts (e.g. Thai)
+type Options struct {
+	FontPath     string
+	BoldFontPath string
+}
+
+const (
+	pageMargin  = 15.0
+	timeColumn  = 32.0
+	lineHeight  = 5.5
+	fontFamily  = "booklet"
+	coreFamily  = "Helvetica"
+	accentRed   = 33
+	accentGreen = 97
+	accentBlue  = 140
+)
+
+type renderer struct {
+	pdf       *fpdf.Fpdf
+	family    string
+	translate func(string) string
+}
+
+// Render writes the booklet as PDF
+func Render(w io.Writer, b *Booklet, opts Options) error {
+	r, err := newRenderer(opts)
+	if err != nil {
+		return err
+	}
+
+	r.cover(b)
+	r.itinerary(b.Days)
+	r.members(b.Members)
+	r.budget(&b.Budget)
+
+	return r.pdf.Output(w)
+}
+
+func newRenderer(opts Options) (*renderer, error) {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(pageMargin, pageMargin, pageMargin)
+	pdf.SetAutoPageBreak(true, pageMargin+5)
+
+	r := &renderer{pdf: pdf, family: coreFamily}
+
+	if opts.FontPath != "" {
+		regular, err := os.ReadFile(opts.FontPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load booklet font: %w", err)
+		}
+		bold := regular
+		if opts.BoldFontPath != "" {
+			if bold, err = os.ReadFile(opts.BoldFontPath); err != nil {
+				return nil, fmt.Errorf("failed to load booklet bold font: %w", err)
+			}
+		}
+		pdf.AddUTF8FontFromBytes(fontFamily, "", regular)
+		pdf.AddUTF8FontFromBytes(fontFamily, "B", bold)
+		r.family = fontFamily
+		r.translate = func(s string) string { return s }
+	} else {
+		r.translate = pdf.UnicodeTranslatorFromDescriptor("")
+	}
+
+	if err := pdf.Error(); err != nil {
+		return nil, err
+	}
+
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-pageMargin)
+		r.font("", 8)
+		pdf.SetTextColor(128, 128, 128)
+		pdf.CellFormat(0, 10, fmt.Sprintf("%d", pdf.PageNo()), "", 0, "C", false, 0, "")
+	})
+
+	return r, nil
+}
+
+func (r *renderer) font(style string, size float64) {
+	r.pdf.SetFont(r.family, style, size)
+}
+
+func (r *renderer) text(height float64, value string) {
+	r.pdf.MultiCell(0, height, r.translate(value), "", "L", false)
+}
+
+func (r *renderer) sectionTitle(title string) {
+	r.pdf.SetTextColor(accentRed, accentGreen, accentBlue)
+	r.font("B", 18)
+	r.pdf.CellFormat(0, 12, r.translate(title), "B", 1, "L", false, 0, "")
+	r.pdf.Ln(4)
+	r.pdf.SetTextColor(0, 0, 0)
+}
+
+// cover renders the first page with the cover photo and trip summary
+func (r *renderer) cover(b *Booklet) {
+	pdf := r.pdf
+	pdf.AddPage()
+
+	width, _ := pdf.GetPageSize()
+	contentWidth := width - 2*pageMargin
+
+	if b.Cover != nil {
+		options := fpdf.ImageOptions{ImageType: b.Cover.Type, ReadDpi: false}
+		info := pdf.RegisterImageOptionsReader("cover", options, bytes.NewReader(b.Cover.Data))
+		if pdf.Ok() && info != nil {
+			height := contentWidth * info.Height() / info.Width()
+			if height > 140 {
+				height = 140
+			}
+			pdf.ImageOptions("cover", pageMargin, pageMargin, contentWidth, height, false, options, 0, "")
+			pdf.SetY(pageMargin + height + 10)
+		} else {
+			// A broken cover image must not fail the whole booklet
+			pdf.ClearError()
+			pdf.SetY(80)
+		}
+	} else {
+		pdf.SetY(80)
+	}
+
+	pdf.SetTextColor(accentRed, accentGreen, accentBlue)
+	r.font("B", 28)
+	r.text(12, b.Title)
+	pdf.SetTextColor(0, 0, 0)
+
+	if b.Subtitle != "" {
+		pdf.Ln(2)
+		r.font("", 14)
+		r.text(7, b.Subtitle)
+	}
+	if b.Destination != "" {
+		r.font("", 12)
+		r.text(6, b.Destination)
+	}
+	if b.Description != "" {
+		pdf.Ln(6)
+		r.font("", 11)
+		r.text(lineHeight, b.Description)
+	}
+}
+
+// itinerary renders the day-by-day plan, entries show time, title, address and notes
+func (r *renderer) itinerary(days []Day) {
+	if len(days) == 0 {
+		return
+	}
+
+	pdf := r.pdf
+	pdf.AddPage()
+	r.sectionTitle("Itinerary")
+
+	for _, day := range days {
+		pdf.SetFillColor(235, 241, 247)
+		r.font("B", 13)
+		heading := day.Heading
+		if day.Date != "" {
+			heading += "  ·  " + day.Date
+		}
+		pdf.CellFormat(0, 9, r.translate(heading), "", 1, "L", true, 0, "")
+		pdf.Ln(2)
+
+		if len(day.Entries) == 0 {
+			r.font("", 10)
+			pdf.SetTextColor(128, 128, 128)
+			r.text(lineHeight, "Nothing planned")
+			pdf.SetTextColor(0, 0, 0)
+		}
+
+		for _, entry := range day.Entries {
+			r.entry(&entry)
+		}
+		pdf.Ln(4)
+	}
+}
+
+func (r *renderer) entry(entry *Entry) {
+	pdf := r.pdf
+	left, _, right, _ := pdf.GetMargins()
+	width, _ := pdf.GetPageSize()
+
+	// Keep the title row together with at least one detail line
+	_, pageHeight := pdf.GetPageSize()
+	if pdf.GetY()+3*lineHeight > pageHeight-pageMargin-5 {
+		pdf.AddPage()
+	}
+
+	r.font("B", 10)
+	pdf.SetTextColor(accentRed, accentGreen, accentBlue)
+	pdf.CellFormat(timeColumn, lineHeight, r.translate(entry.Time), "", 0, "L", false, 0, "")
+	pdf.SetTextColor(0, 0, 0)
+
+	detailWidth := width - left - right - timeColumn
+	r.font("B", 11)
+	pdf.MultiCell(detailWidth, lineHeight, r.translate(entry.Title), "", "L", false)
+
+	r.font("", 9)
+	if entry.Address != "" {
+		pdf.SetX(left + timeColumn)
+		pdf.SetTextColor(90, 90, 90)
+		pdf.MultiCell(detailWidth, lineHeight-1, r.translate(entry.Address), "", "L", false)
+		pdf.SetTextColor(0, 0, 0)
+	}
+	if entry.Notes != "" {
+		pdf.SetX(left + timeColumn)
+		pdf.MultiCell(detailWidth, lineHeight-1, r.translate(entry.Notes), "", "L", false)
+	}
+	pdf.Ln(2)
+}
+
+// members renders the member list
+func (r *renderer) members(members []Member) {
+	if len(members) == 0 {
+		return
+	}
+
+	pdf := r.pdf
+	pdf.AddPage()
+	r.sectionTitle("Travellers")
+
+	for _, member := range members {
+		r.font("B", 11)
+		pdf.CellFormat(100, 7, r.translate(member.Name), "B", 0, "L", false, 0, "")
+		r.font("", 10)
+		pdf.CellFormat(0, 7, r.translate(capitalize(member.Role)), "B", 1, "R", false, 0, "")
+	}
+}
+
+// budget renders spending per category against the planned budget
+func (r *renderer) budget(budget *Budget) {
+	if len(budget.Categories) == 0 && budget.Planned == nil {
+		return
+	}
+
+	pdf := r.pdf
+	pdf.Ln(10)
+	r.sectionTitle("Budget")
+
+	categories := make([]string, 0, len(budget.Categories))
+	for category := range budget.Categories {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+
+	spent := 0.0
+	r.font("", 11)
+	for _, category := range categories {
+		amount := budget.Categories[category]
+		spent += amount
+		pdf.CellFormat(100, 7, r.translate(capitalize(category)), "B", 0, "L", false, 0, "")
+		pdf.CellFormat(0, 7, r.money(amount, budget.Currency), "B", 1, "R", false, 0, "")
+	}
+
+	r.font("B", 11)
+	pdf.CellFormat(100, 8, "Total spent", "", 0, "L", false, 0, "")
+	pdf.CellFormat(0, 8, r.money(spent, budget.Currency), "", 1, "R", false, 0, "")
+
+	if budget.Planned != nil {
+		r.font("", 11)
+		pdf.CellFormat(100, 7, "Planned budget", "", 0, "L", false, 0, "")
+		pdf.CellFormat(0, 7, r.money(*budget.Planned, budget.Currency), "", 1, "R", false, 0, "")
+		pdf.CellFormat(100, 7, "Remaining", "", 0, "L", false, 0, "")
+		pdf.CellFormat(0, 7, r.money(*budget.Planned-spent, budget.Currency), "", 1, "R", false, 0, "")
+	}
+}
+
+func (r *renderer) money(amount float64, currency string) string {
+	return r.translate(strings.TrimSpace(fmt.Sprintf("%s %.2f", currency, amount)))
+}
+
+func capitalize(value string) string {
+	if value == "" {
+		return value
+	}
+	return strings.ToUpper(value[:1]) + value[1:]
+}
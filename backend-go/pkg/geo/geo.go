@@ -0,0 +1,33 @@
+package geo
+
+import "math"
+
+// earthRadiusKm is the mean Earth radius used for great-circle distances
+const earthRadiusKm = 6371.0
+
+// Point is a latitude/longitude pair in degrees
+type Point struct {
+	Lat float64
+	Lng float64
+}
+
+// DistanceKm returns the straight-line (great-circle) distance between two points using the haversine formula
+func DistanceKm(a, b Point) float64 {
+	lat1 := a.Lat * math.Pi / 180
+	lat2 := b.Lat * math.Pi / 180
+	dLat := (b.Lat - a.Lat) * math.Pi / 180
+	dLng := (b.Lng - a.Lng) * math.Pi / 180
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadiusKm * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// PathKm returns the total distance of visiting the points in order
+func PathKm(points []Point) float64 {
+	total := 0.0
+	for i := 1; i < len(points); i++ {
+		total += DistanceKm(points[i-1], points[i])
+	}
+	return total
+}
@@ -0,0 +1,113 @@
+package geo
+
+// minImprovementKm ignores improvements that are only floating point noise
+const minImprovementKm = 1e-9
+
+// Stop is a position in a route, Point is nil for stops without a known location
+// Fixed stops keep their position, the others are rearranged around them.
+type Stop struct {
+	Point *Point
+	Fixed bool
+}
+
+// OptimizeOrder returns the stop indices in an order that keeps fixed stops in place
+// and shortens the travel distance through the located stops, using a nearest-neighbour
+// tour improved with 2-opt style reversals. The input order is returned when nothing shorter is found.
+func OptimizeOrder(stops []Stop) []int {
+	order := make([]int, len(stops))
+	slots := []int{}
+	for i, stop := range stops {
+		order[i] = i
+		if !stop.Fixed {
+			slots = append(slots, i)
+		}
+	}
+	if len(slots) < 2 {
+		return order
+	}
+
+	best := append([]int(nil), order...)
+	bestKm := routeKm(stops, best)
+
+	// Nearest neighbour from every possible first stop, the first free slot may have no predecessor
+	for _, seed := range slots {
+		candidate := nearestNeighbour(stops, order, slots, seed)
+		if km := routeKm(stops, candidate); km < bestKm-minImprovementKm {
+			best, bestKm = candidate, km
+		}
+	}
+
+	// 2-opt: reverse the free stops between two slots while that shortens the route
+	for improved := true; improved; {
+		improved = false
+		for i := 0; i < len(slots)-1; i++ {
+			for j := i + 1; j < len(slots); j++ {
+				reverseSlots(best, slots[i:j+1])
+				if km := routeKm(stops, best); km < bestKm-minImprovementKm {
+					bestKm = km
+					improved = true
+					continue
+				}
+				reverseSlots(best, slots[i:j+1])
+			}
+		}
+	}
+
+	return best
+}
+
+// nearestNeighbour fills the free slots in turn with the unused stop closest to the previous located stop
+func nearestNeighbour(stops []Stop, order, slots []int, seed int) []int {
+	result := append([]int(nil), order...)
+	used := map[int]bool{seed: true}
+	result[slots[0]] = seed
+
+	for _, slot := range slots[1:] {
+		prev := previousPoint(stops, result, slot)
+		choice, choiceKm := -1, 0.0
+		for _, candidate := range slots {
+			if used[candidate] {
+				continue
+			}
+			// Stops without a location or predecessor are taken in their original order
+			km := 0.0
+			if prev != nil && stops[candidate].Point != nil {
+				km = DistanceKm(*prev, *stops[candidate].Point)
+			}
+			if choice == -1 || km < choiceKm {
+				choice, choiceKm = candidate, km
+			}
+		}
+		used[choice] = true
+		result[slot] = choice
+	}
+	return result
+}
+
+// previousPoint returns the location of the closest located stop before position
+func previousPoint(stops []Stop, order []int, position int) *Point {
+	for i := position - 1; i >= 0; i-- {
+		if point := stops[order[i]].Point; point != nil {
+			return point
+		}
+	}
+	return nil
+}
+
+// reverseSlots reverses the stops at the given positions, leaving the positions in between untouched
+func reverseSlots(order []int, positions []int) {
+	for i, j := 0, len(positions)-1; i < j; i, j = i+1, j-1 {
+		order[positions[i]], order[positions[j]] = order[positions[j]], order[positions[i]]
+	}
+}
+
+// routeKm is the distance of visiting the located stops in order
+func routeKm(stops []Stop, order []int) float64 {
+	points := make([]Point, 0, len(order))
+	for _, index := range order {
+		if point := stops[index].Point; point != nil {
+			points = append(points, *point)
+		}
+	}
+	return PathKm(points)
+}
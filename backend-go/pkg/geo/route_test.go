@@ -0,0 +1,131 @@
+package geo
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+// at places stops along the equator, one degree of longitude is about 111 km
+func at(lng float64) *Point {
+	return &Point{Lat: 0, Lng: lng}
+}
+
+func TestOptimizeOrder(t *testing.T) {
+	tests := []struct {
+		name  string
+		stops []Stop
+		want  []int // nil when any order passing the properties is fine
+	}{
+		{
+			name:  "no stops",
+			stops: []Stop{},
+			want:  []int{},
+		},
+		{
+			name:  "one stop",
+			stops: []Stop{{Point: at(1)}},
+			want:  []int{0},
+		},
+		{
+			name:  "two stops keep their order",
+			stops: []Stop{{Point: at(2)}, {Point: at(1)}},
+			want:  []int{0, 1},
+		},
+		{
+			name:  "stops along a line",
+			stops: []Stop{{Point: at(0)}, {Point: at(3)}, {Point: at(1)}, {Point: at(2)}},
+		},
+		{
+			name:  "fixed start",
+			stops: []Stop{{Point: at(0), Fixed: true}, {Point: at(3)}, {Point: at(1)}, {Point: at(2)}},
+			want:  []int{0, 2, 3, 1},
+		},
+		{
+			name:  "fixed start and end",
+			stops: []Stop{{Point: at(0), Fixed: true}, {Point: at(2)}, {Point: at(1)}, {Point: at(3), Fixed: true}},
+			want:  []int{0, 2, 1, 3},
+		},
+		{
+			name:  "fixed middle stop",
+			stops: []Stop{{Point: at(4)}, {Point: at(0)}, {Point: at(2), Fixed: true}, {Point: at(1)}, {Point: at(3)}},
+		},
+		{
+			name:  "all fixed",
+			stops: []Stop{{Point: at(3), Fixed: true}, {Point: at(1), Fixed: true}, {Point: at(2), Fixed: true}},
+			want:  []int{0, 1, 2},
+		},
+		{
+			name:  "stops without a location",
+			stops: []Stop{{Point: at(0), Fixed: true}, {}, {Point: at(2)}, {}, {Point: at(1)}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := OptimizeOrder(tt.stops)
+
+			checkRouteOrder(t, tt.stops, got)
+			if tt.want != nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("order = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// The line case has a single shortest tour up to direction, it must be found
+func TestOptimizeOrderFindsShortestLine(t *testing.T) {
+	stops := []Stop{{Point: at(0)}, {Point: at(3)}, {Point: at(1)}, {Point: at(2)}}
+
+	got := OptimizeOrder(stops)
+	if km, want := routeKm(stops, got), DistanceKm(*at(0), *at(3)); km > want+1e-6 {
+		t.Errorf("route %v is %.1f km, want %.1f km", got, km, want)
+	}
+}
+
+func TestOptimizeOrderProperties(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+
+	for run := 0; run < 200; run++ {
+		stops := make([]Stop, random.Intn(9))
+		for i := range stops {
+			if random.Intn(5) > 0 {
+				stops[i].Point = &Point{Lat: random.Float64()*10 + 40, Lng: random.Float64() * 10}
+			}
+			stops[i].Fixed = random.Intn(4) == 0
+		}
+
+		checkRouteOrder(t, stops, OptimizeOrder(stops))
+	}
+}
+
+// checkRouteOrder verifies order is a permutation of the stops that keeps fixed stops
+// in place and is no longer than visiting them in their input order
+func checkRouteOrder(t *testing.T, stops []Stop, order []int) {
+	t.Helper()
+
+	if len(order) != len(stops) {
+		t.Fatalf("order %v has %d stops, want %d", order, len(order), len(stops))
+	}
+	seen := make([]bool, len(stops))
+	for position, index := range order {
+		if index < 0 || index >= len(stops) || seen[index] {
+			t.Fatalf("order %v is not a permutation", order)
+		}
+		seen[index] = true
+		if stops[index].Fixed && index != position {
+			t.Errorf("fixed stop %d moved to position %d in %v", index, position, order)
+		}
+		if stops[position].Fixed && index != position {
+			t.Errorf("position %d of fixed stop taken by %d in %v", position, index, order)
+		}
+	}
+
+	identity := make([]int, len(stops))
+	for i := range identity {
+		identity[i] = i
+	}
+	if km, inputKm := routeKm(stops, order), routeKm(stops, identity); km > inputKm+1e-9 {
+		t.Errorf("order %v is %.3f km, longer than the input order %.3f km", order, km, inputKm)
+	}
+}
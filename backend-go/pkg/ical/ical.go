@@ -0,0 +1,158 @@
+package ical
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Calendar represents an iCalendar (RFC 5545) VCALENDAR object
+type Calendar struct {
+	ProdID string
+	Name   string
+	// TimeZone is the X-WR-TIMEZONE calendar default, set by Parse
+	TimeZone string
+	// RefreshInterval hints subscribed clients how often to refetch the feed (0 = omit)
+	RefreshInterval time.Duration
+	Events          []Event
+}
+
+// Event represents a VEVENT
+// All-day events use the date of Start only, timed events are written as floating local times
+// because trips don't carry a timezone and calendar apps then show the wall-clock time as planned
+type Event struct {
+	UID         string
+	Summary     string
+	Description string
+	Location    string
+	URL         string
+	Geo         *Geo
+	Start       time.Time
+	End         time.Time
+	AllDay      bool
+	Stamp       time.Time
+
+	// Floating is set by Parse when DTSTART has neither a UTC nor a known TZID time,
+	// Start and End then hold the wall-clock time in UTC
+	Floating bool
+	// ParseError is set by Parse when a property of the event is malformed
+	ParseError error
+}
+
+// Geo represents the GEO property (latitude;longitude)
+type Geo struct {
+	Lat float64
+	Lng float64
+}
+
+const (
+	dateFormat      = "20060102"
+	localTimeFormat = "20060102T150405"
+	utcTimeFormat   = "20060102T150405Z"
+)
+
+// Marshal encodes the calendar as an RFC 5545 document with CRLF line endings and folded lines
+func (c *Calendar) Marshal() []byte {
+	var buf bytes.Buffer
+
+	writeLine(&buf, "BEGIN:VCALENDAR")
+	writeLine(&buf, "VERSION:2.0")
+	writeLine(&buf, "PRODID:"+escapeText(c.ProdID))
+	writeLine(&buf, "CALSCALE:GREGORIAN")
+	writeLine(&buf, "METHOD:PUBLISH")
+	if c.Name != "" {
+		writeLine(&buf, "X-WR-CALNAME:"+escapeText(c.Name))
+	}
+	if c.RefreshInterval > 0 {
+		duration := formatDuration(c.RefreshInterval)
+		writeLine(&buf, "REFRESH-INTERVAL;VALUE=DURATION:"+duration)
+		writeLine(&buf, "X-PUBLISHED-TTL:"+duration)
+	}
+
+	for _, event := range c.Events {
+		writeEvent(&buf, &event)
+	}
+
+	writeLine(&buf, "END:VCALENDAR")
+	return buf.Bytes()
+}
+
+func writeEvent(buf *bytes.Buffer, e *Event) {
+	writeLine(buf, "BEGIN:VEVENT")
+	writeLine(buf, "UID:"+escapeText(e.UID))
+	writeLine(buf, "DTSTAMP:"+e.Stamp.UTC().Format(utcTimeFormat))
+
+	if e.AllDay {
+		end := e.End
+		if !end.After(e.Start) {
+			end = e.Start.AddDate(0, 0, 1) // DTEND is exclusive for all-day events
+		}
+		writeLine(buf, "DTSTART;VALUE=DATE:"+e.Start.Format(dateFormat))
+		writeLine(buf, "DTEND;VALUE=DATE:"+end.Format(dateFormat))
+	} else {
+		writeLine(buf, "DTSTART:"+e.Start.Format(localTimeFormat))
+		writeLine(buf, "DTEND:"+e.End.Format(localTimeFormat))
+	}
+
+	writeLine(buf, "SUMMARY:"+escapeText(e.Summary))
+	if e.Description != "" {
+		writeLine(buf, "DESCRIPTION:"+escapeText(e.Description))
+	}
+	if e.Location != "" {
+		writeLine(buf, "LOCATION:"+escapeText(e.Location))
+	}
+	if e.Geo != nil {
+		writeLine(buf, fmt.Sprintf("GEO:%.6f;%.6f", e.Geo.Lat, e.Geo.Lng))
+	}
+	if e.URL != "" {
+		writeLine(buf, "URL:"+e.URL)
+	}
+	writeLine(buf, "END:VEVENT")
+}
+
+// writeLine writes a content line folded at 75 octets without splitting UTF-8 sequences
+func writeLine(buf *bytes.Buffer, line string) {
+	limit := 75
+
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !isRuneStart(line[cut]) {
+			cut--
+		}
+		buf.WriteString(line[:cut])
+		buf.WriteString("\r\n ")
+		line = line[cut:]
+		limit = 74 // continuation lines start with a space
+	}
+	buf.WriteString(line)
+	buf.WriteString("\r\n")
+}
+
+func isRuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
+
+var textEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	";", `\;`,
+	",", `\,`,
+	"\r\n", `\n`,
+	"\n", `\n`,
+	"\r", "",
+)
+
+// escapeText escapes a TEXT property value
+func escapeText(value string) string {
+	return textEscaper.Replace(value)
+}
+
+// formatDuration formats a duration as an RFC 5545 DURATION value (hours and minutes precision)
+func formatDuration(d time.Duration) string {
+	hours := int(d.Hours())
+	minutes := int(d.Minutes()) % 60
+	if minutes == 0 {
+		return fmt.Sprintf("PT%dH", hours)
+	}
+	return fmt.Sprintf("PT%dH%dM", hours, minutes)
+}
@@ -0,0 +1,97 @@
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMarshalParseRoundTrip(t *testing.T) {
+	stamp := time.Date(2026, 3, 1, 8, 30, 0, 0, time.UTC)
+	calendar := &Calendar{
+		ProdID: "-//Test//Trips//EN",
+		Name:   "Japan; spring, 2026",
+		Events: []Event{
+			{
+				UID:         "entry-1@test",
+				Summary:     `Sushi; sashimi, and \ more`,
+				Description: "Line one\nLine two, with a comma; and a semicolon",
+				Location:    "Tsukiji, Tokyo",
+				URL:         "https://example.com/sushi",
+				Geo:         &Geo{Lat: 35.665498, Lng: 139.770932},
+				Start:       time.Date(2026, 4, 2, 12, 0, 0, 0, time.UTC),
+				End:         time.Date(2026, 4, 2, 13, 30, 0, 0, time.UTC),
+				Stamp:       stamp,
+			},
+			{
+				UID:         "day-2@test",
+				Summary:     "Day 2",
+				Description: strings.Repeat("東京タワー ", 30),
+				Start:       time.Date(2026, 4, 3, 0, 0, 0, 0, time.UTC),
+				AllDay:      true,
+				Stamp:       stamp,
+			},
+		},
+	}
+
+	data := calendar.Marshal()
+
+	for _, line := range strings.Split(strings.TrimSuffix(string(data), "\r\n"), "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("line is %d octets, want at most 75: %q", len(line), line)
+		}
+	}
+	for _, want := range []string{
+		"X-WR-CALNAME:Japan\\; spring\\, 2026\r\n",
+		"SUMMARY:Sushi\\; sashimi\\, and \\\\ more\r\n",
+		"DTSTART:20260402T120000\r\n",
+		"DTEND:20260402T133000\r\n",
+		"DTSTART;VALUE=DATE:20260403\r\n",
+		"DTEND;VALUE=DATE:20260404\r\n",
+		"DTSTAMP:20260301T083000Z\r\n",
+	} {
+		if !bytes.Contains(data, []byte(want)) {
+			t.Errorf("output is missing %q", want)
+		}
+	}
+
+	parsed, err := Parse(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.Name != calendar.Name {
+		t.Errorf("name = %q, want %q", parsed.Name, calendar.Name)
+	}
+	if len(parsed.Events) != len(calendar.Events) {
+		t.Fatalf("got %d events, want %d", len(parsed.Events), len(calendar.Events))
+	}
+
+	timed := parsed.Events[0]
+	want := calendar.Events[0]
+	if timed.ParseError != nil {
+		t.Fatal(timed.ParseError)
+	}
+	if timed.UID != want.UID || timed.Summary != want.Summary || timed.Description != want.Description ||
+		timed.Location != want.Location || timed.URL != want.URL {
+		t.Errorf("text fields = %+v, want %+v", timed, want)
+	}
+	if timed.Geo == nil || *timed.Geo != *want.Geo {
+		t.Errorf("geo = %v, want %v", timed.Geo, want.Geo)
+	}
+	if !timed.Start.Equal(want.Start) || !timed.End.Equal(want.End) || timed.AllDay {
+		t.Errorf("times = %v-%v allDay=%v, want %v-%v", timed.Start, timed.End, timed.AllDay, want.Start, want.End)
+	}
+	if !timed.Stamp.Equal(stamp) {
+		t.Errorf("stamp = %v, want %v", timed.Stamp, stamp)
+	}
+
+	allDay := parsed.Events[1]
+	if allDay.Description != calendar.Events[1].Description {
+		t.Errorf("folded description = %q, want %q", allDay.Description, calendar.Events[1].Description)
+	}
+	if !allDay.AllDay || !allDay.Start.Equal(calendar.Events[1].Start) ||
+		!allDay.End.Equal(calendar.Events[1].Start.AddDate(0, 0, 1)) {
+		t.Errorf("all-day = %v-%v allDay=%v", allDay.Start, allDay.End, allDay.AllDay)
+	}
+}
@@ -0,0 +1,231 @@
+package ical
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrNoCalendar is returned when the input has no VCALENDAR object
+var ErrNoCalendar = errors.New("no VCALENDAR found")
+
+// property is a parsed content line: NAME;PARAM=VALUE:value
+type property struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// Parse decodes the VEVENTs of an iCalendar document
+// UTC times are returned in UTC and TZID times in their zone, callers convert them to the zone
+// they plan in. Floating and all-day values, and TZIDs unknown to the tz database, are returned
+// as wall-clock times in UTC. Components nested in an event (VALARM) are skipped.
+// Events that fail to parse are returned with ParseError set so callers can report them per row.
+func Parse(r io.Reader) (*Calendar, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+
+	calendar := &Calendar{}
+	found := false
+	var current *Event
+	nested := 0
+
+	for _, line := range lines {
+		prop, err := parseProperty(line)
+		if err != nil {
+			if current != nil && nested == 0 && current.ParseError == nil {
+				current.ParseError = err
+			}
+			continue
+		}
+
+		switch {
+		case prop.name == "BEGIN" && strings.EqualFold(prop.value, "VCALENDAR"):
+			found = true
+		case prop.name == "BEGIN" && strings.EqualFold(prop.value, "VEVENT"):
+			current = &Event{}
+			nested = 0
+		case prop.name == "END" && strings.EqualFold(prop.value, "VEVENT"):
+			if current != nil {
+				calendar.Events = append(calendar.Events, *current)
+			}
+			current = nil
+			nested = 0
+		case current != nil && prop.name == "BEGIN":
+			nested++
+		case current != nil && prop.name == "END" && nested > 0:
+			nested--
+		case current != nil:
+			if nested > 0 {
+				continue
+			}
+			if err := current.apply(prop); err != nil && current.ParseError == nil {
+				current.ParseError = err
+			}
+		case prop.name == "PRODID":
+			calendar.ProdID = prop.value
+		case prop.name == "X-WR-CALNAME":
+			calendar.Name = unescapeText(prop.value)
+		case prop.name == "X-WR-TIMEZONE":
+			calendar.TimeZone = prop.value
+		}
+	}
+
+	if !found {
+		return nil, ErrNoCalendar
+	}
+	return calendar, nil
+}
+
+func (e *Event) apply(prop *property) error {
+	switch prop.name {
+	case "UID":
+		e.UID = prop.value
+	case "SUMMARY":
+		e.Summary = unescapeText(prop.value)
+	case "DESCRIPTION":
+		e.Description = unescapeText(prop.value)
+	case "LOCATION":
+		e.Location = unescapeText(prop.value)
+	case "URL":
+		e.URL = prop.value
+	case "GEO":
+		parts := strings.Split(prop.value, ";")
+		if len(parts) != 2 {
+			return fmt.Errorf("invalid GEO value %q", prop.value)
+		}
+		lat, err1 := strconv.ParseFloat(parts[0], 64)
+		lng, err2 := strconv.ParseFloat(parts[1], 64)
+		if err1 != nil || err2 != nil {
+			return fmt.Errorf("invalid GEO value %q", prop.value)
+		}
+		e.Geo = &Geo{Lat: lat, Lng: lng}
+	case "DTSTART":
+		start, allDay, floating, err := parseDateTime(prop)
+		if err != nil {
+			return err
+		}
+		e.Start = start
+		e.AllDay = allDay
+		e.Floating = floating && !allDay
+	case "DTEND":
+		end, _, _, err := parseDateTime(prop)
+		if err != nil {
+			return err
+		}
+		e.End = end
+	case "DTSTAMP":
+		if stamp, _, _, err := parseDateTime(prop); err == nil {
+			e.Stamp = stamp
+		}
+	}
+	return nil
+}
+
+// parseDateTime parses DATE and DATE-TIME values, allDay is true for DATE values
+// floating is true when the value is a wall-clock time without a usable zone
+func parseDateTime(prop *property) (t time.Time, allDay, floating bool, err error) {
+	value := prop.value
+	invalid := fmt.Errorf("invalid %s value %q", prop.name, prop.value)
+
+	if strings.EqualFold(prop.params["VALUE"], "DATE") || len(value) == len(dateFormat) {
+		t, err := time.Parse(dateFormat, value)
+		if err != nil {
+			return time.Time{}, false, false, invalid
+		}
+		return t, true, true, nil
+	}
+
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse(utcTimeFormat, value)
+		if err != nil {
+			return time.Time{}, false, false, invalid
+		}
+		return t, false, false, nil
+	}
+
+	location := time.UTC
+	floating = true
+	if tzid := prop.params["TZID"]; tzid != "" {
+		// Some clients prefix TZIDs with "/" to mark them as globally unique
+		if loaded, err := time.LoadLocation(strings.TrimPrefix(tzid, "/")); err == nil {
+			location = loaded
+			floating = false
+		}
+	}
+
+	t, err = time.ParseInLocation(localTimeFormat, value, location)
+	if err != nil {
+		return time.Time{}, false, false, invalid
+	}
+	return t, false, floating, nil
+}
+
+// unfold joins folded content lines (continuation lines start with a space or tab)
+func unfold(r io.Reader) ([]string, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var lines []string
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line == "" {
+			continue
+		}
+		if (line[0] == ' ' || line[0] == '\t') && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return lines, nil
+}
+
+// parseProperty splits a content line into name, parameters and value
+// Quoted parameter values may contain ':' and ';'
+func parseProperty(line string) (*property, error) {
+	inQuotes := false
+	colon := -1
+	for i, ch := range line {
+		if ch == '"' {
+			inQuotes = !inQuotes
+		} else if ch == ':' && !inQuotes {
+			colon = i
+			break
+		}
+	}
+	if colon <= 0 {
+		return nil, fmt.Errorf("invalid content line %q", line)
+	}
+
+	prop := &property{params: map[string]string{}, value: line[colon+1:]}
+	parts := strings.Split(line[:colon], ";")
+	prop.name = strings.ToUpper(parts[0])
+	for _, param := range parts[1:] {
+		if key, value, ok := strings.Cut(param, "="); ok {
+			prop.params[strings.ToUpper(key)] = strings.Trim(value, `"`)
+		}
+	}
+	return prop, nil
+}
+
+var textUnescaper = strings.NewReplacer(
+	`\\`, `\`,
+	`\;`, ";",
+	`\,`, ",",
+	`\n`, "\n",
+	`\N`, "\n",
+)
+
+func unescapeText(value string) string {
+	return textUnescaper.Replace(value)
+}
@@ -0,0 +1,192 @@
+package ical
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// calendar wraps VEVENT lines in a VCALENDAR with CRLF line endings
+func calendar(lines ...string) string {
+	all := append([]string{"BEGIN:VCALENDAR", "VERSION:2.0"}, lines...)
+	all = append(all, "END:VCALENDAR")
+	return strings.Join(all, "\r\n") + "\r\n"
+}
+
+func parseOne(t *testing.T, input string) Event {
+	t.Helper()
+
+	parsed, err := Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(parsed.Events) != 1 {
+		t.Fatalf("got %d events, want 1", len(parsed.Events))
+	}
+	return parsed.Events[0]
+}
+
+func TestParseUnfoldsLines(t *testing.T) {
+	event := parseOne(t, calendar(
+		"BEGIN:VEVENT",
+		"SUMMARY:A very long summary that a client",
+		"  folded with a space",
+		"DESCRIPTION:and one",
+		"\t folded with a tab",
+		"DTSTART:20260501T090000",
+		"END:VEVENT",
+	))
+
+	if want := "A very long summary that a client folded with a space"; event.Summary != want {
+		t.Errorf("summary = %q, want %q", event.Summary, want)
+	}
+	if want := "and one folded with a tab"; event.Description != want {
+		t.Errorf("description = %q, want %q", event.Description, want)
+	}
+}
+
+func TestParseUnescapesText(t *testing.T) {
+	event := parseOne(t, calendar(
+		"BEGIN:VEVENT",
+		`SUMMARY:Fish\, chips\; peas`,
+		`DESCRIPTION:Line one\nLine two\NLine three \\ done`,
+		`LOCATION:"Quoted"\, Street`,
+		"DTSTART:20260501T090000",
+		"END:VEVENT",
+	))
+
+	if want := "Fish, chips; peas"; event.Summary != want {
+		t.Errorf("summary = %q, want %q", event.Summary, want)
+	}
+	if want := "Line one\nLine two\nLine three \\ done"; event.Description != want {
+		t.Errorf("description = %q, want %q", event.Description, want)
+	}
+	if want := `"Quoted", Street`; event.Location != want {
+		t.Errorf("location = %q, want %q", event.Location, want)
+	}
+}
+
+func TestParseDateTimes(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Skip("tz database not available:", err)
+	}
+
+	tests := []struct {
+		name     string
+		dtstart  string
+		want     time.Time
+		allDay   bool
+		floating bool
+	}{
+		{
+			name:     "all day",
+			dtstart:  "DTSTART;VALUE=DATE:20260501",
+			want:     time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC),
+			allDay:   true,
+			floating: false, // only timed events are reported floating
+		},
+		{
+			name:     "floating",
+			dtstart:  "DTSTART:20260501T093000",
+			want:     time.Date(2026, 5, 1, 9, 30, 0, 0, time.UTC),
+			floating: true,
+		},
+		{
+			name:    "utc",
+			dtstart: "DTSTART:20260501T073000Z",
+			want:    time.Date(2026, 5, 1, 7, 30, 0, 0, time.UTC),
+		},
+		{
+			name:    "tzid",
+			dtstart: "DTSTART;TZID=Europe/Paris:20260501T093000",
+			want:    time.Date(2026, 5, 1, 9, 30, 0, 0, paris),
+		},
+		{
+			name:    "quoted tzid",
+			dtstart: `DTSTART;TZID="Europe/Paris":20260501T093000`,
+			want:    time.Date(2026, 5, 1, 9, 30, 0, 0, paris),
+		},
+		{
+			name:     "unknown tzid",
+			dtstart:  "DTSTART;TZID=Romance Standard Time:20260501T093000",
+			want:     time.Date(2026, 5, 1, 9, 30, 0, 0, time.UTC),
+			floating: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := parseOne(t, calendar("BEGIN:VEVENT", "SUMMARY:Walk", tt.dtstart, "END:VEVENT"))
+
+			if event.ParseError != nil {
+				t.Fatal(event.ParseError)
+			}
+			if !event.Start.Equal(tt.want) || event.Start.Location().String() != tt.want.Location().String() {
+				t.Errorf("start = %v, want %v", event.Start, tt.want)
+			}
+			if event.AllDay != tt.allDay {
+				t.Errorf("allDay = %v, want %v", event.AllDay, tt.allDay)
+			}
+			if event.Floating != tt.floating {
+				t.Errorf("floating = %v, want %v", event.Floating, tt.floating)
+			}
+		})
+	}
+}
+
+func TestParseSkipsAlarms(t *testing.T) {
+	event := parseOne(t, calendar(
+		"BEGIN:VEVENT",
+		"SUMMARY:Flight to Lisbon",
+		"DESCRIPTION:Terminal 2",
+		"DTSTART:20260501T093000Z",
+		"BEGIN:VALARM",
+		"ACTION:DISPLAY",
+		"DESCRIPTION:Reminder",
+		"SUMMARY:Alarm",
+		"TRIGGER:-PT1H",
+		"END:VALARM",
+		"LOCATION:Airport",
+		"END:VEVENT",
+	))
+
+	if event.Summary != "Flight to Lisbon" || event.Description != "Terminal 2" {
+		t.Errorf("alarm overwrote the event: summary %q description %q", event.Summary, event.Description)
+	}
+	if event.Location != "Airport" {
+		t.Errorf("location after the alarm = %q, want Airport", event.Location)
+	}
+}
+
+func TestParseReportsMalformedEvents(t *testing.T) {
+	parsed, err := Parse(strings.NewReader(calendar(
+		"BEGIN:VEVENT",
+		"SUMMARY:Bad start",
+		"DTSTART:2026-05-01",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"SUMMARY:Good",
+		"DTSTART:20260502T100000",
+		"END:VEVENT",
+	)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(parsed.Events) != 2 {
+		t.Fatalf("got %d events, want 2", len(parsed.Events))
+	}
+	if parsed.Events[0].ParseError == nil {
+		t.Error("malformed DTSTART was not reported")
+	}
+	if parsed.Events[1].ParseError != nil {
+		t.Errorf("valid event reported %v", parsed.Events[1].ParseError)
+	}
+}
+
+func TestParseRequiresCalendar(t *testing.T) {
+	if _, err := Parse(strings.NewReader("BEGIN:VEVENT\r\nEND:VEVENT\r\n")); !errors.Is(err, ErrNoCalendar) {
+		t.Errorf("err = %v, want ErrNoCalendar", err)
+	}
+}
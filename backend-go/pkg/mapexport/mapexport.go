@@ -0,0 +1,280 @@
+package mapexport
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+)
+
+const creator = "Painaina"
+
+// Document is a set of waypoints grouped per day, in visiting order
+type Document struct {
+	Name        string
+	Description string
+	Days        []Day
+}
+
+// Day is one itinerary day with its ordered waypoints
+type Day struct {
+	Number    int
+	Title     string
+	Date      string // YYYY-MM-DD, optional
+	Waypoints []Waypoint
+}
+
+// Waypoint is a single place visited on a day
+type Waypoint struct {
+	ID          string
+	Name        string
+	Description string
+	Address     string
+	StartTime   string // HH:MM, optional
+	Lat         float64
+	Lng         float64
+}
+
+// Label returns the day display name
+func (d *Day) Label() string {
+	if d.Title != "" {
+		return d.Title
+	}
+	return fmt.Sprintf("Day %d", d.Number)
+}
+
+// ==================== GeoJSON ====================
+
+type geoJSONCollection struct {
+	Type     string           `json:"type"`
+	Name     string           `json:"name,omitempty"`
+	Features []geoJSONFeature `json:"features"`
+}
+
+type geoJSONFeature struct {
+	Type       string                 `json:"type"`
+	ID         string                 `json:"id,omitempty"`
+	Geometry   geoJSONGeometry        `json:"geometry"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+type geoJSONGeometry struct {
+	Type        string      `json:"type"`
+	Coordinates interface{} `json:"coordinates"`
+}
+
+// GeoJSON encodes the document as a FeatureCollection
+// Each waypoint is a Point feature, each day with two or more waypoints also gets a LineString route
+func (doc *Document) GeoJSON() ([]byte, error) {
+	collection := geoJSONCollection{
+		Type:     "FeatureCollection",
+		Name:     doc.Name,
+		Features: []geoJSONFeature{},
+	}
+
+	for _, day := range doc.Days {
+		route := make([][]float64, 0, len(day.Waypoints))
+
+		for i, wp := range day.Waypoints {
+			properties := map[string]interface{}{
+				"kind":  "waypoint",
+				"name":  wp.Name,
+				"day":   day.Number,
+				"order": i + 1,
+			}
+			if day.Date != "" {
+				properties["date"] = day.Date
+			}
+			if wp.Address != "" {
+				properties["address"] = wp.Address
+			}
+			if wp.Description != "" {
+				properties["description"] = wp.Description
+			}
+			if wp.StartTime != "" {
+				properties["startTime"] = wp.StartTime
+			}
+
+			collection.Features = append(collection.Features, geoJSONFeature{
+				Type: "Feature",
+				ID:   wp.ID,
+				Geometry: geoJSONGeometry{
+					Type:        "Point",
+					Coordinates: []float64{wp.Lng, wp.Lat}, // GeoJSON order is [lng, lat]
+				},
+				Properties: properties,
+			})
+			route = append(route, []float64{wp.Lng, wp.Lat})
+		}
+
+		if len(route) >= 2 {
+			collection.Features = append(collection.Features, geoJSONFeature{
+				Type: "Feature",
+				Geometry: geoJSONGeometry{
+					Type:        "LineString",
+					Coordinates: route,
+				},
+				Properties: map[string]interface{}{
+					"kind": "route",
+					"name": day.Label(),
+					"day":  day.Number,
+				},
+			})
+		}
+	}
+
+	return json.Marshal(collection)
+}
+
+// ==================== GPX ====================
+
+type gpxDocument struct {
+	XMLName  xml.Name    `xml:"gpx"`
+	Version  string      `xml:"version,attr"`
+	Creator  string      `xml:"creator,attr"`
+	XMLNS    string      `xml:"xmlns,attr"`
+	Metadata gpxMetadata `xml:"metadata"`
+	Points   []gpxPoint  `xml:"wpt"`
+	Routes   []gpxRoute  `xml:"rte"`
+}
+
+type gpxMetadata struct {
+	Name string `xml:"name,omitempty"`
+	Desc string `xml:"desc,omitempty"`
+}
+
+type gpxPoint struct {
+	Lat  float64 `xml:"lat,attr"`
+	Lon  float64 `xml:"lon,attr"`
+	Name string  `xml:"name,omitempty"`
+	Desc string  `xml:"desc,omitempty"`
+	Type string  `xml:"type,omitempty"`
+}
+
+type gpxRoute struct {
+	Name   string     `xml:"name,omitempty"`
+	Number int        `xml:"number,omitempty"`
+	Points []gpxPoint `xml:"rtept"`
+}
+
+// GPX encodes the document as GPX 1.1 with all waypoints and one route per day
+func (doc *Document) GPX() ([]byte, error) {
+	gpx := gpxDocument{
+		Version:  "1.1",
+		Creator:  creator,
+		XMLNS:    "http://www.topografix.com/GPX/1/1",
+		Metadata: gpxMetadata{Name: doc.Name, Desc: doc.Description},
+	}
+
+	for _, day := range doc.Days {
+		route := gpxRoute{Name: day.Label(), Number: day.Number}
+
+		for _, wp := range day.Waypoints {
+			point := gpxPoint{
+				Lat:  wp.Lat,
+				Lon:  wp.Lng,
+				Name: wp.Name,
+				Desc: joinNonEmpty(wp.Address, wp.Description),
+				Type: day.Label(),
+			}
+			gpx.Points = append(gpx.Points, point)
+			route.Points = append(route.Points, gpxPoint{Lat: wp.Lat, Lon: wp.Lng, Name: wp.Name})
+		}
+
+		if len(route.Points) > 0 {
+			gpx.Routes = append(gpx.Routes, route)
+		}
+	}
+
+	return marshalXML(gpx)
+}
+
+// ==================== KML ====================
+
+type kmlDocument struct {
+	XMLName  xml.Name     `xml:"kml"`
+	XMLNS    string       `xml:"xmlns,attr"`
+	Document kmlContainer `xml:"Document"`
+}
+
+type kmlContainer struct {
+	Name        string         `xml:"name,omitempty"`
+	Description string         `xml:"description,omitempty"`
+	Folders     []kmlContainer `xml:"Folder,omitempty"`
+	Placemarks  []kmlPlacemark `xml:"Placemark,omitempty"`
+}
+
+type kmlPlacemark struct {
+	Name        string       `xml:"name,omitempty"`
+	Address     string       `xml:"address,omitempty"`
+	Description string       `xml:"description,omitempty"`
+	Point       *kmlGeometry `xml:"Point,omitempty"`
+	LineString  *kmlGeometry `xml:"LineString,omitempty"`
+}
+
+type kmlGeometry struct {
+	Tessellate  int    `xml:"tessellate,omitempty"`
+	Coordinates string `xml:"coordinates"`
+}
+
+// KML encodes the document as KML 2.2 with a folder per day
+func (doc *Document) KML() ([]byte, error) {
+	kml := kmlDocument{
+		XMLNS: "http://www.opengis.net/kml/2.2",
+		Document: kmlContainer{
+			Name:        doc.Name,
+			Description: doc.Description,
+		},
+	}
+
+	for _, day := range doc.Days {
+		folder := kmlContainer{Name: day.Label(), Description: day.Date}
+		route := ""
+
+		for _, wp := range day.Waypoints {
+			coordinates := fmt.Sprintf("%f,%f", wp.Lng, wp.Lat)
+			folder.Placemarks = append(folder.Placemarks, kmlPlacemark{
+				Name:        wp.Name,
+				Address:     wp.Address,
+				Description: wp.Description,
+				Point:       &kmlGeometry{Coordinates: coordinates},
+			})
+			if route != "" {
+				route += " "
+			}
+			route += coordinates
+		}
+
+		if len(day.Waypoints) >= 2 {
+			folder.Placemarks = append(folder.Placemarks, kmlPlacemark{
+				Name:       day.Label() + " route",
+				LineString: &kmlGeometry{Tessellate: 1, Coordinates: route},
+			})
+		}
+
+		kml.Document.Folders = append(kml.Document.Folders, folder)
+	}
+
+	return marshalXML(kml)
+}
+
+func marshalXML(v interface{}) ([]byte, error) {
+	data, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), data...), nil
+}
+
+func joinNonEmpty(values ...string) string {
+	result := ""
+	for _, value := range values {
+		if value == "" {
+			continue
+		}
+		if result != "" {
+			result += "\n"
+		}
+		result += value
+	}
+	return result
+}
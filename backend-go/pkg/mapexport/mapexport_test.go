@@ -0,0 +1,122 @@
+package mapexport
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// testDocument covers escaping in names and descriptions, a day with a single waypoint (no route)
+// and a day with no waypoints at all, which is what a day whose entries lack coordinates becomes
+func testDocument() *Document {
+	return &Document{
+		Name:        `Tom & Jerry's "Road" <Trip>`,
+		Description: "Coast to coast",
+		Days: []Day{
+			{
+				Number: 1,
+				Date:   "2026-05-01",
+				Waypoints: []Waypoint{
+					{
+						ID:          "wp1",
+						Name:        "Café <Paris> & Co",
+						Description: `Try the "croque"`,
+						Address:     "1 Rue de Rivoli",
+						StartTime:   "09:30",
+						Lat:         48.8566,
+						Lng:         2.3522,
+					},
+					{
+						ID:   "wp2",
+						Name: "Louvre",
+						Lat:  48.8606,
+						Lng:  2.3376,
+					},
+				},
+			},
+			{
+				Number: 2,
+				Title:  "Lyon & around",
+				Waypoints: []Waypoint{
+					{ID: "wp3", Name: "Fourvière", Lat: 45.7623, Lng: 4.8222},
+				},
+			},
+			{
+				Number: 3,
+			},
+		},
+	}
+}
+
+func TestGeoJSON(t *testing.T) {
+	data, err := testDocument().GeoJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, data, "", "  "); err != nil {
+		t.Fatal(err)
+	}
+	indented.WriteByte('\n')
+	assertGolden(t, "document.geojson", indented.Bytes())
+}
+
+func TestGPX(t *testing.T) {
+	data, err := testDocument().GPX()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertGolden(t, "document.gpx", append(data, '\n'))
+}
+
+func TestKML(t *testing.T) {
+	data, err := testDocument().KML()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertGolden(t, "document.kml", append(data, '\n'))
+}
+
+func TestEmptyDocument(t *testing.T) {
+	doc := &Document{Name: "Empty"}
+
+	data, err := doc.GeoJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"type":"FeatureCollection","name":"Empty","features":[]}`; string(data) != want {
+		t.Errorf("GeoJSON = %s, want %s", data, want)
+	}
+
+	if _, err := doc.GPX(); err != nil {
+		t.Errorf("GPX: %v", err)
+	}
+	if _, err := doc.KML(); err != nil {
+		t.Errorf("KML: %v", err)
+	}
+}
+
+func assertGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("%s mismatch (run go test -update to accept)\ngot:\n%s\nwant:\n%s", name, got, want)
+	}
+}
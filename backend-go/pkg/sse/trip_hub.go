@@ -0,0 +1,137 @@
+package sse
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// Trip event types, named <resource>.<change>
+const (
+	TripEventDayCreated       = "day.created"
+	TripEventDayUpdated       = "day.updated"
+	TripEventDayDeleted       = "day.deleted"
+	TripEventDaysChanged      = "days.changed" // Several days were renumbered or re-dated, data holds every day
+	TripEventEntryCreated     = "entry.created"
+	TripEventEntryUpdated     = "entry.updated"
+	TripEventEntryDeleted     = "entry.deleted"
+	TripEventEntriesReordered = "entries.reordered"
+	TripEventTodosUpdated     = "todos.updated"
+	TripEventExpenseCreated   = "expense.created"
+	TripEventExpenseUpdated   = "expense.updated"
+	TripEventExpenseDeleted   = "expense.deleted"
+	TripEventDocumentAdded    = "document.added"
+	TripEventDocumentUpdated  = "document.updated"
+	TripEventDocumentRemoved  = "document.removed"
+	TripEventMemberAdded      = "member.added"
+	TripEventMemberUpdated    = "member.updated"
+	TripEventMemberRemoved    = "member.removed"
+)
+
+// TripEvent is a change to a trip, Data carries the changed resource so clients can patch their state
+type TripEvent struct {
+	Type      string      `json:"type"`
+	TripID    string      `json:"tripId"`
+	Timestamp int64       `json:"timestamp"`
+	Data      interface{} `json:"data,omitempty"`
+
+	// CloseFor ends the subscriptions of this user after the event is delivered, e.g. when they leave the trip
+	CloseFor string `json:"-"`
+}
+
+// TripClient is a connection subscribed to one trip
+type TripClient struct {
+	TripID  string
+	UserID  string
+	Channel chan TripEvent
+}
+
+// TripHub manages SSE connections subscribed to trips
+type TripHub struct {
+	// Map of tripID to subscribed connections
+	clients map[string][]*TripClient
+	mu      sync.RWMutex
+}
+
+// NewTripHub creates a new trip SSE hub
+func NewTripHub() *TripHub {
+	return &TripHub{
+		clients: make(map[string][]*TripClient),
+	}
+}
+
+// Register subscribes a user's connection to a trip
+func (h *TripHub) Register(tripID, userID string, ch chan TripEvent) *TripClient {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	client := &TripClient{
+		TripID:  tripID,
+		UserID:  userID,
+		Channel: ch,
+	}
+
+	h.clients[tripID] = append(h.clients[tripID], client)
+	return client
+}
+
+// Unregister removes a trip subscription
+func (h *TripHub) Unregister(client *TripClient) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	clients := h.clients[client.TripID]
+	for i, c := range clients {
+		if c == client {
+			h.clients[client.TripID] = append(clients[:i], clients[i+1:]...)
+			break
+		}
+	}
+
+	if len(h.clients[client.TripID]) == 0 {
+		delete(h.clients, client.TripID)
+	}
+
+	close(client.Channel)
+}
+
+// Publish sends an event to every connection subscribed to the event's trip
+func (h *TripHub) Publish(event TripEvent) {
+	if event.Timestamp == 0 {
+		event.Timestamp = time.Now().UnixMilli()
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for _, client := range h.clients[event.TripID] {
+		select {
+		case client.Channel <- event:
+		default:
+			// Channel is full or closed, skip
+		}
+	}
+}
+
+// GetConnectionCount returns total number of trip subscriptions
+func (h *TripHub) GetConnectionCount() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	count := 0
+	for _, clients := range h.clients {
+		count += len(clients)
+	}
+	return count
+}
+
+// FormatTripEvent formats a trip event for SSE protocol, the event name is the event type
+func FormatTripEvent(event TripEvent) ([]byte, error) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+
+	result := "event: " + event.Type + "\ndata: " + string(data) + "\n\n"
+	return []byte(result), nil
+}